@@ -0,0 +1,178 @@
+package devicehive
+
+import (
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"github.com/devicehive/devicehive-go/devicehive/record"
+	"github.com/devicehive/devicehive-go/devicehive/ws"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Websocket cassette with out of order notification responses
+const testWsAsyncCassette = `
+{"kind":"ws","dir":"dial","url":"ws://localhost/device"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"notification/insert\",\"requestId\":1,\"deviceId\":\"dev-1\",\"notification\":{\"notification\":\"n1\"}}"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"notification/insert\",\"requestId\":2,\"deviceId\":\"dev-1\",\"notification\":{\"notification\":\"n2\"}}"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"notification/insert\",\"requestId\":3,\"deviceId\":\"dev-1\",\"notification\":{\"notification\":\"n3\"}}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"notification/insert\",\"requestId\":3,\"status\":\"success\",\"notification\":{\"id\":103}}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"notification/insert\",\"requestId\":1,\"status\":\"success\",\"notification\":{\"id\":101}}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"notification/insert\",\"requestId\":2,\"status\":\"error\",\"code\":403,\"error\":\"Forbidden\"}"}
+`
+
+// Test Websocket asynchronous requests are completed in submission order
+func TestWebsocketAsyncOrder(t *testing.T) {
+	testWebsocketAsyncOrder(t, true, []int{0, 1, 2, 3})
+}
+
+// Test Websocket asynchronous requests are completed as soon as responses arrive
+func TestWebsocketAsyncUnordered(t *testing.T) {
+	// validation error first, then in response order
+	testWebsocketAsyncOrder(t, false, []int{2, 3, 0, 1})
+}
+
+// check completion order of asynchronous requests
+func testWebsocketAsyncOrder(t *testing.T, ordered bool, expected []int) {
+	player, err := record.NewPlayer(strings.NewReader(testWsAsyncCassette))
+	if err != nil {
+		t.Fatalf("Failed to load cassette (error: %s)", err)
+	}
+
+	service, err := ws.NewServiceDial("ws://localhost", "", player.Dial())
+	if err != nil {
+		t.Fatalf("Failed to create WS service (error: %s)", err)
+	}
+	service.SetAsyncOrdered(ordered)
+
+	device := core.NewDevice("dev-1", "name", nil)
+	notifications := []*core.Notification{
+		core.NewNotification("n1", nil),
+		core.NewNotification("n2", nil),
+		core.NewNotification("", nil), // client-side validation error
+		core.NewNotification("n3", nil),
+	}
+
+	order := make(chan int, len(notifications))
+	futures := make([]*ws.Future, len(notifications))
+	for i, n := range notifications {
+		i := i
+		futures[i] = service.InsertNotificationAsync(device, n, func(err error) {
+			order <- i
+		})
+	}
+
+	for i, f := range futures {
+		err = f.Wait(testWaitTimeout)
+		if (err != nil) != (i == 1 || i == 2) {
+			t.Errorf("Unexpected #%d error: %v", i, err)
+		}
+	}
+	if notifications[0].Id != 101 || notifications[3].Id != 103 {
+		t.Errorf("Notifications are not updated: %s, %s", notifications[0], notifications[3])
+	}
+
+	for _, i := range expected {
+		if k := <-order; k != i {
+			t.Errorf("Unexpected completion order: #%d instead of #%d", k, i)
+		}
+	}
+}
+
+// Websocket cassette with a lost response
+const testWsAsyncWindowCassette = `
+{"kind":"ws","dir":"dial","url":"ws://localhost/device"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"command/update\",\"requestId\":1,\"deviceId\":\"dev-1\",\"commandId\":1,\"command\":{\"status\":\"Done\"}}"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"command/update\",\"requestId\":2,\"deviceId\":\"dev-1\",\"commandId\":1,\"command\":{\"status\":\"Done\"}}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"command/update\",\"requestId\":2,\"status\":\"success\"}"}
+`
+
+// Test Websocket in-flight window and asynchronous timeout
+func TestWebsocketAsyncWindow(t *testing.T) {
+	player, err := record.NewPlayer(strings.NewReader(testWsAsyncWindowCassette))
+	if err != nil {
+		t.Fatalf("Failed to load cassette (error: %s)", err)
+	}
+
+	service, err := ws.NewServiceDial("ws://localhost", "", player.Dial())
+	if err != nil {
+		t.Fatalf("Failed to create WS service (error: %s)", err)
+	}
+	service.SetMaxInFlight(1)
+	service.SetAsyncTimeout(100 * time.Millisecond)
+
+	device := core.NewDevice("dev-1", "name", nil)
+	command := &core.Command{Id: 1, Status: "Done"}
+	first := service.UpdateCommandAsync(device, command, nil)
+
+	submitted := make(chan *ws.Future)
+	go func() {
+		submitted <- service.UpdateCommandAsync(device, command, nil)
+	}()
+
+	select {
+	case <-submitted:
+		t.Fatalf("In-flight window is not respected")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err = first.Wait(testWaitTimeout); err == nil || err.Error() != "timed out" {
+		t.Errorf("Timeout error expected, got %v", err)
+	}
+	second := <-submitted
+	if err = second.Wait(testWaitTimeout); err != nil {
+		t.Errorf("Failed to update command (error: %s)", err)
+	}
+}
+
+// Websocket cassette with a late response
+const testWsPendingCassette = `
+{"kind":"ws","dir":"dial","url":"ws://localhost/device"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"device/delete\",\"requestId\":1,\"deviceId\":\"dev-1\"}"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"device/delete\",\"requestId\":2,\"deviceId\":\"dev-1\"}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"device/delete\",\"requestId\":1,\"status\":\"success\"}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"device/delete\",\"requestId\":2,\"status\":\"success\"}"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"command/update\",\"requestId\":3,\"deviceId\":\"dev-1\",\"commandId\":1,\"command\":{}}"}
+`
+
+// Test Websocket abandoned requests are removed and late responses are discarded
+func TestWebsocketPendingCleanup(t *testing.T) {
+	player, err := record.NewPlayer(strings.NewReader(testWsPendingCassette))
+	if err != nil {
+		t.Fatalf("Failed to load cassette (error: %s)", err)
+	}
+
+	service, err := ws.NewServiceDial("ws://localhost", "", player.Dial())
+	if err != nil {
+		t.Fatalf("Failed to create WS service (error: %s)", err)
+	}
+
+	device := core.NewDevice("dev-1", "name", nil)
+	if err = service.DeleteDevice(device, 50*time.Millisecond); err == nil {
+		t.Errorf("Timeout error expected")
+	}
+	if n := service.Pending(); n != 0 {
+		t.Errorf("Timed out request is still pending: %d", n)
+	}
+
+	// late response should not block the next one
+	if err = service.DeleteDevice(device, testWaitTimeout); err != nil {
+		t.Errorf("Failed to delete device (error: %s)", err)
+	}
+
+	service.SetMaxPending(1)
+	future := service.UpdateCommandAsync(device, &core.Command{Id: 1}, nil)
+	if err = service.DeleteDevice(device, testWaitTimeout); err != ws.ErrTooManyPending {
+		t.Errorf("Too many pending error expected, got %v", err)
+	}
+	if n := service.Pending(); n != 1 {
+		t.Errorf("Unexpected number of pending requests: %d", n)
+	}
+
+	future.Cancel()
+	if err = future.Wait(testWaitTimeout); err != ws.ErrCanceled {
+		t.Errorf("Canceled error expected, got %v", err)
+	}
+	if n := service.Pending(); n != 0 {
+		t.Errorf("Canceled request is still pending: %d", n)
+	}
+}
@@ -0,0 +1,178 @@
+package devicehive
+
+import (
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Default number of concurrent requests of bulk operations.
+	DefaultBulkConcurrency = 8
+
+	// Default timeout of a single bulk operation item.
+	DefaultBulkTimeout = 30 * time.Second
+)
+
+// Bulk operation options. Zero value gives defaults.
+type BulkOptions struct {
+	// Maximum number of concurrent requests.
+	// Zero means DefaultBulkConcurrency.
+	Concurrency int
+
+	// Maximum number of requests started per second.
+	// Zero means no rate limit.
+	Rate float64
+
+	// Timeout of a single item. Zero means DefaultBulkTimeout.
+	Timeout time.Duration
+
+	// Progress callback [optional], called once per finished item.
+	// Calls are serialized, done is the number of finished items.
+	Progress func(done, total int, result *BulkResult)
+}
+
+// Result of a single bulk operation item.
+type BulkResult struct {
+	// Index of the item in the input list.
+	Index int
+
+	// Time spent on the item.
+	Duration time.Duration
+
+	// Item error, nil if succeeded.
+	Err error
+}
+
+// Aggregated error of a bulk operation.
+type BulkError struct {
+	// Operation name.
+	Op string
+
+	// Total number of items.
+	Total int
+
+	// Failed items, sorted by index.
+	Failed []*BulkResult
+}
+
+// Get error string representation
+func (err *BulkError) Error() string {
+	reasons := make([]string, 0, len(err.Failed))
+	for i, r := range err.Failed {
+		if i == 3 {
+			reasons = append(reasons, "...")
+			break
+		}
+		reasons = append(reasons, fmt.Sprintf("#%d: %s", r.Index, r.Err))
+	}
+	return fmt.Sprintf("%s: %d of %d failed (%s)", err.Op,
+		len(err.Failed), err.Total, strings.Join(reasons, ", "))
+}
+
+// Unwrap() gets all item errors, for errors.Is and errors.As.
+func (err *BulkError) Unwrap() []error {
+	errs := make([]error, 0, len(err.Failed))
+	for _, r := range err.Failed {
+		errs = append(errs, r.Err)
+	}
+	return errs
+}
+
+// run bulk operation on total items
+// returns per-item results and aggregated error
+func runBulk(op string, total int, options *BulkOptions, do func(index int, timeout time.Duration) error) ([]BulkResult, error) {
+	opts := BulkOptions{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultBulkConcurrency
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultBulkTimeout
+	}
+
+	// rate limiter
+	var ticker *time.Ticker
+	if opts.Rate > 0 {
+		ticker = time.NewTicker(time.Duration(float64(time.Second) / opts.Rate))
+		defer ticker.Stop()
+	}
+
+	results := make([]BulkResult, total)
+	var lock sync.Mutex // protects progress
+	done := 0
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, opts.Concurrency)
+	for i := 0; i < total; i++ {
+		if ticker != nil && i != 0 {
+			<-ticker.C
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() { <-sem; wg.Done() }()
+
+			start := time.Now()
+			err := do(i, opts.Timeout)
+			results[i] = BulkResult{Index: i, Duration: time.Since(start), Err: err}
+
+			lock.Lock()
+			defer lock.Unlock()
+			done++
+			if opts.Progress != nil {
+				opts.Progress(done, total, &results[i])
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var failed []*BulkResult
+	for i := range results {
+		if results[i].Err != nil {
+			failed = append(failed, &results[i])
+		}
+	}
+	if len(failed) != 0 {
+		return results, &BulkError{Op: op, Total: total, Failed: failed}
+	}
+	return results, nil
+}
+
+// RegisterDevices registers the devices concurrently.
+// Per-item results are returned in the input order, error is *BulkError.
+func RegisterDevices(service Service, devices []*core.Device, options *BulkOptions) ([]BulkResult, error) {
+	return runBulk("register devices", len(devices), options, func(i int, timeout time.Duration) error {
+		return service.RegisterDevice(devices[i], timeout)
+	})
+}
+
+// DeleteDevices deletes the devices concurrently.
+// Per-item results are returned in the input order, error is *BulkError.
+func DeleteDevices(service Service, devices []*core.Device, options *BulkOptions) ([]BulkResult, error) {
+	return runBulk("delete devices", len(devices), options, func(i int, timeout time.Duration) error {
+		return service.DeleteDevice(devices[i], timeout)
+	})
+}
+
+// InsertNotifications inserts the device notifications concurrently.
+// Notifications might be inserted out of order.
+// Per-item results are returned in the input order, error is *BulkError.
+func InsertNotifications(service Service, device *core.Device, notifications []*core.Notification, options *BulkOptions) ([]BulkResult, error) {
+	return runBulk("insert notifications", len(notifications), options, func(i int, timeout time.Duration) error {
+		return service.InsertNotification(device, notifications[i], timeout)
+	})
+}
+
+// InsertCommands inserts the device commands concurrently.
+// Commands might be inserted out of order.
+// Per-item results are returned in the input order, error is *BulkError.
+func InsertCommands(service CommandService, device *core.Device, commands []*core.Command, options *BulkOptions) ([]BulkResult, error) {
+	return runBulk("insert commands", len(commands), options, func(i int, timeout time.Duration) error {
+		return service.InsertCommand(device, commands[i], timeout)
+	})
+}
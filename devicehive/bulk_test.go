@@ -0,0 +1,109 @@
+package devicehive
+
+import (
+	"errors"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"github.com/devicehive/devicehive-go/devicehive/rest"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Test bulk device registration with bounded concurrency
+func TestBulkRegisterDevices(t *testing.T) {
+	var lock sync.Mutex
+	active, maxActive := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		active++
+		if active > maxActive {
+			maxActive = active
+		}
+		lock.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		lock.Lock()
+		active--
+		lock.Unlock()
+
+		if strings.HasSuffix(r.URL.Path, "-bad") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	service, err := rest.NewService(server.URL, "")
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	devices := make([]*core.Device, 20)
+	for i := range devices {
+		devices[i] = core.NewDevice(fmt.Sprintf("dev-%d", i), "name", nil)
+	}
+	devices[7].Id += "-bad"
+	devices[13].Name = "" // client-side validation error
+
+	progress := 0
+	results, err := RegisterDevices(service, devices, &BulkOptions{Concurrency: 3,
+		Progress: func(done, total int, result *BulkResult) {
+			if progress++; done != progress || total != len(devices) {
+				t.Errorf("unexpected progress %d/%d", done, total)
+			}
+		}})
+
+	var bulkErr *BulkError
+	if !errors.As(err, &bulkErr) || len(bulkErr.Failed) != 2 ||
+		bulkErr.Failed[0].Index != 7 || bulkErr.Failed[1].Index != 13 {
+		t.Fatalf("unexpected bulk error: %v", err)
+	}
+	var validationErr *core.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("validation error is not unwrapped from %v", err)
+	}
+	if len(results) != len(devices) || results[0].Err != nil || results[7].Err == nil {
+		t.Errorf("unexpected results %v", results)
+	}
+	if progress != len(devices) {
+		t.Errorf("progress is called %d times", progress)
+	}
+	if maxActive > 3 || maxActive < 2 {
+		t.Errorf("unexpected number of concurrent requests: %d", maxActive)
+	}
+}
+
+// Test bulk operation rate limit
+func TestBulkRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	service, err := rest.NewService(server.URL, "")
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	device := core.NewDevice("dev-1", "name", nil)
+	notifications := make([]*core.Notification, 6)
+	for i := range notifications {
+		notifications[i] = core.NewNotification("bulk", i)
+	}
+
+	start := time.Now()
+	_, err = InsertNotifications(service, device, notifications, &BulkOptions{Rate: 100})
+	if err != nil {
+		t.Errorf("failed to insert notifications (error: %s)", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("rate limit is not respected, elapsed %s", elapsed)
+	}
+}
@@ -0,0 +1,198 @@
+// Fault injection for chaos testing.
+//
+// This package provides wrappers for both transports:
+// an http.RoundTripper for REST service and a Websocket connection
+// wrapper for Websocket service. Both of them use an Injector
+// which decides what to do with each message: add latency, drop,
+// duplicate, reply with scripted HTTP status or abruptly close
+// the connection. Faults are taken from a fixed schedule first
+// and then chosen randomly according to configured probabilities.
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var (
+	// Error returned when message is dropped.
+	ErrDropped = errors.New("chaos: message dropped")
+
+	// Error returned when connection is abruptly closed.
+	ErrClosed = errors.New("chaos: connection closed")
+)
+
+// Fault kind.
+type Kind int
+
+const (
+	// Message is passed as is (latency might be added).
+	None Kind = iota
+
+	// Message is dropped.
+	Drop
+
+	// Message is sent/received twice.
+	Duplicate
+
+	// Scripted HTTP status is returned (REST only).
+	Status
+
+	// Connection is abruptly closed.
+	Close
+)
+
+// convert fault kind to string
+// return "UNKNOWN" for unknown kinds
+func (kind Kind) String() string {
+	switch kind {
+	case None:
+		return "None"
+	case Drop:
+		return "Drop"
+	case Duplicate:
+		return "Duplicate"
+	case Status:
+		return "Status"
+	case Close:
+		return "Close"
+	}
+
+	return "UNKNOWN" // by default
+}
+
+// Single fault to inject.
+type Fault struct {
+	// Fault kind.
+	Kind Kind
+
+	// Additional latency before message is processed.
+	Latency time.Duration
+
+	// HTTP status code for Status fault.
+	StatusCode int
+}
+
+// Get Fault string representation
+func (fault Fault) String() string {
+	body := fmt.Sprintf("Kind:%s", fault.Kind)
+
+	// Latency [optional]
+	if fault.Latency != 0 {
+		body += fmt.Sprintf(", Latency:%s", fault.Latency)
+	}
+
+	// StatusCode [optional]
+	if fault.StatusCode != 0 {
+		body += fmt.Sprintf(", StatusCode:%d", fault.StatusCode)
+	}
+
+	return fmt.Sprintf("Fault{%s}", body)
+}
+
+// Fault injector configuration.
+type Config struct {
+	// Fixed schedule: i-th message gets i-th fault.
+	// Probabilities are used when schedule is over.
+	Schedule []Fault
+
+	// Latency added to each message.
+	Latency time.Duration
+
+	// Random latency [0..Jitter) added to each message.
+	Jitter time.Duration
+
+	// Probabilities of each fault kind, [0..1].
+	DropRate      float64
+	DuplicateRate float64
+	StatusRate    float64
+	CloseRate     float64
+
+	// HTTP status code used for random Status faults.
+	// 503 Service Unavailable by default.
+	StatusCode int
+
+	// Time to wait before dropped HTTP request fails.
+	// A dropped request fails immediately by default.
+	DropTimeout time.Duration
+
+	// Random generator seed. The same seed gives the same fault sequence.
+	Seed int64
+}
+
+// Fault injector.
+// It's safe to share the same injector between several transports.
+type Injector struct {
+	config Config
+
+	lock  sync.Mutex
+	rand  *rand.Rand
+	count int
+	stats map[Kind]int
+}
+
+// NewInjector creates a new fault injector.
+func NewInjector(config Config) *Injector {
+	if config.StatusCode == 0 {
+		config.StatusCode = 503
+	}
+
+	return &Injector{config: config,
+		rand:  rand.New(rand.NewSource(config.Seed)),
+		stats: make(map[Kind]int)}
+}
+
+// Next() decides what to do with the next message.
+func (injector *Injector) Next() (fault Fault) {
+	injector.lock.Lock()
+	defer injector.lock.Unlock()
+
+	config := &injector.config
+	if injector.count < len(config.Schedule) {
+		fault = config.Schedule[injector.count]
+	} else {
+		x := injector.rand.Float64()
+		switch {
+		case x < config.CloseRate:
+			fault.Kind = Close
+		case x < config.CloseRate+config.DropRate:
+			fault.Kind = Drop
+		case x < config.CloseRate+config.DropRate+config.DuplicateRate:
+			fault.Kind = Duplicate
+		case x < config.CloseRate+config.DropRate+config.DuplicateRate+config.StatusRate:
+			fault.Kind = Status
+		}
+	}
+	injector.count += 1
+
+	// default status code, for scheduled faults too
+	if fault.Kind == Status && fault.StatusCode == 0 {
+		fault.StatusCode = config.StatusCode
+	}
+
+	// latency
+	fault.Latency += config.Latency
+	if config.Jitter > 0 {
+		fault.Latency += time.Duration(injector.rand.Int63n(int64(config.Jitter)))
+	}
+
+	injector.stats[fault.Kind] += 1
+	return
+}
+
+// Count() returns number of injected faults of the given kind.
+func (injector *Injector) Count(kind Kind) int {
+	injector.lock.Lock()
+	defer injector.lock.Unlock()
+	return injector.stats[kind]
+}
+
+// Total() returns number of processed messages.
+func (injector *Injector) Total() int {
+	injector.lock.Lock()
+	defer injector.lock.Unlock()
+	return injector.count
+}
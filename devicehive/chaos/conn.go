@@ -0,0 +1,119 @@
+package chaos
+
+import (
+	"github.com/devicehive/devicehive-go/devicehive/log"
+	"github.com/devicehive/devicehive-go/devicehive/ws"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Fault injecting Websocket connection.
+// Status faults are ignored.
+type Conn struct {
+	conn ws.Conn
+
+	// Injectors for transmitted and received messages [optional].
+	tx *Injector
+	rx *Injector
+
+	// duplicated message to be received again
+	dupType int
+	dupData []byte
+	dupOk   bool
+
+	closeOnce sync.Once
+}
+
+// NewConn wraps a Websocket connection.
+// Any of TX/RX injectors might be nil, no faults are injected then.
+func NewConn(conn ws.Conn, tx, rx *Injector) *Conn {
+	return &Conn{conn: conn, tx: tx, rx: rx}
+}
+
+// Dial wraps a Websocket dial function,
+// so each new connection will inject faults.
+func Dial(dial ws.DialFunc, tx, rx *Injector) ws.DialFunc {
+	return func(url string, headers http.Header) (ws.Conn, error) {
+		conn, err := dial(url, headers)
+		if err != nil {
+			return nil, err
+		}
+		return NewConn(conn, tx, rx), nil
+	}
+}
+
+// get the next fault, apply latency
+func nextFault(injector *Injector, dir string) Fault {
+	if injector == nil {
+		return Fault{}
+	}
+
+	fault := injector.Next()
+	if fault.Kind != None {
+		log.Debugf("CHAOS: %s for %s message", fault, dir)
+	}
+	if fault.Latency > 0 {
+		time.Sleep(fault.Latency)
+	}
+	return fault
+}
+
+// abruptly close underlying connection
+func (conn *Conn) abort() error {
+	conn.closeOnce.Do(func() { conn.conn.Close() })
+	return ErrClosed
+}
+
+// WriteMessage() sends message with faults injected.
+func (conn *Conn) WriteMessage(messageType int, data []byte) error {
+	switch nextFault(conn.tx, "TX").Kind {
+	case Drop:
+		return nil // pretend message is sent
+
+	case Duplicate:
+		err := conn.conn.WriteMessage(messageType, data)
+		if err != nil {
+			return err
+		}
+
+	case Close:
+		return conn.abort()
+	}
+
+	return conn.conn.WriteMessage(messageType, data)
+}
+
+// ReadMessage() receives message with faults injected.
+func (conn *Conn) ReadMessage() (messageType int, data []byte, err error) {
+	if conn.dupOk {
+		conn.dupOk = false
+		return conn.dupType, conn.dupData, nil
+	}
+
+	for {
+		messageType, data, err = conn.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		switch nextFault(conn.rx, "RX").Kind {
+		case Drop:
+			continue // read next message
+
+		case Duplicate:
+			conn.dupType, conn.dupData, conn.dupOk = messageType, data, true
+
+		case Close:
+			return 0, nil, conn.abort()
+		}
+
+		return
+	}
+}
+
+// Close() closes underlying connection.
+func (conn *Conn) Close() (err error) {
+	conn.closeOnce.Do(func() { err = conn.conn.Close() })
+	return
+}
@@ -0,0 +1,127 @@
+package chaos
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/log"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Fault injecting HTTP transport.
+type Transport struct {
+	// Underlying transport, http.DefaultTransport if nil.
+	Base http.RoundTripper
+
+	// Fault injector.
+	Injector *Injector
+}
+
+// NewTransport creates a new fault injecting HTTP transport.
+// Base transport might be nil, http.DefaultTransport is used then.
+func NewTransport(base http.RoundTripper, injector *Injector) *Transport {
+	return &Transport{Base: base, Injector: injector}
+}
+
+// get underlying transport
+func (transport *Transport) base() http.RoundTripper {
+	if transport.Base != nil {
+		return transport.Base
+	}
+	return http.DefaultTransport
+}
+
+// RoundTrip() implements http.RoundTripper interface.
+func (transport *Transport) RoundTrip(request *http.Request) (response *http.Response, err error) {
+	fault := transport.Injector.Next()
+	if fault.Kind != None {
+		log.Debugf("CHAOS: %s for %s %s", fault, request.Method, request.URL)
+	}
+
+	// latency
+	if fault.Latency > 0 {
+		select {
+		case <-time.After(fault.Latency):
+		case <-request.Context().Done():
+			closeBody(request)
+			return nil, request.Context().Err()
+		}
+	}
+
+	switch fault.Kind {
+	case Drop:
+		if timeout := transport.Injector.config.DropTimeout; timeout > 0 {
+			select {
+			case <-time.After(timeout):
+			case <-request.Context().Done():
+			}
+		}
+		closeBody(request)
+		return nil, ErrDropped
+
+	case Duplicate:
+		return transport.duplicate(request)
+
+	case Status:
+		closeBody(request)
+		return newStatusResponse(request, fault.StatusCode), nil
+
+	case Close:
+		if c, ok := transport.base().(interface {
+			CloseIdleConnections()
+		}); ok {
+			c.CloseIdleConnections()
+		}
+		closeBody(request)
+		return nil, ErrClosed
+	}
+
+	return transport.base().RoundTrip(request)
+}
+
+// close the request body, the request is not sent
+// RoundTripper should always close the body, even on errors
+func closeBody(request *http.Request) {
+	if request.Body != nil {
+		request.Body.Close()
+	}
+}
+
+// send the same request twice, the second response is returned
+func (transport *Transport) duplicate(request *http.Request) (response *http.Response, err error) {
+	var body []byte
+	if request.Body != nil {
+		body, err = ioutil.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return
+		}
+	}
+
+	first := request.Clone(request.Context())
+	first.Body = ioutil.NopCloser(bytes.NewReader(body))
+	response, err = transport.base().RoundTrip(first)
+	if err == nil {
+		ioutil.ReadAll(response.Body) // ignore errors
+		response.Body.Close()
+	}
+
+	second := request.Clone(request.Context())
+	second.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return transport.base().RoundTrip(second)
+}
+
+// create scripted response with empty body
+func newStatusResponse(request *http.Request, code int) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		StatusCode:    code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{},
+		Body:          ioutil.NopCloser(bytes.NewReader(nil)),
+		ContentLength: 0,
+		Request:       request}
+}
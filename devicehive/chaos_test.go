@@ -0,0 +1,203 @@
+package devicehive
+
+import (
+	"errors"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/chaos"
+	"github.com/devicehive/devicehive-go/devicehive/rest"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// creates new test HTTP server with /info handler
+func testNewInfoServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"apiVersion":"1.2.3","serverTimestamp":"2015-10-22T14:15:16.999"}`)
+	}))
+}
+
+// Test scripted faults injected into REST transport
+func TestChaosRestSchedule(t *testing.T) {
+	server := testNewInfoServer()
+	defer server.Close()
+
+	injector := chaos.NewInjector(chaos.Config{
+		Schedule: []chaos.Fault{
+			{Kind: chaos.Status, StatusCode: http.StatusInternalServerError},
+			{Kind: chaos.Drop},
+			{Kind: chaos.Close},
+			{Kind: chaos.Duplicate},
+			{Kind: chaos.None, Latency: 10 * time.Millisecond}}})
+
+	service, err := rest.NewService(server.URL, "")
+	if err != nil {
+		t.Fatalf("Failed to create REST service (error: %s)", err)
+	}
+	service.SetTransport(chaos.NewTransport(nil, injector))
+
+	for i, expectError := range []bool{true, true, true, false, false} {
+		_, err := service.GetServerInfo(testWaitTimeout)
+		if expectError && err == nil {
+			t.Errorf("#%d: expected error", i)
+		}
+		if !expectError && err != nil {
+			t.Errorf("#%d: unexpected error: %s", i, err)
+		}
+	}
+
+	if injector.Total() != 5 {
+		t.Errorf("unexpected number of processed messages: %d", injector.Total())
+	}
+	for _, kind := range []chaos.Kind{chaos.Status, chaos.Drop, chaos.Close, chaos.Duplicate} {
+		if injector.Count(kind) != 1 {
+			t.Errorf("unexpected number of %s faults: %d", kind, injector.Count(kind))
+		}
+	}
+}
+
+// Test scheduled Status fault without code gets the default one
+func TestChaosScheduledStatus(t *testing.T) {
+	if fault := chaos.NewInjector(chaos.Config{
+		Schedule: []chaos.Fault{{Kind: chaos.Status}}}).Next(); fault.StatusCode != 503 {
+		t.Errorf("unexpected fault: %+v", fault)
+	}
+
+	server := testNewInfoServer()
+	defer server.Close()
+
+	service, err := rest.NewService(server.URL, "")
+	if err != nil {
+		t.Fatalf("Failed to create REST service (error: %s)", err)
+	}
+	service.SetTransport(chaos.NewTransport(nil, chaos.NewInjector(chaos.Config{
+		Schedule: []chaos.Fault{{Kind: chaos.Status}}})))
+
+	if _, err = service.GetServerInfo(testWaitTimeout); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("503 status error expected, got %v", err)
+	}
+}
+
+// Test random faults are reproducible with the same seed
+func TestChaosRandomSeed(t *testing.T) {
+	config := chaos.Config{DropRate: 0.3, DuplicateRate: 0.2, CloseRate: 0.1, Seed: 42}
+	a := chaos.NewInjector(config)
+	b := chaos.NewInjector(config)
+
+	for i := 0; i < 100; i++ {
+		if fa, fb := a.Next(), b.Next(); fa != fb {
+			t.Fatalf("#%d: fault mismatch %s != %s", i, fa, fb)
+		}
+	}
+	if a.Count(chaos.None) == 100 {
+		t.Errorf("no faults injected")
+	}
+}
+
+// request body tracking Close() calls
+type testChaosBody struct {
+	io.Reader
+	closed bool
+}
+
+func (body *testChaosBody) Close() error {
+	body.closed = true
+	return nil
+}
+
+// Test request body is closed when the request is not sent
+func TestChaosRestCloseBody(t *testing.T) {
+	injector := chaos.NewInjector(chaos.Config{
+		Schedule: []chaos.Fault{
+			{Kind: chaos.Status, StatusCode: http.StatusInternalServerError},
+			{Kind: chaos.Drop},
+			{Kind: chaos.Close}}})
+	transport := chaos.NewTransport(nil, injector)
+
+	for i := 0; i < 3; i++ {
+		body := &testChaosBody{Reader: strings.NewReader("{}")}
+		request, err := http.NewRequest("POST", "http://localhost/info", body)
+		if err != nil {
+			t.Fatalf("Failed to create request (error: %s)", err)
+		}
+		response, _ := transport.RoundTrip(request)
+		if response != nil {
+			response.Body.Close()
+		}
+		if !body.closed {
+			t.Errorf("#%d: request body is not closed", i)
+		}
+	}
+}
+
+// in-memory Websocket connection, written messages are read back
+type testChaosConn struct {
+	messages [][]byte
+	closed   bool
+}
+
+func (conn *testChaosConn) WriteMessage(messageType int, data []byte) error {
+	conn.messages = append(conn.messages, data)
+	return nil
+}
+
+func (conn *testChaosConn) ReadMessage() (int, []byte, error) {
+	if len(conn.messages) == 0 {
+		return 0, nil, io.EOF
+	}
+	data := conn.messages[0]
+	conn.messages = conn.messages[1:]
+	return 1, data, nil
+}
+
+func (conn *testChaosConn) Close() error {
+	conn.closed = true
+	return nil
+}
+
+// Test scripted faults injected into Websocket connection
+func TestChaosWebsocketSchedule(t *testing.T) {
+	tx := chaos.NewInjector(chaos.Config{
+		Schedule: []chaos.Fault{
+			{Kind: chaos.Drop},
+			{Kind: chaos.None, Latency: 20 * time.Millisecond},
+			{Kind: chaos.None},
+			{Kind: chaos.Close}}})
+	rx := chaos.NewInjector(chaos.Config{
+		Schedule: []chaos.Fault{
+			{Kind: chaos.Duplicate},
+			{Kind: chaos.Drop}}})
+	base := &testChaosConn{}
+	conn := chaos.NewConn(base, tx, rx)
+
+	// TX: dropped, delayed, sent, closed
+	for _, msg := range []string{"a", "b", "c"} {
+		start := time.Now()
+		if err := conn.WriteMessage(1, []byte(msg)); err != nil {
+			t.Errorf("Failed to send %q (error: %s)", msg, err)
+		}
+		if msg == "b" && time.Since(start) < 20*time.Millisecond {
+			t.Errorf("No latency injected for %q", msg)
+		}
+	}
+	if err := conn.WriteMessage(1, []byte("d")); !errors.Is(err, chaos.ErrClosed) || !base.closed {
+		t.Errorf("Connection is not closed (error: %v)", err)
+	}
+
+	// RX: duplicated, dropped
+	var received []string
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		received = append(received, string(data))
+	}
+	if strings.Join(received, ",") != "b,b" {
+		t.Errorf("Unexpected received messages %q", received)
+	}
+}
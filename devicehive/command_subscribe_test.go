@@ -0,0 +1,72 @@
+package devicehive
+
+import (
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"github.com/devicehive/devicehive-go/devicehive/record"
+	"github.com/devicehive/devicehive-go/devicehive/ws"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Websocket cassette with commands sent right after subscription
+const testWsSubscribeCassette = `
+{"kind":"ws","dir":"dial","url":"ws://localhost/device"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"command/subscribe\",\"requestId\":1,\"deviceId\":\"dev-1\"}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"command/subscribe\",\"requestId\":1,\"status\":\"success\"}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"command/insert\",\"deviceGuid\":\"dev-1\",\"command\":{\"id\":1,\"command\":\"first\"}}"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"command/subscribe\",\"requestId\":2,\"deviceId\":\"dev-1\"}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"command/subscribe\",\"requestId\":2,\"status\":\"success\"}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"command/insert\",\"deviceGuid\":\"dev-1\",\"command\":{\"id\":2,\"command\":\"second\"}}"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"command/subscribe\",\"requestId\":3,\"deviceId\":\"dev-2\"}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"command/subscribe\",\"requestId\":3,\"status\":\"error\",\"code\":404,\"error\":\"Device not found\"}"}
+`
+
+// wait for the command on Websocket listener
+func testWsWaitCommand(t *testing.T, listener *core.CommandListener, id uint64) {
+	select {
+	case cmd, ok := <-listener.C:
+		if !ok || cmd.Id != id {
+			t.Errorf("Unexpected command %v, expected %d", cmd, id)
+		}
+	case <-time.After(testWaitTimeout):
+		t.Errorf("No command %d received", id)
+	}
+}
+
+// Test Websocket command subscription doesn't lose commands and doesn't leak listeners
+func TestWebsocketSubscribeCommands(t *testing.T) {
+	player, err := record.NewPlayer(strings.NewReader(testWsSubscribeCassette))
+	if err != nil {
+		t.Fatalf("Failed to load cassette (error: %s)", err)
+	}
+
+	service, err := ws.NewServiceDial("ws://localhost", "", player.Dial())
+	if err != nil {
+		t.Fatalf("Failed to create WS service (error: %s)", err)
+	}
+
+	// command right after the response is delivered
+	device := core.NewDevice("dev-1", "name", nil)
+	first, err := service.SubscribeCommands(device, core.Timestamp{}, testWaitTimeout)
+	if err != nil {
+		t.Fatalf("Failed to subscribe commands (error: %s)", err)
+	}
+	testWsWaitCommand(t, first, 1)
+
+	// previous listener is closed
+	second, err := service.SubscribeCommands(device, core.Timestamp{}, testWaitTimeout)
+	if err != nil {
+		t.Fatalf("Failed to subscribe commands again (error: %s)", err)
+	}
+	if _, ok := <-first.C; ok {
+		t.Errorf("Previous command listener is not closed")
+	}
+	testWsWaitCommand(t, second, 2)
+
+	// failed subscription gives no listener
+	listener, err := service.SubscribeCommands(core.NewDevice("dev-2", "name", nil), core.Timestamp{}, testWaitTimeout)
+	if err == nil || listener != nil {
+		t.Errorf("Subscription error expected, got %v (listener: %v)", err, listener)
+	}
+}
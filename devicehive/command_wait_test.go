@@ -0,0 +1,118 @@
+package devicehive
+
+import (
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"github.com/devicehive/devicehive-go/devicehive/record"
+	"github.com/devicehive/devicehive-go/devicehive/rest"
+	"github.com/devicehive/devicehive-go/devicehive/ws"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Test REST command insert and wait with intermediate status
+func TestRestSendCommandAndWait(t *testing.T) {
+	var lock sync.Mutex
+	gets := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/device/dev-1/command":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":5,"timestamp":"2016-01-02T03:04:05"}`))
+		case r.URL.Path == "/device/dev-1/command/5/poll":
+			if r.URL.Query().Get("waitTimeout") == "" {
+				t.Errorf("no waitTimeout provided")
+			}
+			w.Write([]byte(`{"id":5,"command":"go","status":"Processing"}`))
+		case r.URL.Path == "/device/dev-1/command/5":
+			if gets++; gets < 3 {
+				w.Write([]byte(`{"id":5,"command":"go","status":"Processing"}`))
+			} else {
+				w.Write([]byte(`{"id":5,"command":"go","status":"Completed","result":{"ok":true}}`))
+			}
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	refresh := rest.CommandRefreshInterval
+	rest.CommandRefreshInterval = time.Millisecond
+	defer func() { rest.CommandRefreshInterval = refresh }()
+
+	service, err := rest.NewService(server.URL, "")
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	device := core.NewDevice("dev-1", "name", nil)
+	command := core.NewCommand("go", nil)
+	var statuses []string
+	err = service.SendCommandAndWait(device, command, testWaitTimeout, func(cmd *core.Command) bool {
+		statuses = append(statuses, cmd.Status)
+		return cmd.IsFinal()
+	})
+	if err != nil || command.Id != 5 || command.Status != "Completed" {
+		t.Fatalf("unexpected command %s (error: %v)", command, err)
+	}
+	if strings.Join(statuses, ",") != "Processing,Completed" {
+		t.Errorf("unexpected status updates %q", statuses)
+	}
+	if res, err := core.CommandResult[map[string]bool](command); err != nil || !res["ok"] {
+		t.Errorf("unexpected command result %v (error: %v)", command.Result, err)
+	}
+}
+
+// Websocket cassette with command insert and pushed updates
+const testWsCommandWaitCassette = `
+{"kind":"ws","dir":"dial","url":"ws://localhost/device"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"command/insert\",\"requestId\":1,\"deviceGuid\":\"dev-1\",\"command\":{\"command\":\"go\"}}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"command/insert\",\"requestId\":1,\"status\":\"success\",\"command\":{\"id\":7,\"timestamp\":\"2016-01-02T03:04:05\"}}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"command/update\",\"command\":{\"id\":7,\"command\":\"go\",\"status\":\"Processing\"}}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"command/update\",\"command\":{\"id\":7,\"command\":\"go\",\"status\":\"Completed\",\"result\":42}}"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"command/insert\",\"requestId\":2,\"deviceGuid\":\"dev-1\",\"command\":{\"command\":\"go\"}}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"command/insert\",\"requestId\":2,\"status\":\"success\",\"command\":{\"id\":8}}"}
+`
+
+// Test Websocket command insert and wait through the Service interface
+func TestWebsocketSendCommandAndWait(t *testing.T) {
+	player, err := record.NewPlayer(strings.NewReader(testWsCommandWaitCassette))
+	if err != nil {
+		t.Fatalf("Failed to load cassette (error: %s)", err)
+	}
+
+	ws_service, err := ws.NewServiceDial("ws://localhost", "", player.Dial())
+	if err != nil {
+		t.Fatalf("Failed to create WS service (error: %s)", err)
+	}
+	var service CommandService = ws_service
+
+	device := core.NewDevice("dev-1", "name", nil)
+	command := core.NewCommand("go", nil)
+	var statuses []string
+	err = service.SendCommandAndWait(device, command, testWaitTimeout, func(cmd *core.Command) bool {
+		statuses = append(statuses, cmd.Status)
+		return cmd.IsFinal()
+	})
+	if err != nil || command.Id != 7 || command.Status != "Completed" {
+		t.Fatalf("Unexpected command %s (error: %v)", command, err)
+	}
+	if strings.Join(statuses, ",") != "Processing,Completed" {
+		t.Errorf("Unexpected status updates %q", statuses)
+	}
+
+	// no updates, deadline
+	command = core.NewCommand("go", nil)
+	err = service.SendCommandAndWait(device, command, 50*time.Millisecond, nil)
+	if err == nil || command.Id != 8 {
+		t.Errorf("Timeout error expected, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package devicehive_test
+
+import (
+	"flag"
+	"github.com/devicehive/devicehive-go/devicehive"
+	"github.com/devicehive/devicehive-go/devicehive/rest"
+	"github.com/devicehive/devicehive-go/devicehive/servicetest"
+	"github.com/devicehive/devicehive-go/devicehive/ws"
+	"testing"
+	"time"
+)
+
+// get test flag value (flags are registered by service_test.go)
+func testFlag(name string) string {
+	if f := flag.Lookup(name); f != nil {
+		return f.Value.String()
+	}
+	return ""
+}
+
+// check if the live server is enabled
+func testLive(urlFlag string) bool {
+	return testFlag("live") == "true" && len(testFlag(urlFlag)) != 0
+}
+
+// creates new service factory for the URL flag provided
+func testFactory(urlFlag string) servicetest.Factory {
+	return func(t *testing.T) devicehive.Service {
+		url := testFlag(urlFlag)
+		if len(url) == 0 {
+			return nil
+		}
+
+		service, err := devicehive.NewService(url, testFlag("access-key"))
+		if err != nil {
+			t.Fatalf("Failed to create service %q (error: %s)", url, err)
+		}
+		return service
+	}
+}
+
+// REST service is used to insert commands
+func testInserter(t *testing.T) servicetest.CommandInserter {
+	url := testFlag("rest-url")
+	if len(url) == 0 {
+		return nil
+	}
+
+	service, err := rest.NewService(url, testFlag("access-key"))
+	if err != nil {
+		t.Fatalf("Failed to create REST service (error: %s)", err)
+	}
+	return service
+}
+
+// offline configuration, the suite is run against the fake server
+func testOfflineConfig(factory servicetest.Factory) servicetest.Config {
+	config := servicetest.DefaultConfig(factory)
+	config.Timeout = 5 * time.Second
+	config.WaitTimeout = 5 * time.Second
+	config.QuietPeriod = 100 * time.Millisecond
+	return config
+}
+
+// Run conformance suite for REST service
+// The fake server is used unless the live server is enabled.
+func TestConformanceRest(t *testing.T) {
+	config := servicetest.DefaultConfig(testFactory("rest-url"))
+	if !testLive("rest-url") {
+		server := newFakeServer(t)
+		config = testOfflineConfig(func(t *testing.T) devicehive.Service {
+			service, err := rest.NewService(server.URL(), "")
+			if err != nil {
+				t.Fatalf("Failed to create REST service (error: %s)", err)
+			}
+			return service
+		})
+	}
+	config.DeviceId += "-rest"
+	servicetest.RunConfig(t, config)
+}
+
+// Run conformance suite for Websocket service
+// The fake server is used unless the live server is enabled.
+func TestConformanceWebsocket(t *testing.T) {
+	config := servicetest.DefaultConfig(testFactory("ws-url"))
+	config.NewInserter = testInserter
+	if !testLive("ws-url") {
+		server := newFakeServer(t)
+		config = testOfflineConfig(func(t *testing.T) devicehive.Service {
+			service, err := ws.NewServiceDial("ws://localhost", "", server.Dial)
+			if err != nil {
+				t.Fatalf("Failed to create WS service (error: %s)", err)
+			}
+			return service
+		})
+		config.NewInserter = func(t *testing.T) servicetest.CommandInserter {
+			service, err := rest.NewService(server.URL(), "")
+			if err != nil {
+				t.Fatalf("Failed to create REST service (error: %s)", err)
+			}
+			return service
+		}
+	}
+	config.DeviceId += "-ws"
+	servicetest.RunConfig(t, config)
+}
@@ -0,0 +1,234 @@
+package devicehive
+
+import (
+	"errors"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"github.com/devicehive/devicehive-go/devicehive/record"
+	"github.com/devicehive/devicehive-go/devicehive/ws"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Websocket cassette with a network error
+const testWsConnectionLostCassette = `
+{"kind":"ws","dir":"dial","url":"ws://localhost/device"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"command/subscribe\",\"requestId\":1,\"deviceId\":\"dev-1\"}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"command/subscribe\",\"requestId\":1,\"status\":\"success\"}"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"device/delete\",\"requestId\":2,\"deviceId\":\"dev-1\"}"}
+{"kind":"ws","dir":"rx","error":"connection reset by peer"}
+`
+
+// Test Websocket pending and new calls fail fast when the connection is lost
+func TestWebsocketConnectionLost(t *testing.T) {
+	player, err := record.NewPlayer(strings.NewReader(testWsConnectionLostCassette))
+	if err != nil {
+		t.Fatalf("Failed to load cassette (error: %s)", err)
+	}
+
+	service, err := ws.NewServiceDial("ws://localhost", "", player.Dial())
+	if err != nil {
+		t.Fatalf("Failed to create WS service (error: %s)", err)
+	}
+
+	device := core.NewDevice("dev-1", "name", nil)
+	listener, err := service.SubscribeCommands(device, core.Timestamp{}, testWaitTimeout)
+	if err != nil {
+		t.Fatalf("Failed to subscribe commands (error: %s)", err)
+	}
+
+	// pending call
+	start := time.Now()
+	err = service.DeleteDevice(device, testWaitTimeout)
+	if !errors.Is(err, ws.ErrConnectionClosed) || !strings.Contains(err.Error(), "connection reset by peer") {
+		t.Errorf("Connection closed error expected, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Pending call failed after %s", elapsed)
+	}
+
+	select {
+	case <-service.Done():
+	case <-time.After(testWaitTimeout):
+		t.Fatalf("Service is not done")
+	}
+	if !errors.Is(service.Err(), ws.ErrConnectionClosed) {
+		t.Errorf("Unexpected service error %v", service.Err())
+	}
+	if _, ok := <-listener.C; ok {
+		t.Errorf("Command listener is not closed")
+	}
+
+	// new calls
+	err = service.InsertNotification(device, core.NewNotification("n", nil), testWaitTimeout)
+	if !errors.Is(err, ws.ErrConnectionClosed) {
+		t.Errorf("Connection closed error expected, got %v", err)
+	}
+	future := service.InsertNotificationAsync(device, core.NewNotification("n", nil), nil)
+	if err = future.Wait(testWaitTimeout); !errors.Is(err, ws.ErrConnectionClosed) {
+		t.Errorf("Connection closed error expected, got %v", err)
+	}
+}
+
+// connection closed by the server
+type testClosedConn struct{}
+
+func (testClosedConn) ReadMessage() (int, []byte, error) {
+	return 0, nil, &websocket.CloseError{Code: websocket.CloseGoingAway, Text: "restart"}
+}
+func (testClosedConn) WriteMessage(int, []byte) error { return nil }
+func (testClosedConn) Close() error                   { return nil }
+
+// Test Websocket close code and reason are reported
+func TestWebsocketConnectionClosedByServer(t *testing.T) {
+	service, err := ws.NewServiceDial("ws://localhost", "", func(string, http.Header) (ws.Conn, error) {
+		return testClosedConn{}, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to create WS service (error: %s)", err)
+	}
+
+	<-service.Done()
+	var closeErr *ws.ConnectionClosedError
+	if !errors.As(service.Err(), &closeErr) || closeErr.Code != websocket.CloseGoingAway || closeErr.Reason != "restart" {
+		t.Errorf("Unexpected service error %v", service.Err())
+	}
+}
+
+// Test Websocket asynchronous call waiting for a free slot fails when the connection is lost
+func TestWebsocketConnectionLostAsyncWindow(t *testing.T) {
+	conn := &testBlockedConn{closed: make(chan struct{})}
+	service, err := ws.NewServiceDial("ws://localhost", "", func(string, http.Header) (ws.Conn, error) {
+		return conn, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to create WS service (error: %s)", err)
+	}
+	service.SetMaxInFlight(1)
+
+	device := core.NewDevice("dev-1", "name", nil)
+	first := service.InsertNotificationAsync(device, core.NewNotification("n1", nil), nil)
+	submitted := make(chan *ws.Future)
+	go func() {
+		submitted <- service.InsertNotificationAsync(device, core.NewNotification("n2", nil), nil)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	close(conn.closed) // connection is lost
+
+	var second *ws.Future
+	select {
+	case second = <-submitted:
+	case <-time.After(testWaitTimeout):
+		t.Fatalf("Asynchronous call is still waiting for a free slot")
+	}
+	for _, f := range []*ws.Future{first, second} {
+		if err = f.Wait(testWaitTimeout); !errors.Is(err, ws.ErrConnectionClosed) {
+			t.Errorf("Connection closed error expected, got %v", err)
+		}
+	}
+}
+
+// connection without responses, lost on demand
+type testBlockedConn struct {
+	closed chan struct{}
+}
+
+func (c *testBlockedConn) ReadMessage() (int, []byte, error) {
+	<-c.closed
+	return 0, nil, errors.New("connection reset by peer")
+}
+func (c *testBlockedConn) WriteMessage(int, []byte) error { return nil }
+func (c *testBlockedConn) Close() error                   { return nil }
+
+// connection failing to send after command/subscribe,
+// pushes commands until closed
+type testPushConn struct {
+	responses chan []byte
+	closed    chan struct{}
+	lock      sync.Mutex
+	writes    int
+}
+
+func (c *testPushConn) ReadMessage() (int, []byte, error) {
+	select {
+	case <-c.closed:
+		return 0, nil, errors.New("use of closed connection")
+	case data := <-c.responses:
+		return websocket.TextMessage, data, nil
+	}
+}
+func (c *testPushConn) WriteMessage(int, []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.writes++; c.writes > 1 {
+		return errors.New("broken pipe")
+	}
+	go func() {
+		c.responses <- []byte(`{"action":"command/subscribe","requestId":1,"status":"success"}`)
+		for {
+			select {
+			case c.responses <- []byte(`{"action":"command/insert","deviceId":"dev-1","command":{"id":1,"command":"cmd"}}`):
+			case <-c.closed:
+				return
+			}
+		}
+	}()
+	return nil
+}
+func (c *testPushConn) Close() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	select {
+	case <-c.closed:
+	default:
+		close(c.closed)
+	}
+	return nil
+}
+
+// Test send error while RX thread is blocked by a full command listener
+// and all the service threads are stopped
+func TestWebsocketConnectionLostOnSend(t *testing.T) {
+	before := runtime.NumGoroutine()
+	conn := &testPushConn{responses: make(chan []byte), closed: make(chan struct{})}
+	service, err := ws.NewServiceDial("ws://localhost", "", func(string, http.Header) (ws.Conn, error) {
+		return conn, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to create WS service (error: %s)", err)
+	}
+
+	device := core.NewDevice("dev-1", "name", nil)
+	listener, err := service.SubscribeCommands(device, core.Timestamp{}, testWaitTimeout)
+	if err != nil {
+		t.Fatalf("Failed to subscribe commands (error: %s)", err)
+	}
+	time.Sleep(50 * time.Millisecond) // listener is full
+
+	if err = service.DeleteDevice(device, testWaitTimeout); !errors.Is(err, ws.ErrConnectionClosed) {
+		t.Errorf("Connection closed error expected, got %v", err)
+	}
+
+	// listener is closed after the received commands
+	for closed := false; !closed; {
+		select {
+		case _, ok := <-listener.C:
+			closed = !ok
+		case <-time.After(testWaitTimeout):
+			t.Fatalf("Command listener is not closed")
+		}
+	}
+
+	deadline := time.Now().Add(testWaitTimeout)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("Service threads are not stopped: %d goroutines, %d expected", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,301 @@
+package core
+
+import "fmt"
+
+// Access key type.
+type AccessKeyType int
+
+// Access key types.
+const (
+	AccessKeyDefault AccessKeyType = 0
+	AccessKeySession AccessKeyType = 1
+	AccessKeyOAuth   AccessKeyType = 2
+)
+
+// Get AccessKeyType string representation
+func (keyType AccessKeyType) String() string {
+	switch keyType {
+	case AccessKeyDefault:
+		return "Default"
+	case AccessKeySession:
+		return "Session"
+	case AccessKeyOAuth:
+		return "OAuth"
+	}
+	return fmt.Sprintf("AccessKeyType(%d)", int(keyType))
+}
+
+// Access key permission action.
+type AccessKeyAction string
+
+// Access key permission actions.
+const (
+	ActionGetNetwork               AccessKeyAction = "GetNetwork"
+	ActionGetDevice                AccessKeyAction = "GetDevice"
+	ActionGetDeviceState           AccessKeyAction = "GetDeviceState"
+	ActionGetDeviceNotification    AccessKeyAction = "GetDeviceNotification"
+	ActionGetDeviceCommand         AccessKeyAction = "GetDeviceCommand"
+	ActionRegisterDevice           AccessKeyAction = "RegisterDevice"
+	ActionCreateDeviceNotification AccessKeyAction = "CreateDeviceNotification"
+	ActionCreateDeviceCommand      AccessKeyAction = "CreateDeviceCommand"
+	ActionUpdateDeviceCommand      AccessKeyAction = "UpdateDeviceCommand"
+	ActionGetCurrentUser           AccessKeyAction = "GetCurrentUser"
+	ActionUpdateCurrentUser        AccessKeyAction = "UpdateCurrentUser"
+	ActionManageAccessKey          AccessKeyAction = "ManageAccessKey"
+	ActionManageOAuthGrant         AccessKeyAction = "ManageOAuthGrant"
+)
+
+// Represents an access key permission.
+// Nil list means no restriction, i.e. any domain, any subnet etc.
+type AccessKeyPermission struct {
+	// Allowed domains [optional], e.g. "www.example.com" or ".example.com".
+	Domains []string `json:"domains,omitempty"`
+
+	// Allowed subnets [optional], e.g. "127.0.0.1" or "10.10.0.0/16".
+	Subnets []string `json:"subnets,omitempty"`
+
+	// Allowed actions [optional].
+	Actions []AccessKeyAction `json:"actions,omitempty"`
+
+	// Allowed network identifiers [optional].
+	NetworkIds []uint64 `json:"networkIds,omitempty"`
+
+	// Allowed device identifiers [optional].
+	DeviceIds []string `json:"deviceGuids,omitempty"`
+}
+
+// Get AccessKeyPermission string representation
+func (perm AccessKeyPermission) String() string {
+	body := ""
+
+	// Domains [optional]
+	if perm.Domains != nil {
+		body += fmt.Sprintf(", Domains:%q", perm.Domains)
+	}
+
+	// Subnets [optional]
+	if perm.Subnets != nil {
+		body += fmt.Sprintf(", Subnets:%q", perm.Subnets)
+	}
+
+	// Actions [optional]
+	if perm.Actions != nil {
+		body += fmt.Sprintf(", Actions:%q", perm.Actions)
+	}
+
+	// NetworkIds [optional]
+	if perm.NetworkIds != nil {
+		body += fmt.Sprintf(", NetworkIds:%v", perm.NetworkIds)
+	}
+
+	// DeviceIds [optional]
+	if perm.DeviceIds != nil {
+		body += fmt.Sprintf(", DeviceIds:%q", perm.DeviceIds)
+	}
+
+	if len(body) != 0 {
+		body = body[2:] // remove leading ", "
+	}
+
+	return fmt.Sprintf("AccessKeyPermission{%s}", body)
+}
+
+// MarshalJSON() encodes permission to JSON.
+// Nil lists are omitted, empty lists are encoded as [].
+func (perm AccessKeyPermission) MarshalJSON() ([]byte, error) {
+	encoder := &objectEncoder{}
+	if perm.Domains != nil {
+		encoder.put("domains", perm.Domains)
+	}
+	if perm.Subnets != nil {
+		encoder.put("subnets", perm.Subnets)
+	}
+	if perm.Actions != nil {
+		encoder.put("actions", perm.Actions)
+	}
+	if perm.NetworkIds != nil {
+		encoder.put("networkIds", perm.NetworkIds)
+	}
+	if perm.DeviceIds != nil {
+		encoder.put("deviceGuids", perm.DeviceIds)
+	}
+	return encoder.finish()
+}
+
+// UnmarshalJSON() decodes permission from JSON.
+func (perm *AccessKeyPermission) UnmarshalJSON(data []byte) (err error) {
+	decoder, err := newObjectDecoder("AccessKeyPermission", data)
+	if decoder == nil {
+		return
+	}
+
+	if err = decodeField(decoder, &perm.Domains, "domains"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &perm.Subnets, "subnets"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &perm.Actions, "actions"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &perm.NetworkIds, "networkIds", "networks"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &perm.DeviceIds, "deviceGuids", "devices"); err != nil {
+		return
+	}
+	decoder.ignore("id")
+
+	return decoder.finish()
+}
+
+// HasAction() checks if the action is allowed by the permission.
+func (perm *AccessKeyPermission) HasAction(action AccessKeyAction) bool {
+	if perm.Actions == nil {
+		return true // no restriction
+	}
+	for _, a := range perm.Actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// Represents an access key - an alternative authentication mechanism.
+type AccessKey struct {
+	// Unique identifier [do not change].
+	Id uint64 `json:"id,omitempty"`
+
+	// Display label.
+	Label string `json:"label"`
+
+	// Key value [read only]. Generated by the server.
+	Key string `json:"key,omitempty"`
+
+	// Expiration date, UTC [optional]. Zero means never expires.
+	ExpirationDate Timestamp `json:"expirationDate,omitzero"`
+
+	// Key type.
+	Type AccessKeyType `json:"type"`
+
+	// Permissions. Key without permissions has no access.
+	Permissions []*AccessKeyPermission `json:"permissions"`
+}
+
+// NewAccessKey creates a new access key with the permissions.
+func NewAccessKey(label string, permissions ...*AccessKeyPermission) *AccessKey {
+	return &AccessKey{Label: label, Permissions: permissions}
+}
+
+// Get AccessKey string representation
+func (key AccessKey) String() string {
+	body := ""
+
+	// Id [optional]
+	if key.Id != 0 {
+		body += fmt.Sprintf("Id:%d, ", key.Id)
+	}
+
+	// Label
+	body += fmt.Sprintf("Label:%q", key.Label)
+
+	// Key [optional]
+	if len(key.Key) != 0 {
+		body += fmt.Sprintf(", Key:%q", RedactSecret(key.Key))
+	}
+
+	// ExpirationDate [optional]
+	if !key.ExpirationDate.IsZero() {
+		body += fmt.Sprintf(", ExpirationDate:%q", key.ExpirationDate)
+	}
+
+	// Type
+	body += fmt.Sprintf(", Type:%s", key.Type)
+
+	// Permissions
+	body += fmt.Sprintf(", Permissions:%v", key.Permissions)
+
+	return fmt.Sprintf("AccessKey{%s}", body)
+}
+
+// MarshalJSON() encodes access key to JSON.
+func (key AccessKey) MarshalJSON() ([]byte, error) {
+	encoder := &objectEncoder{}
+	if key.Id != 0 {
+		encoder.put("id", key.Id)
+	}
+	encoder.put("label", key.Label)
+	if len(key.Key) != 0 {
+		encoder.put("key", key.Key)
+	}
+	if !key.ExpirationDate.IsZero() {
+		encoder.put("expirationDate", key.ExpirationDate)
+	}
+	encoder.put("type", key.Type)
+	if key.Permissions != nil {
+		encoder.put("permissions", key.Permissions)
+	} else {
+		encoder.put("permissions", []*AccessKeyPermission{})
+	}
+	return encoder.finish()
+}
+
+// UnmarshalJSON() decodes access key from JSON.
+func (key *AccessKey) UnmarshalJSON(data []byte) (err error) {
+	decoder, err := newObjectDecoder("AccessKey", data)
+	if decoder == nil {
+		return
+	}
+
+	if err = decoder.uint64(&key.Id, "id"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &key.Label, "label"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &key.Key, "key"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &key.ExpirationDate, "expirationDate"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &key.Type, "type"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &key.Permissions, "permissions"); err != nil {
+		return
+	}
+	decoder.ignore("user")
+
+	return decoder.finish()
+}
+
+// Validate() checks the access key fields.
+// Label should not be empty, permission lists should not contain empty items.
+func (key *AccessKey) Validate() error {
+	v := &validator{}
+	v.required(key.Label, "label")
+	v.check(key.Type >= AccessKeyDefault && key.Type <= AccessKeyOAuth,
+		"type", "unknown type %d", int(key.Type))
+	for i, perm := range key.Permissions {
+		field := fmt.Sprintf("permissions[%d]", i)
+		if perm == nil {
+			v.check(false, field, "is nil")
+			continue
+		}
+		for _, d := range perm.Domains {
+			v.check(len(d) != 0, field+".domains", "contains empty domain")
+		}
+		for _, s := range perm.Subnets {
+			v.check(len(s) != 0, field+".subnets", "contains empty subnet")
+		}
+		for _, a := range perm.Actions {
+			v.check(len(a) != 0, field+".actions", "contains empty action")
+		}
+		for _, d := range perm.DeviceIds {
+			v.check(len(d) != 0, field+".deviceGuids", "contains empty device")
+		}
+	}
+	return v.result("AccessKey")
+}
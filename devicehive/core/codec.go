@@ -0,0 +1,232 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// strict JSON mode, see SetStrictJSON()
+var strictJSON atomic.Bool
+
+// SetStrictJSON() enables or disables strict JSON mode.
+//
+// In strict mode decoding of any model type fails with
+// *UnknownFieldsError if JSON object contains unknown fields.
+// Unknown fields are silently ignored by default.
+//
+// The mode is process-wide and might be changed at any time,
+// values being decoded concurrently use either mode.
+func SetStrictJSON(enable bool) {
+	strictJSON.Store(enable)
+}
+
+// IsStrictJSON() checks if strict JSON mode is enabled.
+func IsStrictJSON() bool {
+	return strictJSON.Load()
+}
+
+// Unknown fields error, reported in strict JSON mode only.
+type UnknownFieldsError struct {
+	// Model type name.
+	Type string
+
+	// Unknown field names, sorted.
+	Fields []string
+}
+
+// Get error string representation
+func (err *UnknownFieldsError) Error() string {
+	return fmt.Sprintf("%s: unknown fields %s", err.Type,
+		strings.Join(err.Fields, ", "))
+}
+
+// JSON object decoder
+// keeps track of used fields
+type objectDecoder struct {
+	name   string
+	fields map[string]json.RawMessage
+	used   map[string]bool
+}
+
+// create new JSON object decoder
+// null object gives nil decoder without error
+func newObjectDecoder(name string, data []byte) (decoder *objectDecoder, err error) {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil, nil
+	}
+
+	decoder = &objectDecoder{name: name,
+		used: make(map[string]bool)}
+	err = json.Unmarshal(data, &decoder.fields)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s - unexpected data type", name, string(data))
+	}
+
+	return
+}
+
+// get the first existing field from the name variants
+// all variants are marked as used
+func (decoder *objectDecoder) lookup(names ...string) (raw json.RawMessage, ok bool) {
+	for _, name := range names {
+		decoder.used[name] = true
+		if !ok {
+			raw, ok = decoder.fields[name]
+		}
+	}
+	return
+}
+
+// mark some fields as known but ignored
+func (decoder *objectDecoder) ignore(names ...string) {
+	for _, name := range names {
+		decoder.used[name] = true
+	}
+}
+
+// report error for a field
+func (decoder *objectDecoder) fieldError(raw json.RawMessage, names []string) error {
+	return fmt.Errorf("%s: %s - unexpected value for %s",
+		decoder.name, string(raw), names[0])
+}
+
+// decode field: null gives zero value, missing field is not changed
+func decodeField[T any](decoder *objectDecoder, v *T, names ...string) error {
+	raw, ok := decoder.lookup(names...)
+	if !ok {
+		return nil // not changed
+	}
+
+	if string(raw) == "null" {
+		var zero T
+		*v = zero
+		return nil
+	}
+
+	err := json.Unmarshal(raw, v)
+	if err != nil {
+		if _, ok := err.(*UnknownFieldsError); ok {
+			return err // nested object in strict mode
+		}
+		return decoder.fieldError(raw, names)
+	}
+
+	return nil // OK
+}
+
+// decode unsigned integer field
+// string values are also accepted
+func (decoder *objectDecoder) uint64(v *uint64, names ...string) error {
+	raw, ok := decoder.lookup(names...)
+	if !ok {
+		return nil // not changed
+	}
+
+	switch {
+	case string(raw) == "null":
+		*v = 0
+		return nil
+
+	case len(raw) != 0 && raw[0] == '"':
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			x, err := strconv.ParseUint(s, 10, 64)
+			if err == nil {
+				*v = x
+				return nil
+			}
+		}
+
+	default:
+		x, err := strconv.ParseUint(string(raw), 10, 64)
+		if err == nil {
+			*v = x
+			return nil
+		}
+	}
+
+	return decoder.fieldError(raw, names)
+}
+
+// decode arbitrary field according to raw JSON mode
+func (decoder *objectDecoder) value(v *interface{}, names ...string) (err error) {
+	raw, ok := decoder.lookup(names...)
+	if !ok {
+		return nil // not changed
+	}
+
+	*v, err = assignRaw(raw)
+	if err != nil {
+		return decoder.fieldError(raw, names)
+	}
+
+	return nil // OK
+}
+
+// check unknown fields in strict mode
+func (decoder *objectDecoder) finish() error {
+	if !strictJSON.Load() {
+		return nil
+	}
+
+	var unknown []string
+	for name := range decoder.fields {
+		if !decoder.used[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) != 0 {
+		sort.Strings(unknown)
+		return &UnknownFieldsError{Type: decoder.name, Fields: unknown}
+	}
+
+	return nil // OK
+}
+
+// JSON object encoder
+// fields are written in order
+type objectEncoder struct {
+	buf bytes.Buffer
+	err error
+}
+
+// put field to the JSON object
+func (encoder *objectEncoder) put(name string, v interface{}) {
+	if encoder.err != nil {
+		return
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		encoder.err = err
+		return
+	}
+
+	if encoder.buf.Len() == 0 {
+		encoder.buf.WriteByte('{')
+	} else {
+		encoder.buf.WriteByte(',')
+	}
+	name_data, _ := json.Marshal(name)
+	encoder.buf.Write(name_data)
+	encoder.buf.WriteByte(':')
+	encoder.buf.Write(data)
+}
+
+// get the JSON object
+func (encoder *objectEncoder) finish() ([]byte, error) {
+	if encoder.err != nil {
+		return nil, encoder.err
+	}
+	if encoder.buf.Len() == 0 {
+		return []byte("{}"), nil
+	}
+
+	encoder.buf.WriteByte('}')
+	return encoder.buf.Bytes(), nil
+}
@@ -1,6 +1,9 @@
 package core
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Represents command object - a set of data sent from DeviceHive to devices.
 type Command struct {
@@ -8,7 +11,7 @@ type Command struct {
 	Id uint64 `json:"id,omitempty"`
 
 	// Timestamp, UTC.
-	Timestamp string `json:"timestamp,omitempty"`
+	Timestamp Timestamp `json:"timestamp,omitzero"`
 
 	// Accociated user identifier.
 	UserId uint64 `json:"userId,omitempty"`
@@ -65,7 +68,7 @@ func (command Command) String() string {
 	body += fmt.Sprintf("Name:%q", command.Name)
 
 	// Timestamp
-	if len(command.Timestamp) != 0 {
+	if !command.Timestamp.IsZero() {
 		body += fmt.Sprintf(", Timestamp:%q", command.Timestamp)
 	}
 
@@ -81,7 +84,7 @@ func (command Command) String() string {
 
 	// Parameters [optional]
 	if command.Parameters != nil {
-		body += fmt.Sprintf(", Parameters:%v", command.Parameters)
+		body += fmt.Sprintf(", Parameters:%s", formatValue(command.Parameters))
 	}
 
 	// Status
@@ -91,103 +94,149 @@ func (command Command) String() string {
 
 	// Result [optional]
 	if command.Result != nil {
-		body += fmt.Sprintf(", Result:%v", command.Result)
+		body += fmt.Sprintf(", Result:%s", formatValue(command.Result))
 	}
 
 	return fmt.Sprintf("Command{%s}", body)
 }
 
-// Assign parsed JSON.
-// This method is used to assign already parsed JSON data.
-func (command *Command) AssignJSON(rawData interface{}) error {
-	if rawData == nil {
-		return fmt.Errorf("Command: no data")
+// MarshalJSON() encodes command to JSON.
+// Empty fields are omitted.
+func (command Command) MarshalJSON() ([]byte, error) {
+	encoder := &objectEncoder{}
+	if command.Id != 0 {
+		encoder.put("id", command.Id)
 	}
-
-	data, ok := rawData.(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("Command: %v - unexpected data type", rawData)
+	if !command.Timestamp.IsZero() {
+		encoder.put("timestamp", command.Timestamp)
 	}
-
-	// identifier
-	if id, ok := data["id"]; ok {
-		switch v := id.(type) {
-		case float64:
-			command.Id = uint64(v)
-		case uint64:
-			command.Id = v
-		default:
-			return fmt.Errorf("Command: %v - unexpected value for id", id)
-		}
+	if command.UserId != 0 {
+		encoder.put("userId", command.UserId)
 	}
-
-	// timestamp
-	if ts, ok := data["timestamp"]; ok {
-		switch v := ts.(type) {
-		case string:
-			command.Timestamp = v
-		default:
-			return fmt.Errorf("Command: %v - unexpected value for timestamp", ts)
-		}
+	if len(command.Name) != 0 {
+		encoder.put("command", command.Name)
 	}
-
-	// user identifier
-	if id, ok := data["userId"]; ok {
-		switch v := id.(type) {
-		case float64:
-			command.UserId = uint64(v)
-		case uint64:
-			command.UserId = v
-		default:
-			return fmt.Errorf("Command: %v - unexpected value for userId", id)
-		}
+	if command.Lifetime != 0 {
+		encoder.put("lifetime", command.Lifetime)
 	}
-
-	// name
-	if name, ok := data["command"]; ok {
-		switch v := name.(type) {
-		case string:
-			command.Name = v
-		default:
-			return fmt.Errorf("Command: %v - unexpected value for name", name)
-		}
+	if command.Parameters != nil {
+		encoder.put("parameters", command.Parameters)
+	}
+	if len(command.Status) != 0 {
+		encoder.put("status", command.Status)
+	}
+	if command.Result != nil {
+		encoder.put("result", command.Result)
 	}
+	return encoder.finish()
+}
 
-	// lifetime
-	if lt, ok := data["lifetime"]; ok {
-		switch v := lt.(type) {
-		case float64:
-			command.Lifetime = uint64(v)
-		case uint64:
-			command.Lifetime = v
-		case nil:
-			command.Lifetime = 0
-		default:
-			return fmt.Errorf("Command: %v - unexpected value for lifetime", lt)
-		}
+// UnmarshalJSON() decodes command from JSON.
+// Both "command" and "name" are accepted for command name.
+// Parameters and result are decoded according to raw JSON mode.
+func (command *Command) UnmarshalJSON(data []byte) (err error) {
+	decoder, err := newObjectDecoder("Command", data)
+	if decoder == nil {
+		return
 	}
 
-	// parameters (as is)
-	if p, ok := data["parameters"]; ok {
-		command.Parameters = p
+	if err = decoder.uint64(&command.Id, "id"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &command.Timestamp, "timestamp"); err != nil {
+		return
+	}
+	if err = decoder.uint64(&command.UserId, "userId"); err != nil {
+		return
 	}
+	if err = decodeField(decoder, &command.Name, "command", "name"); err != nil {
+		return
+	}
+	if err = decoder.uint64(&command.Lifetime, "lifetime"); err != nil {
+		return
+	}
+	if err = decoder.value(&command.Parameters, "parameters"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &command.Status, "status"); err != nil {
+		return
+	}
+	if err = decoder.value(&command.Result, "result"); err != nil {
+		return
+	}
+
+	// known server fields, not modelled
+	decoder.ignore("deviceGuid", "deviceId", "networkId", "isUpdated", "lastUpdated")
+	return decoder.finish()
+}
+
+// DecodeParameters() decodes command parameters into the provided value.
+func (command *Command) DecodeParameters(v interface{}) error {
+	return DecodeValue(command.Parameters, v)
+}
+
+// DecodeResult() decodes command result into the provided value.
+func (command *Command) DecodeResult(v interface{}) error {
+	return DecodeValue(command.Result, v)
+}
+
+// Command statuses considered as intermediate, i.e. a device
+// is still processing the command and the final result is expected.
+var IntermediateCommandStatuses = []string{"Pending", "Processing", "InProgress"}
 
-	// status
-	if s, ok := data["status"]; ok {
-		switch v := s.(type) {
-		case string:
-			command.Status = v
-		case nil:
-			command.Status = ""
-		default:
-			return fmt.Errorf("Command: %v - unexpected value for status", s)
+// IsFinal() checks if the command has a final status reported by device.
+// Empty status and IntermediateCommandStatuses are not final.
+func (command *Command) IsFinal() bool {
+	if len(command.Status) == 0 {
+		return false
+	}
+	for _, status := range IntermediateCommandStatuses {
+		if strings.EqualFold(command.Status, status) {
+			return false
 		}
 	}
+	return true
+}
 
-	// result (as is)
-	if r, ok := data["result"]; ok {
-		command.Result = r
+// Clone() creates a deep copy of the command.
+func (command *Command) Clone() *Command {
+	if command == nil {
+		return nil
 	}
 
-	return nil // OK
+	c := *command
+	c.Parameters = cloneValue(command.Parameters)
+	c.Result = cloneValue(command.Result)
+	return &c
+}
+
+// Equal() checks if two commands are the same.
+func (command *Command) Equal(other *Command) bool {
+	return len(command.Diff(other)) == 0
+}
+
+// Diff() reports all the changes from this command to the other one.
+func (command *Command) Diff(other *Command) []Change {
+	if command == nil || other == nil {
+		return diffPresence(nil, "", command, other)
+	}
+
+	var changes []Change
+	changes = diffField(changes, "id", command.Id, other.Id)
+	changes = diffTimestamp(changes, "timestamp", command.Timestamp, other.Timestamp)
+	changes = diffField(changes, "userId", command.UserId, other.UserId)
+	changes = diffField(changes, "command", command.Name, other.Name)
+	changes = diffField(changes, "lifetime", command.Lifetime, other.Lifetime)
+	changes = diffValue(changes, "parameters", command.Parameters, other.Parameters)
+	changes = diffField(changes, "status", command.Status, other.Status)
+	changes = diffValue(changes, "result", command.Result, other.Result)
+	return changes
+}
+
+// Validate() checks the command fields.
+// Name should not be empty.
+func (command *Command) Validate() error {
+	v := &validator{}
+	v.required(command.Name, "command")
+	return v.result("Command")
 }
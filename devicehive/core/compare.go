@@ -0,0 +1,262 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Change describes a single difference between two objects.
+type Change struct {
+	// Path to the changed field using JSON names,
+	// for example "deviceClass.equipment[0].name".
+	// Empty path means the whole object.
+	Path string
+
+	// Old value, nil if absent.
+	Old interface{}
+
+	// New value, nil if absent.
+	New interface{}
+}
+
+// Get Change string representation
+func (change Change) String() string {
+	body := fmt.Sprintf("%s -> %s", formatChange(change.Old), formatChange(change.New))
+	if len(change.Path) != 0 {
+		body = fmt.Sprintf("%s: %s", change.Path, body)
+	}
+	return body
+}
+
+// format changed value
+func formatChange(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "<none>"
+	case string:
+		return fmt.Sprintf("%q", x)
+	}
+	return formatValue(v)
+}
+
+// FormatChanges() produces human-readable change report, one change per line.
+func FormatChanges(changes []Change) string {
+	lines := make([]string, 0, len(changes))
+	for _, change := range changes {
+		lines = append(lines, change.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// join field path
+func joinPath(prefix, name string) string {
+	if len(prefix) == 0 {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// compare simple field
+func diffField[T comparable](changes []Change, path string, old, new T) []Change {
+	if old != new {
+		changes = append(changes, Change{Path: path, Old: old, New: new})
+	}
+	return changes
+}
+
+// compare timestamp field
+func diffTimestamp(changes []Change, path string, old, new Timestamp) []Change {
+	if !old.Equal(new.Time) {
+		changes = append(changes, Change{Path: path, Old: old.String(), New: new.String()})
+	}
+	return changes
+}
+
+// compare objects when at least one of them is missing (nil pointer)
+func diffPresence(changes []Change, path string, old, new interface{}) []Change {
+	old, new = derefValue(old), derefValue(new)
+	if old != nil || new != nil {
+		changes = append(changes, Change{Path: path, Old: old, New: new})
+	}
+	return changes
+}
+
+// dereference pointer, nil if pointer is nil
+func derefValue(v interface{}) interface{} {
+	r := reflect.ValueOf(v)
+	if r.Kind() == reflect.Ptr {
+		if r.IsNil() {
+			return nil
+		}
+		return r.Elem().Interface()
+	}
+	return v
+}
+
+// compare arbitrary values, JSON objects and arrays are compared recursively
+// Values are compared by their JSON representation, so numbers of
+// different types or raw JSON are considered equal if they represent the same value.
+func diffValue(changes []Change, path string, old, new interface{}) []Change {
+	if reflect.DeepEqual(old, new) {
+		return changes // fast path
+	}
+
+	a, errA := normalizeValue(old)
+	b, errB := normalizeValue(new)
+	if errA != nil || errB != nil {
+		return append(changes, Change{Path: path, Old: old, New: new})
+	}
+
+	return diffGeneric(changes, path, a, b)
+}
+
+// compare generic JSON values
+func diffGeneric(changes []Change, path string, old, new interface{}) []Change {
+	switch a := old.(type) {
+	case map[string]interface{}:
+		if b, ok := new.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(a)+len(b))
+			for k := range a {
+				keys = append(keys, k)
+			}
+			for k := range b {
+				if _, ok := a[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+
+			for _, k := range keys {
+				changes = diffGeneric(changes, joinPath(path, k), a[k], b[k])
+			}
+			return changes
+		}
+
+	case []interface{}:
+		if b, ok := new.([]interface{}); ok && len(a) == len(b) {
+			for i := range a {
+				changes = diffGeneric(changes, fmt.Sprintf("%s[%d]", path, i), a[i], b[i])
+			}
+			return changes
+		}
+
+	case json.Number:
+		if b, ok := new.(json.Number); ok && equalNumber(a, b) {
+			return changes
+		}
+	}
+
+	if !reflect.DeepEqual(old, new) {
+		changes = append(changes, Change{Path: path, Old: old, New: new})
+	}
+	return changes
+}
+
+// convert arbitrary value to generic JSON value
+func normalizeValue(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	data, ok := v.(json.RawMessage)
+	if !ok {
+		var err error
+		data, err = json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var res interface{}
+	err := UnmarshalNumber(data, &res)
+	return res, err
+}
+
+// compare two JSON numbers by value
+func equalNumber(a, b json.Number) bool {
+	if a == b {
+		return true
+	}
+
+	x, okA := new(big.Rat).SetString(a.String())
+	y, okB := new(big.Rat).SetString(b.String())
+	return okA && okB && x.Cmp(y) == 0
+}
+
+// copy arbitrary value
+// Maps, slices and pointers are copied recursively.
+// Values should not contain cycles.
+func cloneValue(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return cloneReflect(reflect.ValueOf(v)).Interface()
+}
+
+// copy arbitrary value using reflection
+func cloneReflect(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for it := v.MapRange(); it.Next(); {
+			c.SetMapIndex(it.Key(), cloneReflect(it.Value()))
+		}
+		return c
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			reflect.Copy(c, v) // []byte and json.RawMessage
+			return c
+		}
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(cloneReflect(v.Index(i)))
+		}
+		return c
+
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(cloneReflect(v.Index(i)))
+		}
+		return c
+
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(cloneReflect(v.Elem()))
+		return c
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(cloneReflect(v.Elem()))
+		return c
+
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v) // unexported fields are copied as is
+		for i := 0; i < v.NumField(); i++ {
+			if f := c.Field(i); f.CanSet() {
+				f.Set(cloneReflect(v.Field(i)))
+			}
+		}
+		return c
+	}
+
+	return v
+}
@@ -2,7 +2,14 @@
 // Defines common data structures
 package core
 
+import "errors"
+
 const (
-	// Datetime layout used for timestamps
+	// Datetime layout used for timestamps (milliseconds precision).
+	// See Timestamp type for parsing and formatting.
 	DateTimeLayout = "2006-01-02T15:04:05.999"
 )
+
+// Error returned by all service methods if the timeout is expired.
+// Check with errors.Is(), the actual error might be wrapped.
+var ErrTimeout = errors.New("timed out")
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// raw JSON mode, see SetRawJSON()
+var rawJSON atomic.Bool
+
+// SetRawJSON() enables or disables raw JSON mode.
+//
+// In raw JSON mode all arbitrary values (command parameters and result,
+// notification parameters, device, device class and equipment data)
+// are kept as json.RawMessage, so they could be decoded once
+// directly into the caller's structure using Decode* methods.
+// Otherwise they are decoded into generic
+// map[string]interface{}, []interface{}, json.Number, etc.
+// Numbers are kept as json.Number to avoid precision loss.
+//
+// The mode is process-wide and might be changed at any time,
+// values being decoded concurrently use either mode.
+func SetRawJSON(enable bool) {
+	rawJSON.Store(enable)
+}
+
+// IsRawJSON() checks if raw JSON mode is enabled.
+func IsRawJSON() bool {
+	return rawJSON.Load()
+}
+
+// DecodeValue decodes an arbitrary value into the provided one.
+// The value might be json.RawMessage or generic tree of
+// map[string]interface{}, []interface{}, json.Number, etc.
+// Nothing is changed for nil value.
+func DecodeValue(data interface{}, v interface{}) error {
+	switch d := data.(type) {
+	case nil:
+		return nil // nothing to decode
+
+	case json.RawMessage:
+		return json.Unmarshal(d, v)
+
+	case *json.RawMessage:
+		if d == nil {
+			return nil
+		}
+		return json.Unmarshal(*d, v)
+	}
+
+	// generic data, convert using JSON
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// DecodeAs decodes an arbitrary value into a new value of type T.
+func DecodeAs[T any](data interface{}) (value T, err error) {
+	err = DecodeValue(data, &value)
+	return
+}
+
+// CommandParameters decodes command parameters into a new value of type T.
+func CommandParameters[T any](command *Command) (T, error) {
+	return DecodeAs[T](command.Parameters)
+}
+
+// CommandResult decodes command result into a new value of type T.
+func CommandResult[T any](command *Command) (T, error) {
+	return DecodeAs[T](command.Result)
+}
+
+// NotificationParameters decodes notification parameters into a new value of type T.
+func NotificationParameters[T any](notification *Notification) (T, error) {
+	return DecodeAs[T](notification.Parameters)
+}
+
+// DeviceData decodes device data into a new value of type T.
+func DeviceData[T any](device *Device) (T, error) {
+	return DecodeAs[T](device.Data)
+}
+
+// convert raw JSON value according to raw JSON mode
+// return nil for null
+func assignRaw(raw json.RawMessage) (value interface{}, err error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	if rawJSON.Load() {
+		return json.RawMessage(append([]byte(nil), raw...)), nil
+	}
+
+	err = UnmarshalNumber(raw, &value)
+	return
+}
+
+// UnmarshalNumber is the same as json.Unmarshal but
+// numbers inside interface{} values are decoded as json.Number
+// instead of float64, so big integers do not lose precision.
+func UnmarshalNumber(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	err := decoder.Decode(v)
+	if err != nil {
+		return err
+	}
+
+	// no data after value expected
+	if _, err = decoder.Token(); err != io.EOF {
+		return fmt.Errorf("unexpected data after JSON value")
+	}
+
+	return nil // OK
+}
+
+// format arbitrary value for String() methods
+// raw JSON is printed as text
+func formatValue(data interface{}) string {
+	switch d := data.(type) {
+	case json.RawMessage:
+		return string(d)
+	case *json.RawMessage:
+		if d != nil {
+			return string(*d)
+		}
+	}
+
+	return fmt.Sprintf("%v", data)
+}
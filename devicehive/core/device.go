@@ -1,6 +1,9 @@
 package core
 
-import "fmt"
+import (
+	"fmt"
+	"unicode/utf8"
+)
 
 // Represents a device - unit that communicate with DeviceHive.
 type Device struct {
@@ -59,7 +62,7 @@ func (device Device) String() string {
 
 	// Key [optional]
 	if len(device.Key) != 0 {
-		body += fmt.Sprintf(", Key:%q", device.Key)
+		body += fmt.Sprintf(", Key:%q", RedactSecret(device.Key))
 	}
 
 	// Status [optional]
@@ -68,7 +71,7 @@ func (device Device) String() string {
 	}
 
 	// Data [optional]
-	body += fmt.Sprintf(", Data:%v", device.Data)
+	body += fmt.Sprintf(", Data:%s", formatValue(device.Data))
 
 	// Network [optional]
 	if device.Network != nil {
@@ -83,92 +86,159 @@ func (device Device) String() string {
 	return fmt.Sprintf("Device{%s}", body)
 }
 
-// Assign parsed JSON.
-// This method is used to assign already parsed JSON data.
-func (device *Device) AssignJSON(rawData interface{}) error {
-	if rawData == nil {
-		return fmt.Errorf("Device: no data")
-	}
-
-	data, ok := rawData.(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("Device: %v - unexpected data type", rawData)
-	}
-
-	// identifier
-	if id, ok := data["id"]; ok {
-		switch v := id.(type) {
-		case string:
-			device.Id = v
-		default:
-			return fmt.Errorf("Device: %v - unexpected value for id", id)
-		}
-	}
-
-	// name
-	if name, ok := data["name"]; ok {
-		switch v := name.(type) {
-		case string:
-			device.Name = v
-		default:
-			return fmt.Errorf("Device: %v - unexpected value for name", name)
-		}
-	}
-
-	// key
-	if key, ok := data["key"]; ok {
-		switch v := key.(type) {
-		case string:
-			device.Key = v
-		default:
-			return fmt.Errorf("Device: %v - unexpected value for key", key)
-		}
-	}
-
-	// status
-	if s, ok := data["status"]; ok {
-		switch v := s.(type) {
-		case string:
-			device.Status = v
-		default:
-			return fmt.Errorf("Device: %v - unexpected value for status", s)
-		}
-	}
-
-	// data (as is)
-	if d, ok := data["data"]; ok {
-		device.Data = d
-	}
-
-	// network
-	if net, ok := data["network"]; ok {
-		if net != nil {
-			if device.Network == nil {
-				device.Network = &Network{}
-			}
-			err := device.Network.AssignJSON(net)
-			if err != nil {
-				return err
-			}
-		} else {
-			device.Network = nil
-		}
-	}
-
-	// deviceClass
-	if dc, ok := data["deviceClass"]; ok {
-		if dc != nil {
-			if device.DeviceClass == nil {
-				device.DeviceClass = &DeviceClass{}
-			}
-			err := device.DeviceClass.AssignJSON(dc)
-			if err != nil {
-				return err
-			}
-		} else {
-			device.DeviceClass = nil
-		}
-	}
-
-	return nil // OK
+// MarshalJSON() encodes device to JSON.
+// Empty fields are omitted.
+func (device Device) MarshalJSON() ([]byte, error) {
+	encoder := &objectEncoder{}
+	if len(device.Id) != 0 {
+		encoder.put("id", device.Id)
+	}
+	if len(device.Name) != 0 {
+		encoder.put("name", device.Name)
+	}
+	if len(device.Key) != 0 {
+		encoder.put("key", device.Key)
+	}
+	if len(device.Status) != 0 {
+		encoder.put("status", device.Status)
+	}
+	if device.Data != nil {
+		encoder.put("data", device.Data)
+	}
+	if device.Network != nil {
+		encoder.put("network", device.Network)
+	}
+	if device.DeviceClass != nil {
+		encoder.put("deviceClass", device.DeviceClass)
+	}
+	return encoder.finish()
+}
+
+// UnmarshalJSON() decodes device from JSON.
+// Both "id" and "guid" are accepted for device identifier.
+// Existing network and device class objects are updated in place.
+// Data is decoded according to raw JSON mode.
+func (device *Device) UnmarshalJSON(data []byte) (err error) {
+	decoder, err := newObjectDecoder("Device", data)
+	if decoder == nil {
+		return
+	}
+
+	if err = decodeField(decoder, &device.Id, "id", "guid"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &device.Name, "name"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &device.Key, "key"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &device.Status, "status"); err != nil {
+		return
+	}
+	if err = decoder.value(&device.Data, "data"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &device.Network, "network"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &device.DeviceClass, "deviceClass"); err != nil {
+		return
+	}
+
+	return decoder.finish()
+}
+
+// DecodeData() decodes device data into the provided value.
+func (device *Device) DecodeData(v interface{}) error {
+	return DecodeValue(device.Data, v)
+}
+
+// Clone() creates a deep copy of the device
+// including network, device class and equipment.
+func (device *Device) Clone() *Device {
+	if device == nil {
+		return nil
+	}
+
+	c := *device
+	c.Data = cloneValue(device.Data)
+	c.Network = device.Network.Clone()
+	c.DeviceClass = device.DeviceClass.Clone()
+	return &c
+}
+
+// Equal() checks if two devices are the same.
+func (device *Device) Equal(other *Device) bool {
+	return len(device.Diff(other)) == 0
+}
+
+// Diff() reports all the changes from this device to the other one.
+func (device *Device) Diff(other *Device) []Change {
+	if device == nil || other == nil {
+		return diffPresence(nil, "", device, other)
+	}
+
+	var changes []Change
+	changes = diffField(changes, "id", device.Id, other.Id)
+	changes = diffField(changes, "name", device.Name, other.Name)
+	changes = diffField(changes, "key", device.Key, other.Key)
+	changes = diffField(changes, "status", device.Status, other.Status)
+	changes = diffValue(changes, "data", device.Data, other.Data)
+	changes = device.Network.diff(changes, "network", other.Network)
+	changes = device.DeviceClass.diff(changes, "deviceClass", other.DeviceClass)
+	return changes
+}
+
+// Delta() creates a partial device with the fields of the updated device
+// that differ from this one, it's used for partial device updates.
+// Identifier is always kept, nil is returned if nothing is changed.
+// Network and device class are taken as a whole. Emptied fields
+// could not be expressed by a partial update and are ignored.
+func (device *Device) Delta(updated *Device) *Device {
+	delta := &Device{Id: updated.Id}
+	changed := false
+
+	if len(updated.Name) != 0 && updated.Name != device.Name {
+		delta.Name, changed = updated.Name, true
+	}
+	if len(updated.Key) != 0 && updated.Key != device.Key {
+		delta.Key, changed = updated.Key, true
+	}
+	if len(updated.Status) != 0 && updated.Status != device.Status {
+		delta.Status, changed = updated.Status, true
+	}
+	if updated.Data != nil && len(diffValue(nil, "data", device.Data, updated.Data)) != 0 {
+		delta.Data, changed = updated.Data, true
+	}
+	if updated.Network != nil && len(device.Network.diff(nil, "network", updated.Network)) != 0 {
+		delta.Network, changed = updated.Network, true
+	}
+	if updated.DeviceClass != nil && len(device.DeviceClass.diff(nil, "deviceClass", updated.DeviceClass)) != 0 {
+		delta.DeviceClass, changed = updated.DeviceClass, true
+	}
+
+	if !changed {
+		return nil
+	}
+	return delta
+}
+
+// Validate() checks the device fields including network and device class.
+// Identifier and name should not be empty,
+// key should not be longer than MaxDeviceKeyLength characters.
+func (device *Device) Validate() error {
+	v := &validator{}
+	v.required(device.Id, "id")
+	v.required(device.Name, "name")
+	v.check(utf8.RuneCountInString(device.Key) <= MaxDeviceKeyLength, "key",
+		"should not be longer than %d characters", MaxDeviceKeyLength)
+	if device.Network != nil && device.Network.Id == 0 {
+		// network referenced by identifier doesn't need a name
+		device.Network.validate(v, "network")
+	}
+	if device.DeviceClass != nil {
+		device.DeviceClass.validate(v, "deviceClass")
+	}
+	return v.result("Device")
 }
@@ -40,6 +40,28 @@ func (deviceClass *DeviceClass) AddEquipment(equipment ...*Equipment) {
 	deviceClass.Equipment = append(deviceClass.Equipment, equipment...)
 }
 
+// FindEquipment() finds the equipment by code, nil if not found
+func (deviceClass *DeviceClass) FindEquipment(code string) *Equipment {
+	for _, eq := range deviceClass.Equipment {
+		if eq != nil && eq.Code == code {
+			return eq
+		}
+	}
+	return nil
+}
+
+// RemoveEquipment() removes the equipment by code
+// returns removed equipment, nil if not found
+func (deviceClass *DeviceClass) RemoveEquipment(code string) *Equipment {
+	for i, eq := range deviceClass.Equipment {
+		if eq != nil && eq.Code == code {
+			deviceClass.Equipment = append(deviceClass.Equipment[:i:i], deviceClass.Equipment[i+1:]...)
+			return eq
+		}
+	}
+	return nil
+}
+
 // Get DeviceClass string representation
 func (deviceClass DeviceClass) String() string {
 	body := ""
@@ -68,7 +90,7 @@ func (deviceClass DeviceClass) String() string {
 	}
 
 	// Data [optional]
-	body += fmt.Sprintf(", Data:%v", deviceClass.Data)
+	body += fmt.Sprintf(", Data:%s", formatValue(deviceClass.Data))
 
 	// Equipment [optional]
 	if len(deviceClass.Equipment) != 0 {
@@ -78,103 +100,153 @@ func (deviceClass DeviceClass) String() string {
 	return fmt.Sprintf("DeviceClass{%s}", body)
 }
 
-// Assign parsed JSON.
-// This method is used to assign already parsed JSON data.
-func (deviceClass *DeviceClass) AssignJSON(rawData interface{}) error {
-	if rawData == nil {
-		return fmt.Errorf("DeviceClass: no data")
+// MarshalJSON() encodes device class to JSON.
+func (deviceClass DeviceClass) MarshalJSON() ([]byte, error) {
+	encoder := &objectEncoder{}
+	if deviceClass.Id != 0 {
+		encoder.put("id", deviceClass.Id)
+	}
+	encoder.put("name", deviceClass.Name)
+	encoder.put("version", deviceClass.Version)
+	if deviceClass.IsPermanent {
+		encoder.put("isPermanent", deviceClass.IsPermanent)
 	}
+	if deviceClass.OfflineTimeout != 0 {
+		encoder.put("offlineTimeout", deviceClass.OfflineTimeout)
+	}
+	if deviceClass.Data != nil {
+		encoder.put("data", deviceClass.Data)
+	}
+	if len(deviceClass.Equipment) != 0 {
+		encoder.put("equipment", deviceClass.Equipment)
+	}
+	return encoder.finish()
+}
 
-	data, ok := rawData.(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("DeviceClass: %v - unexpected data type", rawData)
+// UnmarshalJSON() decodes device class from JSON.
+// Existing equipment objects are updated in place.
+// Data is decoded according to raw JSON mode.
+func (deviceClass *DeviceClass) UnmarshalJSON(data []byte) (err error) {
+	decoder, err := newObjectDecoder("DeviceClass", data)
+	if decoder == nil {
+		return
 	}
 
-	// identifier
-	if id, ok := data["id"]; ok {
-		switch v := id.(type) {
-		case float64:
-			deviceClass.Id = uint64(v)
-		case uint64:
-			deviceClass.Id = v
-		default:
-			return fmt.Errorf("DeviceClass: %v - unexpected value for id", id)
-		}
+	if err = decoder.uint64(&deviceClass.Id, "id"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &deviceClass.Name, "name"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &deviceClass.Version, "version"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &deviceClass.IsPermanent, "isPermanent", "permanent"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &deviceClass.OfflineTimeout, "offlineTimeout"); err != nil {
+		return
+	}
+	if err = decoder.value(&deviceClass.Data, "data"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &deviceClass.Equipment, "equipment"); err != nil {
+		return
 	}
 
-	// name
-	if name, ok := data["name"]; ok {
-		switch v := name.(type) {
-		case string:
-			deviceClass.Name = v
-		default:
-			return fmt.Errorf("DeviceClass: %v - unexpected value for name", name)
-		}
+	return decoder.finish()
+}
+
+// DecodeData() decodes device class data into the provided value.
+func (deviceClass *DeviceClass) DecodeData(v interface{}) error {
+	return DecodeValue(deviceClass.Data, v)
+}
+
+// Clone() creates a deep copy of the device class including equipment.
+func (deviceClass *DeviceClass) Clone() *DeviceClass {
+	if deviceClass == nil {
+		return nil
 	}
 
-	// version
-	if vs, ok := data["version"]; ok {
-		switch v := vs.(type) {
-		case string:
-			deviceClass.Version = v
-		default:
-			return fmt.Errorf("DeviceClass: %v - unexpected value for version", vs)
+	c := *deviceClass
+	c.Data = cloneValue(deviceClass.Data)
+	if deviceClass.Equipment != nil {
+		c.Equipment = make([]*Equipment, len(deviceClass.Equipment))
+		for i, eq := range deviceClass.Equipment {
+			c.Equipment[i] = eq.Clone()
 		}
 	}
+	return &c
+}
 
-	// is permanent flag
-	if p, ok := data["isPermanent"]; ok {
-		switch v := p.(type) {
-		case bool:
-			deviceClass.IsPermanent = v
-		default:
-			return fmt.Errorf("DeviceClass: %v - unexpected value for isPermanent", p)
-		}
+// Equal() checks if two device classes are the same.
+func (deviceClass *DeviceClass) Equal(other *DeviceClass) bool {
+	return len(deviceClass.Diff(other)) == 0
+}
+
+// Diff() reports all the changes from this device class to the other one.
+// Equipment is compared by position.
+func (deviceClass *DeviceClass) Diff(other *DeviceClass) []Change {
+	return deviceClass.diff(nil, "", other)
+}
+
+// compare device classes
+func (deviceClass *DeviceClass) diff(changes []Change, path string, other *DeviceClass) []Change {
+	if deviceClass == nil || other == nil {
+		return diffPresence(changes, path, deviceClass, other)
 	}
 
-	// offline timeout
-	if ot, ok := data["offlineTimeout"]; ok {
-		switch v := ot.(type) {
-		case float64:
-			deviceClass.OfflineTimeout = int(v)
-		case nil:
-			// do nothing
-		default:
-			return fmt.Errorf("DeviceClass: %v - unexpected value for offlineTimeout", ot)
+	changes = diffField(changes, joinPath(path, "id"), deviceClass.Id, other.Id)
+	changes = diffField(changes, joinPath(path, "name"), deviceClass.Name, other.Name)
+	changes = diffField(changes, joinPath(path, "version"), deviceClass.Version, other.Version)
+	changes = diffField(changes, joinPath(path, "isPermanent"), deviceClass.IsPermanent, other.IsPermanent)
+	changes = diffField(changes, joinPath(path, "offlineTimeout"), deviceClass.OfflineTimeout, other.OfflineTimeout)
+	changes = diffValue(changes, joinPath(path, "data"), deviceClass.Data, other.Data)
+
+	n := len(deviceClass.Equipment)
+	if len(other.Equipment) > n {
+		n = len(other.Equipment)
+	}
+	for i := 0; i < n; i++ {
+		var a, b *Equipment
+		if i < len(deviceClass.Equipment) {
+			a = deviceClass.Equipment[i]
+		}
+		if i < len(other.Equipment) {
+			b = other.Equipment[i]
 		}
+		changes = a.diff(changes, fmt.Sprintf("%s[%d]", joinPath(path, "equipment"), i), b)
 	}
+	return changes
+}
 
-	// data (as is)
-	if d, ok := data["data"]; ok {
-		deviceClass.Data = d
-	}
-
-	// equipment
-	if e, ok := data["equipment"]; ok {
-		switch v := e.(type) {
-		case []interface{}:
-			for i, x := range v {
-				if i < len(deviceClass.Equipment) {
-					// update existing
-					err := deviceClass.Equipment[i].AssignJSON(x)
-					if err != nil {
-						return err
-					}
-				} else {
-					// create new
-					tmp := &Equipment{}
-					err := tmp.AssignJSON(x)
-					if err != nil {
-						return err
-					}
-					deviceClass.AddEquipment(tmp)
-				}
-			}
-			deviceClass.Equipment = deviceClass.Equipment[0:len(v)]
-		default:
-			return fmt.Errorf("DeviceClass: %v - unexpected value for equipment", e)
+// Validate() checks the device class fields.
+// Name and version should not be empty,
+// equipment codes should be unique within the device class.
+func (deviceClass *DeviceClass) Validate() error {
+	v := &validator{}
+	deviceClass.validate(v, "")
+	return v.result("DeviceClass")
+}
+
+// validate device class fields
+func (deviceClass *DeviceClass) validate(v *validator, path string) {
+	v.required(deviceClass.Name, joinPath(path, "name"))
+	v.required(deviceClass.Version, joinPath(path, "version"))
+
+	codes := make(map[string]int)
+	for i, eq := range deviceClass.Equipment {
+		field := fmt.Sprintf("%s[%d]", joinPath(path, "equipment"), i)
+		if eq == nil {
+			v.check(false, field, "should not be null")
+			continue
 		}
-	}
+		eq.validate(v, field)
 
-	return nil // OK
+		if first, ok := codes[eq.Code]; ok && len(eq.Code) != 0 {
+			v.check(false, joinPath(field, "code"), "duplicates equipment[%d].code %q", first, eq.Code)
+		} else {
+			codes[eq.Code] = i
+		}
+	}
 }
@@ -46,69 +46,104 @@ func (equipment Equipment) String() string {
 	body += fmt.Sprintf(", Type:%q", equipment.Type)
 
 	// Data [optional]
-	body += fmt.Sprintf(", Data:%v", equipment.Data)
+	body += fmt.Sprintf(", Data:%s", formatValue(equipment.Data))
 
 	return fmt.Sprintf("Equipment{%s}", body)
 }
 
-// Assign parsed JSON.
-// This method is used to assign already parsed JSON data.
-func (equipment *Equipment) AssignJSON(rawData interface{}) error {
-	if rawData == nil {
-		return fmt.Errorf("Equipment: no data")
+// MarshalJSON() encodes equipment to JSON.
+func (equipment Equipment) MarshalJSON() ([]byte, error) {
+	encoder := &objectEncoder{}
+	if equipment.Id != 0 {
+		encoder.put("id", equipment.Id)
 	}
-
-	data, ok := rawData.(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("Equipment: %v - unexpected data type", rawData)
+	encoder.put("name", equipment.Name)
+	encoder.put("code", equipment.Code)
+	encoder.put("type", equipment.Type)
+	if equipment.Data != nil {
+		encoder.put("data", equipment.Data)
 	}
+	return encoder.finish()
+}
 
-	// identifier
-	if id, ok := data["id"]; ok {
-		switch v := id.(type) {
-		case float64:
-			equipment.Id = uint64(v)
-		case uint64:
-			equipment.Id = v
-		default:
-			return fmt.Errorf("Equipment: %v - unexpected value for id", id)
-		}
+// UnmarshalJSON() decodes equipment from JSON.
+// Data is decoded according to raw JSON mode.
+func (equipment *Equipment) UnmarshalJSON(data []byte) (err error) {
+	decoder, err := newObjectDecoder("Equipment", data)
+	if decoder == nil {
+		return
 	}
 
-	// name
-	if name, ok := data["name"]; ok {
-		switch v := name.(type) {
-		case string:
-			equipment.Name = v
-		default:
-			return fmt.Errorf("Equipment: %v - unexpected value for name", name)
-		}
+	if err = decoder.uint64(&equipment.Id, "id"); err != nil {
+		return
 	}
-
-	// code
-	if code, ok := data["code"]; ok {
-		switch v := code.(type) {
-		case string:
-			equipment.Code = v
-		default:
-			return fmt.Errorf("Equipment: %v - unexpected value for code", code)
-		}
+	if err = decodeField(decoder, &equipment.Name, "name"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &equipment.Code, "code"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &equipment.Type, "type"); err != nil {
+		return
 	}
+	if err = decoder.value(&equipment.Data, "data"); err != nil {
+		return
+	}
+
+	return decoder.finish()
+}
+
+// DecodeData() decodes equipment data into the provided value.
+func (equipment *Equipment) DecodeData(v interface{}) error {
+	return DecodeValue(equipment.Data, v)
+}
 
-	// type
-	if t, ok := data["type"]; ok {
-		switch v := t.(type) {
-		case string:
-			equipment.Type = v
-		default:
-			return fmt.Errorf("Equipment: %v - unexpected value for type", t)
-		}
+// Clone() creates a deep copy of the equipment.
+func (equipment *Equipment) Clone() *Equipment {
+	if equipment == nil {
+		return nil
 	}
 
-	// data (as is)
-	if d, ok := data["data"]; ok {
-		equipment.Data = d
+	c := *equipment
+	c.Data = cloneValue(equipment.Data)
+	return &c
+}
+
+// Equal() checks if two equipment objects are the same.
+func (equipment *Equipment) Equal(other *Equipment) bool {
+	return len(equipment.Diff(other)) == 0
+}
+
+// Diff() reports all the changes from this equipment to the other one.
+func (equipment *Equipment) Diff(other *Equipment) []Change {
+	return equipment.diff(nil, "", other)
+}
+
+// compare equipment objects
+func (equipment *Equipment) diff(changes []Change, path string, other *Equipment) []Change {
+	if equipment == nil || other == nil {
+		return diffPresence(changes, path, equipment, other)
 	}
 
-	return nil // OK
+	changes = diffField(changes, joinPath(path, "id"), equipment.Id, other.Id)
+	changes = diffField(changes, joinPath(path, "name"), equipment.Name, other.Name)
+	changes = diffField(changes, joinPath(path, "code"), equipment.Code, other.Code)
+	changes = diffField(changes, joinPath(path, "type"), equipment.Type, other.Type)
+	changes = diffValue(changes, joinPath(path, "data"), equipment.Data, other.Data)
+	return changes
+}
+
+// Validate() checks the equipment fields.
+// Name, code and type should not be empty.
+func (equipment *Equipment) Validate() error {
+	v := &validator{}
+	equipment.validate(v, "")
+	return v.result("Equipment")
+}
+
+// validate equipment fields
+func (equipment *Equipment) validate(v *validator, path string) {
+	v.required(equipment.Name, joinPath(path, "name"))
+	v.required(equipment.Code, joinPath(path, "code"))
+	v.required(equipment.Type, joinPath(path, "type"))
 }
@@ -0,0 +1,295 @@
+package core
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// Sort orders.
+const (
+	SortAsc  = "ASC"
+	SortDesc = "DESC"
+)
+
+// Device list filter.
+// Empty or zero fields are not used.
+type DeviceFilter struct {
+	// Exact device name.
+	Name string
+
+	// Device name pattern, "%" matches any substring.
+	NamePattern string
+
+	// Device status.
+	Status string
+
+	// Network identifier.
+	NetworkId uint64
+
+	// Device class identifier.
+	DeviceClassId uint64
+
+	// Field to sort by: "name", "status", "network" or "deviceClass".
+	SortField string
+
+	// Sort order: SortAsc or SortDesc.
+	SortOrder string
+
+	// Maximum number of devices, zero means server's default.
+	Take int
+
+	// Number of devices to skip.
+	Skip int
+}
+
+// Query() converts filter to URL query parameters.
+func (filter *DeviceFilter) Query() url.Values {
+	query := url.Values{}
+	if filter == nil {
+		return query
+	}
+
+	setQuery(query, "name", filter.Name)
+	setQuery(query, "namePattern", filter.NamePattern)
+	setQuery(query, "status", filter.Status)
+	if filter.NetworkId != 0 {
+		query.Set("networkId", strconv.FormatUint(filter.NetworkId, 10))
+	}
+	if filter.DeviceClassId != 0 {
+		query.Set("deviceClassId", strconv.FormatUint(filter.DeviceClassId, 10))
+	}
+	setQuery(query, "sortField", filter.SortField)
+	setQuery(query, "sortOrder", filter.SortOrder)
+	setPaging(query, filter.Take, filter.Skip)
+	return query
+}
+
+// Network list filter.
+// Empty or zero fields are not used.
+type NetworkFilter struct {
+	// Exact network name.
+	Name string
+
+	// Network name pattern, "%" matches any substring.
+	NamePattern string
+
+	// Field to sort by: "id" or "name".
+	SortField string
+
+	// Sort order: SortAsc or SortDesc.
+	SortOrder string
+
+	// Maximum number of networks, zero means server's default.
+	Take int
+
+	// Number of networks to skip.
+	Skip int
+}
+
+// Query() converts filter to URL query parameters.
+func (filter *NetworkFilter) Query() url.Values {
+	query := url.Values{}
+	if filter == nil {
+		return query
+	}
+
+	setQuery(query, "name", filter.Name)
+	setQuery(query, "namePattern", filter.NamePattern)
+	setQuery(query, "sortField", filter.SortField)
+	setQuery(query, "sortOrder", filter.SortOrder)
+	setPaging(query, filter.Take, filter.Skip)
+	return query
+}
+
+// Device class list filter.
+// Empty or zero fields are not used.
+type DeviceClassFilter struct {
+	// Exact device class name.
+	Name string
+
+	// Device class name pattern, "%" matches any substring.
+	NamePattern string
+
+	// Exact device class version.
+	Version string
+
+	// Field to sort by: "id", "name" or "version".
+	SortField string
+
+	// Sort order: SortAsc or SortDesc.
+	SortOrder string
+
+	// Maximum number of device classes, zero means server's default.
+	Take int
+
+	// Number of device classes to skip.
+	Skip int
+}
+
+// Query() converts filter to URL query parameters.
+func (filter *DeviceClassFilter) Query() url.Values {
+	query := url.Values{}
+	if filter == nil {
+		return query
+	}
+
+	setQuery(query, "name", filter.Name)
+	setQuery(query, "namePattern", filter.NamePattern)
+	setQuery(query, "version", filter.Version)
+	setQuery(query, "sortField", filter.SortField)
+	setQuery(query, "sortOrder", filter.SortOrder)
+	setPaging(query, filter.Take, filter.Skip)
+	return query
+}
+
+// User list filter.
+// Empty, zero or nil fields are not used.
+type UserFilter struct {
+	// Exact user login.
+	Login string
+
+	// User login pattern, "%" matches any substring.
+	LoginPattern string
+
+	// User role [optional].
+	Role *UserRole
+
+	// User status [optional].
+	Status *UserStatus
+
+	// Field to sort by: "id", "login", "role", "status" or "lastLogin".
+	SortField string
+
+	// Sort order: SortAsc or SortDesc.
+	SortOrder string
+
+	// Maximum number of users, zero means server's default.
+	Take int
+
+	// Number of users to skip.
+	Skip int
+}
+
+// Query() converts filter to URL query parameters.
+func (filter *UserFilter) Query() url.Values {
+	query := url.Values{}
+	if filter == nil {
+		return query
+	}
+
+	setQuery(query, "login", filter.Login)
+	setQuery(query, "loginPattern", filter.LoginPattern)
+	if filter.Role != nil {
+		query.Set("role", strconv.Itoa(int(*filter.Role)))
+	}
+	if filter.Status != nil {
+		query.Set("status", strconv.Itoa(int(*filter.Status)))
+	}
+	setQuery(query, "sortField", filter.SortField)
+	setQuery(query, "sortOrder", filter.SortOrder)
+	setPaging(query, filter.Take, filter.Skip)
+	return query
+}
+
+// Command history filter.
+// Empty or zero fields are not used.
+type CommandFilter struct {
+	// Start timestamp, commands created after it are returned.
+	Start Timestamp
+
+	// End timestamp, commands created before it are returned.
+	End Timestamp
+
+	// Exact command name.
+	Command string
+
+	// Command status.
+	Status string
+
+	// Field to sort by: "timestamp", "command" or "status".
+	SortField string
+
+	// Sort order: SortAsc or SortDesc.
+	SortOrder string
+
+	// Maximum number of commands, zero means server's default.
+	Take int
+
+	// Number of commands to skip.
+	Skip int
+}
+
+// Query() converts filter to URL query parameters.
+func (filter *CommandFilter) Query() url.Values {
+	query := url.Values{}
+	if filter == nil {
+		return query
+	}
+
+	setQuery(query, "start", filter.Start.String())
+	setQuery(query, "end", filter.End.String())
+	setQuery(query, "command", filter.Command)
+	setQuery(query, "status", filter.Status)
+	setQuery(query, "sortField", filter.SortField)
+	setQuery(query, "sortOrder", filter.SortOrder)
+	setPaging(query, filter.Take, filter.Skip)
+	return query
+}
+
+// Notification history filter.
+// Empty or zero fields are not used.
+type NotificationFilter struct {
+	// Start timestamp, notifications created after it are returned.
+	Start Timestamp
+
+	// End timestamp, notifications created before it are returned.
+	End Timestamp
+
+	// Exact notification name.
+	Notification string
+
+	// Field to sort by: "timestamp" or "notification".
+	SortField string
+
+	// Sort order: SortAsc or SortDesc.
+	SortOrder string
+
+	// Maximum number of notifications, zero means server's default.
+	Take int
+
+	// Number of notifications to skip.
+	Skip int
+}
+
+// Query() converts filter to URL query parameters.
+func (filter *NotificationFilter) Query() url.Values {
+	query := url.Values{}
+	if filter == nil {
+		return query
+	}
+
+	setQuery(query, "start", filter.Start.String())
+	setQuery(query, "end", filter.End.String())
+	setQuery(query, "notification", filter.Notification)
+	setQuery(query, "sortField", filter.SortField)
+	setQuery(query, "sortOrder", filter.SortOrder)
+	setPaging(query, filter.Take, filter.Skip)
+	return query
+}
+
+// set non-empty query parameter
+func setQuery(query url.Values, name, value string) {
+	if len(value) != 0 {
+		query.Set(name, value)
+	}
+}
+
+// set paging query parameters
+func setPaging(query url.Values, take, skip int) {
+	if take > 0 {
+		query.Set("take", strconv.Itoa(take))
+	}
+	if skip > 0 {
+		query.Set("skip", strconv.Itoa(skip))
+	}
+}
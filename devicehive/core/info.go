@@ -8,7 +8,7 @@ type ServerInfo struct {
 	Version string `json:"apiVersion"`
 
 	// Current server time, UTC.
-	Timestamp string `json:"serverTimestamp"`
+	Timestamp Timestamp `json:"serverTimestamp"`
 
 	// Alternative websocket URL. Empty for Websocket service.
 	WebsocketUrl string `json:"webSocketServerUrl,omitempty"`
@@ -41,61 +41,39 @@ func (info ServerInfo) String() string {
 		info.Version, info.Timestamp)
 }
 
-// Assign parsed JSON.
-// This method is used to assign already parsed JSON data.
-func (info *ServerInfo) AssignJSON(rawData interface{}) error {
-	if rawData == nil {
-		return fmt.Errorf("ServerInfo: no data")
+// MarshalJSON() encodes server information to JSON.
+func (info ServerInfo) MarshalJSON() ([]byte, error) {
+	encoder := &objectEncoder{}
+	encoder.put("apiVersion", info.Version)
+	encoder.put("serverTimestamp", info.Timestamp)
+	if len(info.WebsocketUrl) != 0 {
+		encoder.put("webSocketServerUrl", info.WebsocketUrl)
 	}
-
-	data, ok := rawData.(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("ServerInfo: %v - unexpected data type", rawData)
+	if len(info.RestUrl) != 0 {
+		encoder.put("restServerUrl", info.RestUrl)
 	}
+	return encoder.finish()
+}
 
-	// version
-	if av, ok := data["apiVersion"]; ok {
-		switch v := av.(type) {
-		case string:
-			info.Version = v
-		default:
-			return fmt.Errorf("ServerInfo: %v - unexpected value for version", av)
-		}
+// UnmarshalJSON() decodes server information from JSON.
+func (info *ServerInfo) UnmarshalJSON(data []byte) (err error) {
+	decoder, err := newObjectDecoder("ServerInfo", data)
+	if decoder == nil {
+		return
 	}
 
-	// timestamp
-	if ts, ok := data["serverTimestamp"]; ok {
-		switch v := ts.(type) {
-		case string:
-			info.Timestamp = v
-		default:
-			return fmt.Errorf("ServerInfo: %v - unexpected value for timestamp", ts)
-		}
+	if err = decodeField(decoder, &info.Version, "apiVersion"); err != nil {
+		return
 	}
-
-	// websocket URL
-	if ws, ok := data["webSocketServerUrl"]; ok {
-		switch v := ws.(type) {
-		case string:
-			info.WebsocketUrl = v
-		case nil:
-			// do nothing
-		default:
-			return fmt.Errorf("ServerInfo: %v - unexpected value for websocketUrl", ws)
-		}
+	if err = decodeField(decoder, &info.Timestamp, "serverTimestamp"); err != nil {
+		return
 	}
-
-	// rest URL
-	if rest, ok := data["restServerUrl"]; ok {
-		switch v := rest.(type) {
-		case string:
-			info.RestUrl = v
-		case nil:
-			// do nothing
-		default:
-			return fmt.Errorf("ServerInfo: %v - unexpected value for restUrl", rest)
-		}
+	if err = decodeField(decoder, &info.WebsocketUrl, "webSocketServerUrl", "websocketServerUrl"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &info.RestUrl, "restServerUrl"); err != nil {
+		return
 	}
 
-	return nil // OK
+	return decoder.finish()
 }
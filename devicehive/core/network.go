@@ -39,7 +39,7 @@ func (network Network) String() string {
 
 	// Key [optional]
 	if len(network.Key) != 0 {
-		body += fmt.Sprintf(", Key:%q", network.Key)
+		body += fmt.Sprintf(", Key:%q", RedactSecret(network.Key))
 	}
 
 	// Description [optional]
@@ -50,65 +50,87 @@ func (network Network) String() string {
 	return fmt.Sprintf("Network{%s}", body)
 }
 
-// Assign parsed JSON.
-// This method is used to assign already parsed JSON data.
-func (network *Network) AssignJSON(rawData interface{}) error {
-	if rawData == nil {
-		return fmt.Errorf("Network: no data")
+// MarshalJSON() encodes network to JSON.
+func (network Network) MarshalJSON() ([]byte, error) {
+	encoder := &objectEncoder{}
+	if network.Id != 0 {
+		encoder.put("id", network.Id)
 	}
-
-	data, ok := rawData.(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("Network: %v - unexpected data type", rawData)
+	encoder.put("name", network.Name)
+	if len(network.Key) != 0 {
+		encoder.put("key", network.Key)
 	}
+	if len(network.Description) != 0 {
+		encoder.put("description", network.Description)
+	}
+	return encoder.finish()
+}
 
-	// identifier
-	if id, ok := data["id"]; ok {
-		switch v := id.(type) {
-		case float64:
-			network.Id = uint64(v)
-		case uint64:
-			network.Id = v
-		default:
-			return fmt.Errorf("Network: %v - unexpected value for id", id)
-		}
+// UnmarshalJSON() decodes network from JSON.
+func (network *Network) UnmarshalJSON(data []byte) (err error) {
+	decoder, err := newObjectDecoder("Network", data)
+	if decoder == nil {
+		return
 	}
 
-	// name
-	if name, ok := data["name"]; ok {
-		switch v := name.(type) {
-		case string:
-			network.Name = v
-		case nil:
-			network.Name = ""
-		default:
-			return fmt.Errorf("Network: %v - unexpected value for name", name)
-		}
+	if err = decoder.uint64(&network.Id, "id"); err != nil {
+		return
 	}
+	if err = decodeField(decoder, &network.Name, "name"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &network.Key, "key"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &network.Description, "description"); err != nil {
+		return
+	}
+
+	return decoder.finish()
+}
 
-	// key
-	if key, ok := data["key"]; ok {
-		switch v := key.(type) {
-		case string:
-			network.Key = v
-		case nil:
-			network.Key = ""
-		default:
-			return fmt.Errorf("Network: %v - unexpected value for key", key)
-		}
+// Clone() creates a copy of the network.
+func (network *Network) Clone() *Network {
+	if network == nil {
+		return nil
 	}
 
-	// description
-	if d, ok := data["description"]; ok {
-		switch v := d.(type) {
-		case string:
-			network.Description = v
-		case nil:
-			network.Description = ""
-		default:
-			return fmt.Errorf("Network: %v - unexpected value for description", d)
-		}
+	c := *network
+	return &c
+}
+
+// Equal() checks if two networks are the same.
+func (network *Network) Equal(other *Network) bool {
+	return len(network.Diff(other)) == 0
+}
+
+// Diff() reports all the changes from this network to the other one.
+func (network *Network) Diff(other *Network) []Change {
+	return network.diff(nil, "", other)
+}
+
+// compare networks
+func (network *Network) diff(changes []Change, path string, other *Network) []Change {
+	if network == nil || other == nil {
+		return diffPresence(changes, path, network, other)
 	}
 
-	return nil // OK
+	changes = diffField(changes, joinPath(path, "id"), network.Id, other.Id)
+	changes = diffField(changes, joinPath(path, "name"), network.Name, other.Name)
+	changes = diffField(changes, joinPath(path, "key"), network.Key, other.Key)
+	changes = diffField(changes, joinPath(path, "description"), network.Description, other.Description)
+	return changes
+}
+
+// Validate() checks the network fields.
+// Name should not be empty.
+func (network *Network) Validate() error {
+	v := &validator{}
+	network.validate(v, "")
+	return v.result("Network")
+}
+
+// validate network fields
+func (network *Network) validate(v *validator, path string) {
+	v.required(network.Name, joinPath(path, "name"))
 }
@@ -8,7 +8,7 @@ type Notification struct {
 	Id uint64 `json:"id,omitempty"`
 
 	// Timestamp, UTC.
-	Timestamp string `json:"timestamp,omitempty"`
+	Timestamp Timestamp `json:"timestamp,omitzero"`
 
 	// Notification name.
 	Name string `json:"notification,omitempty"`
@@ -47,66 +47,103 @@ func (notification Notification) String() string {
 	body += fmt.Sprintf("Name:%q", notification.Name)
 
 	// Timestamp
-	if len(notification.Timestamp) != 0 {
+	if !notification.Timestamp.IsZero() {
 		body += fmt.Sprintf(", Timestamp:%q", notification.Timestamp)
 	}
 
 	// Parameters [optional]
 	if notification.Parameters != nil {
-		body += fmt.Sprintf(", Parameters:%v", notification.Parameters)
+		body += fmt.Sprintf(", Parameters:%s", formatValue(notification.Parameters))
 	}
 
 	return fmt.Sprintf("Notification{%s}", body)
 }
 
-// Assign parsed JSON.
-// This method is used to assign already parsed JSON data.
-func (notification *Notification) AssignJSON(rawData interface{}) error {
-	if rawData == nil {
-		return fmt.Errorf("Notification: no data")
+// MarshalJSON() encodes notification to JSON.
+// Empty fields are omitted.
+func (notification Notification) MarshalJSON() ([]byte, error) {
+	encoder := &objectEncoder{}
+	if notification.Id != 0 {
+		encoder.put("id", notification.Id)
 	}
-
-	data, ok := rawData.(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("Notification: %v - unexpected data type", rawData)
+	if !notification.Timestamp.IsZero() {
+		encoder.put("timestamp", notification.Timestamp)
+	}
+	if len(notification.Name) != 0 {
+		encoder.put("notification", notification.Name)
+	}
+	if notification.Parameters != nil {
+		encoder.put("parameters", notification.Parameters)
 	}
+	return encoder.finish()
+}
 
-	// identifier
-	if id, ok := data["id"]; ok {
-		switch v := id.(type) {
-		case float64:
-			notification.Id = uint64(v)
-		case uint64:
-			notification.Id = v
-		default:
-			return fmt.Errorf("Notification: %v - unexpected value for id", id)
-		}
+// UnmarshalJSON() decodes notification from JSON.
+// Both "notification" and "name" are accepted for notification name.
+// Parameters are decoded according to raw JSON mode.
+func (notification *Notification) UnmarshalJSON(data []byte) (err error) {
+	decoder, err := newObjectDecoder("Notification", data)
+	if decoder == nil {
+		return
 	}
 
-	// timestamp
-	if ts, ok := data["timestamp"]; ok {
-		switch v := ts.(type) {
-		case string:
-			notification.Timestamp = v
-		default:
-			return fmt.Errorf("Notification: %v - unexpected value for timestamp", ts)
-		}
+	if err = decoder.uint64(&notification.Id, "id"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &notification.Timestamp, "timestamp"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &notification.Name, "notification", "name"); err != nil {
+		return
+	}
+	if err = decoder.value(&notification.Parameters, "parameters"); err != nil {
+		return
 	}
 
-	// name
-	if name, ok := data["name"]; ok {
-		switch v := name.(type) {
-		case string:
-			notification.Name = v
-		default:
-			return fmt.Errorf("Notification: %v - unexpected value for name", name)
-		}
+	// known server fields, not modelled
+	decoder.ignore("deviceGuid", "deviceId", "networkId")
+	return decoder.finish()
+}
+
+// DecodeParameters() decodes notification parameters into the provided value.
+func (notification *Notification) DecodeParameters(v interface{}) error {
+	return DecodeValue(notification.Parameters, v)
+}
+
+// Clone() creates a deep copy of the notification.
+func (notification *Notification) Clone() *Notification {
+	if notification == nil {
+		return nil
 	}
 
-	// parameters (as is)
-	if p, ok := data["parameters"]; ok {
-		notification.Parameters = p
+	c := *notification
+	c.Parameters = cloneValue(notification.Parameters)
+	return &c
+}
+
+// Equal() checks if two notifications are the same.
+func (notification *Notification) Equal(other *Notification) bool {
+	return len(notification.Diff(other)) == 0
+}
+
+// Diff() reports all the changes from this notification to the other one.
+func (notification *Notification) Diff(other *Notification) []Change {
+	if notification == nil || other == nil {
+		return diffPresence(nil, "", notification, other)
 	}
 
-	return nil // OK
+	var changes []Change
+	changes = diffField(changes, "id", notification.Id, other.Id)
+	changes = diffTimestamp(changes, "timestamp", notification.Timestamp, other.Timestamp)
+	changes = diffField(changes, "notification", notification.Name, other.Name)
+	changes = diffValue(changes, "parameters", notification.Parameters, other.Parameters)
+	return changes
+}
+
+// Validate() checks the notification fields.
+// Name should not be empty.
+func (notification *Notification) Validate() error {
+	v := &validator{}
+	v.required(notification.Name, "notification")
+	return v.result("Notification")
 }
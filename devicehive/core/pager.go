@@ -0,0 +1,122 @@
+package core
+
+import "time"
+
+// Default number of items requested per page.
+const DefaultPageSize = 100
+
+// PageFunc fetches a page of items.
+type PageFunc[T any] func(take, skip int, timeout time.Duration) ([]T, error)
+
+// Pager is a lazy page iterator shared by transports.
+// Pages are requested only when previous one is exhausted.
+type Pager[T any] struct {
+	fetch    PageFunc[T]
+	pageSize int
+	timeout  time.Duration
+
+	skip  int // offset of the next page
+	limit int // remaining number of items, negative means no limit
+
+	page []T
+	pos  int // position of the current item + 1
+	err  error
+}
+
+// NewPager creates a new page iterator.
+// Take is the total number of items, zero means all items.
+// Zero pageSize means DefaultPageSize, timeout is used for each page request.
+func NewPager[T any](fetch PageFunc[T], take, skip, pageSize int, timeout time.Duration) Pager[T] {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	limit := take
+	if limit <= 0 {
+		limit = -1
+	}
+	return Pager[T]{fetch: fetch, pageSize: pageSize,
+		timeout: timeout, skip: skip, limit: limit}
+}
+
+// Next() advances to the next item, fetches next page if needed.
+// Returns false if there are no more items (an empty page is received,
+// or the total number of items is taken) or an error occurred.
+func (p *Pager[T]) Next() bool {
+	if p.err != nil {
+		return false
+	}
+	if p.pos < len(p.page) {
+		p.pos++
+		return true
+	}
+	if p.limit == 0 {
+		p.page, p.pos = nil, 0
+		return false
+	}
+
+	take := p.pageSize
+	if p.limit > 0 && p.limit < take {
+		take = p.limit
+	}
+	p.page, p.err = p.fetch(take, p.skip, p.timeout)
+	if p.err != nil {
+		p.page, p.pos = nil, 0
+		return false
+	}
+	if len(p.page) > take {
+		p.page = p.page[:take] // should not happen
+	}
+
+	// server may limit page size, so only an empty page is the last one
+	p.skip += len(p.page)
+	if p.limit > 0 {
+		p.limit -= len(p.page)
+	}
+
+	p.pos = 0
+	if len(p.page) == 0 {
+		return false
+	}
+	p.pos++
+	return true
+}
+
+// Current() gets the current item, nil if there is no one.
+func (p *Pager[T]) Current() *T {
+	if p.pos == 0 || p.pos > len(p.page) {
+		return nil
+	}
+	return &p.page[p.pos-1]
+}
+
+// Err() gets the error occurred, if any.
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// CommandIterator walks the command history page by page.
+//
+//	it := service.IterateCommands(device, filter, 0, timeout)
+//	for it.Next() {
+//		command := it.Command()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type CommandIterator struct {
+	Pager[Command]
+}
+
+// Command() gets the current command.
+func (it *CommandIterator) Command() *Command {
+	return it.Current()
+}
+
+// NotificationIterator walks the notification history page by page.
+type NotificationIterator struct {
+	Pager[Notification]
+}
+
+// Notification() gets the current notification.
+func (it *NotificationIterator) Notification() *Notification {
+	return it.Current()
+}
@@ -0,0 +1,135 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// Replacement for all redacted secrets.
+const Redacted = "***"
+
+// JSON fields considered as secrets.
+var SecretFields = []string{"accessKey", "deviceKey", "key", "password"}
+
+// HTTP headers considered as secrets.
+var SecretHeaders = []string{"Authorization", "Auth-DeviceKey"}
+
+// reveal secrets mode, see SetRevealSecrets()
+var revealSecrets atomic.Bool
+
+// SetRevealSecrets() enables or disables secret revealing.
+//
+// By default all secrets (access keys, device and network keys, passwords)
+// are redacted in String() representations and in log output.
+// Revealing is intended for local debugging only.
+// The setting is process-wide and safe to change at any time.
+func SetRevealSecrets(enable bool) {
+	revealSecrets.Store(enable)
+}
+
+// IsRevealSecrets() checks if secrets are revealed.
+func IsRevealSecrets() bool {
+	return revealSecrets.Load()
+}
+
+// RedactSecret() hides the secret unless secrets are revealed.
+// Empty secret is returned as is.
+func RedactSecret(secret string) string {
+	if revealSecrets.Load() || len(secret) == 0 {
+		return secret
+	}
+	return Redacted
+}
+
+// RedactJSON() hides secret fields of JSON data unless secrets are revealed.
+// Data is returned as is if it's not a valid JSON.
+func RedactJSON(data []byte) string {
+	if revealSecrets.Load() {
+		return string(data)
+	}
+	return RedactJSONFields(data, SecretFields)
+}
+
+// RedactJSONFields() hides the given fields of JSON data (case insensitive).
+// Unlike RedactJSON() the reveal secrets mode is ignored.
+// Data is returned as is if it's not a valid JSON.
+func RedactJSONFields(data []byte, fields []string) string {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return string(data)
+	}
+	if !redactValue(v, fields) {
+		return string(data) // nothing changed
+	}
+
+	res, err := json.Marshal(v)
+	if err != nil {
+		return string(data)
+	}
+	return string(res)
+}
+
+// redact secret fields recursively
+// return true if anything is changed
+func redactValue(data interface{}, fields []string) (changed bool) {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			if isSecretField(key, fields) {
+				if s, ok := val.(string); ok && len(s) != 0 {
+					v[key] = Redacted
+					changed = true
+				}
+			} else if redactValue(val, fields) {
+				changed = true
+			}
+		}
+
+	case []interface{}:
+		for _, val := range v {
+			if redactValue(val, fields) {
+				changed = true
+			}
+		}
+	}
+
+	return
+}
+
+// check if the JSON field is secret
+func isSecretField(name string, fields []string) bool {
+	for _, f := range fields {
+		if strings.EqualFold(f, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactHeader() copies HTTP headers with the given headers hidden.
+// Authorization scheme is kept, empty values are kept as is.
+// Unlike RedactSecret() the reveal secrets mode is ignored.
+func RedactHeader(header http.Header, names []string) http.Header {
+	res := make(http.Header, len(header))
+	for k, v := range header {
+		res[k] = append([]string(nil), v...)
+	}
+	for _, name := range names {
+		values := res[http.CanonicalHeaderKey(name)]
+		for i, v := range values {
+			if p := strings.IndexByte(v, ' '); p > 0 {
+				values[i] = v[0:p+1] + Redacted
+			} else if len(v) != 0 {
+				values[i] = Redacted
+			}
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// Timestamp layout used for formatting, microseconds precision
+	TimestampLayout = "2006-01-02T15:04:05.999999"
+)
+
+// Layouts accepted while parsing timestamps.
+// Timestamps without zone are considered as UTC.
+var timestampLayouts = []string{
+	time.RFC3339Nano,                // with zone
+	"2006-01-02T15:04:05.999999999", // with or without fractional seconds
+	"2006-01-02 15:04:05.999999999", // space separated
+	"2006-01-02",                    // date only
+}
+
+// Represents DeviceHive timestamp, UTC.
+// Zero value means no timestamp.
+type Timestamp struct {
+	time.Time
+}
+
+// NewTimestamp creates a new timestamp (converted to UTC).
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Time: t.UTC()}
+}
+
+// ParseTimestamp parses a timestamp in DeviceHive format.
+// Empty string gives zero timestamp.
+func ParseTimestamp(s string) (ts Timestamp, err error) {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return // zero timestamp
+	}
+
+	for _, layout := range timestampLayouts {
+		t, e := time.Parse(layout, s)
+		if e == nil {
+			return NewTimestamp(t), nil
+		}
+	}
+
+	err = fmt.Errorf("Timestamp: %q - unexpected format", s)
+	return
+}
+
+// MustParseTimestamp is the same as ParseTimestamp but panics on error.
+// It's useful for timestamp literals.
+func MustParseTimestamp(s string) Timestamp {
+	ts, err := ParseTimestamp(s)
+	if err != nil {
+		panic(err)
+	}
+	return ts
+}
+
+// Get Timestamp string representation in DeviceHive format.
+// Empty string for zero timestamp.
+func (ts Timestamp) String() string {
+	if ts.IsZero() {
+		return ""
+	}
+	return ts.UTC().Format(TimestampLayout)
+}
+
+// MarshalJSON() encodes timestamp as a string in DeviceHive format.
+// Zero timestamp is encoded as null.
+func (ts Timestamp) MarshalJSON() ([]byte, error) {
+	if ts.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(ts.String())
+}
+
+// UnmarshalJSON() decodes timestamp from a string.
+// null or empty string gives zero timestamp.
+func (ts *Timestamp) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*ts = Timestamp{}
+		return nil
+	}
+
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return fmt.Errorf("Timestamp: %s - unexpected value", string(data))
+	}
+
+	*ts, err = ParseTimestamp(s)
+	return err
+}
+
+// MarshalText() encodes timestamp in DeviceHive format,
+// the same as String(). It overrides time.Time.MarshalText(),
+// so text encoders (map keys, query values) use the same layout as JSON.
+func (ts Timestamp) MarshalText() ([]byte, error) {
+	return []byte(ts.String()), nil
+}
+
+// AppendText() appends timestamp in DeviceHive format.
+// It overrides time.Time.AppendText() which newer encoders prefer.
+func (ts Timestamp) AppendText(b []byte) ([]byte, error) {
+	return append(b, ts.String()...), nil
+}
+
+// UnmarshalText() decodes timestamp the same way as ParseTimestamp().
+// Empty text gives zero timestamp.
+func (ts *Timestamp) UnmarshalText(data []byte) (err error) {
+	*ts, err = ParseTimestamp(string(data))
+	return
+}
@@ -0,0 +1,223 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// User role.
+type UserRole int
+
+// User roles.
+const (
+	UserRoleAdmin  UserRole = 0
+	UserRoleClient UserRole = 1
+)
+
+// Get UserRole string representation
+func (role UserRole) String() string {
+	switch role {
+	case UserRoleAdmin:
+		return "Admin"
+	case UserRoleClient:
+		return "Client"
+	}
+	return fmt.Sprintf("UserRole(%d)", int(role))
+}
+
+// User status.
+type UserStatus int
+
+// User statuses.
+const (
+	UserStatusActive   UserStatus = 0
+	UserStatusLocked   UserStatus = 1
+	UserStatusDisabled UserStatus = 2
+)
+
+// Get UserStatus string representation
+func (status UserStatus) String() string {
+	switch status {
+	case UserStatusActive:
+		return "Active"
+	case UserStatusLocked:
+		return "Locked"
+	case UserStatusDisabled:
+		return "Disabled"
+	}
+	return fmt.Sprintf("UserStatus(%d)", int(status))
+}
+
+// Represents a user - an administrator or a client of DeviceHive.
+type User struct {
+	// Unique identifier [do not change].
+	Id uint64 `json:"id,omitempty"`
+
+	// Login name.
+	Login string `json:"login"`
+
+	// Role.
+	Role UserRole `json:"role"`
+
+	// Status.
+	Status UserStatus `json:"status"`
+
+	// Password [write only]. It's never returned by the server.
+	Password string `json:"password,omitempty"`
+
+	// Last login timestamp, UTC [read only].
+	LastLogin Timestamp `json:"lastLogin,omitzero"`
+
+	// JSON object with an arbitrary structure [optional].
+	Data interface{} `json:"data,omitempty"`
+
+	// Networks the user has access to [read only].
+	// Use user-network assignments to change.
+	Networks []*Network `json:"networks,omitempty"`
+}
+
+// NewUser creates a new active user.
+func NewUser(login, password string, role UserRole) *User {
+	return &User{Login: login, Password: password,
+		Role: role, Status: UserStatusActive}
+}
+
+// Get User string representation
+func (user User) String() string {
+	body := ""
+
+	// Id [optional]
+	if user.Id != 0 {
+		body += fmt.Sprintf("Id:%d, ", user.Id)
+	}
+
+	// Login
+	body += fmt.Sprintf("Login:%q", user.Login)
+
+	// Role and Status
+	body += fmt.Sprintf(", Role:%s, Status:%s", user.Role, user.Status)
+
+	// Password [optional]
+	if len(user.Password) != 0 {
+		body += fmt.Sprintf(", Password:%q", RedactSecret(user.Password))
+	}
+
+	// LastLogin [optional]
+	if !user.LastLogin.IsZero() {
+		body += fmt.Sprintf(", LastLogin:%q", user.LastLogin)
+	}
+
+	// Data [optional]
+	if user.Data != nil {
+		body += fmt.Sprintf(", Data:%s", formatValue(user.Data))
+	}
+
+	// Networks [optional]
+	if len(user.Networks) != 0 {
+		body += fmt.Sprintf(", Networks:%v", user.Networks)
+	}
+
+	return fmt.Sprintf("User{%s}", body)
+}
+
+// MarshalJSON() encodes user to JSON.
+// Networks are not encoded, they could not be changed this way.
+func (user User) MarshalJSON() ([]byte, error) {
+	encoder := &objectEncoder{}
+	if user.Id != 0 {
+		encoder.put("id", user.Id)
+	}
+	encoder.put("login", user.Login)
+	encoder.put("role", user.Role)
+	encoder.put("status", user.Status)
+	if len(user.Password) != 0 {
+		encoder.put("password", user.Password)
+	}
+	if !user.LastLogin.IsZero() {
+		encoder.put("lastLogin", user.LastLogin)
+	}
+	if user.Data != nil {
+		encoder.put("data", user.Data)
+	}
+	return encoder.finish()
+}
+
+// UnmarshalJSON() decodes user from JSON.
+// Networks might be either network objects or
+// user-network objects {"network":{...}}.
+// Data is decoded according to raw JSON mode.
+func (user *User) UnmarshalJSON(data []byte) (err error) {
+	decoder, err := newObjectDecoder("User", data)
+	if decoder == nil {
+		return
+	}
+
+	if err = decoder.uint64(&user.Id, "id"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &user.Login, "login"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &user.Role, "role"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &user.Status, "status"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &user.Password, "password"); err != nil {
+		return
+	}
+	if err = decodeField(decoder, &user.LastLogin, "lastLogin"); err != nil {
+		return
+	}
+	if err = decoder.value(&user.Data, "data"); err != nil {
+		return
+	}
+	var networks []userNetwork
+	if err = decodeField(decoder, &networks, "networks"); err != nil {
+		return
+	}
+	if networks != nil {
+		user.Networks = make([]*Network, 0, len(networks))
+		for _, n := range networks {
+			user.Networks = append(user.Networks, n.network)
+		}
+	}
+	decoder.ignore("loginAttempts", "facebookLogin", "googleLogin", "githubLogin")
+
+	return decoder.finish()
+}
+
+// user-network object or just network
+type userNetwork struct {
+	network *Network
+}
+
+// decode user-network object or just network
+func (un *userNetwork) UnmarshalJSON(data []byte) error {
+	var wrapper struct {
+		Network json.RawMessage `json:"network"`
+	}
+	if json.Unmarshal(data, &wrapper) == nil && len(wrapper.Network) != 0 {
+		data = wrapper.Network
+	}
+	un.network = &Network{}
+	return json.Unmarshal(data, un.network)
+}
+
+// DecodeData() decodes user data into the provided value.
+func (user *User) DecodeData(v interface{}) error {
+	return DecodeValue(user.Data, v)
+}
+
+// Validate() checks the user fields.
+// Login should not be empty, role and status should be known.
+func (user *User) Validate() error {
+	v := &validator{}
+	v.required(user.Login, "login")
+	v.check(user.Role == UserRoleAdmin || user.Role == UserRoleClient,
+		"role", "unknown role %d", int(user.Role))
+	v.check(user.Status >= UserStatusActive && user.Status <= UserStatusDisabled,
+		"status", "unknown status %d", int(user.Status))
+	return v.result("User")
+}
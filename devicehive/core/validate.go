@@ -0,0 +1,101 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// Maximum length of device key.
+const MaxDeviceKeyLength = 64
+
+// client-side validation is disabled, see SetValidation()
+var noValidation atomic.Bool
+
+// SetValidation() enables or disables client-side validation.
+//
+// If enabled (default) devices, commands and notifications are validated
+// before they are sent to the server, see Validate().
+// The setting is process-wide and safe to change at any time.
+func SetValidation(enable bool) {
+	noValidation.Store(!enable)
+}
+
+// IsValidation() checks if client-side validation is enabled.
+func IsValidation() bool {
+	return !noValidation.Load()
+}
+
+// Validator is implemented by all model types.
+type Validator interface {
+	Validate() error
+}
+
+// Validate() validates the object if client-side validation is enabled.
+// nil is returned if validation is disabled.
+func Validate(v Validator) error {
+	if noValidation.Load() {
+		return nil
+	}
+	return v.Validate()
+}
+
+// Field validation error.
+type FieldError struct {
+	// Path to the field using JSON names,
+	// for example "deviceClass.equipment[0].code".
+	Field string
+
+	// Problem description.
+	Reason string
+}
+
+// Get error string representation
+func (err *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", err.Field, err.Reason)
+}
+
+// Validation error, contains all the field errors found.
+type ValidationError struct {
+	// Model type name.
+	Type string
+
+	// Field errors.
+	Errors []*FieldError
+}
+
+// Get error string representation
+func (err *ValidationError) Error() string {
+	reasons := make([]string, 0, len(err.Errors))
+	for _, e := range err.Errors {
+		reasons = append(reasons, e.Error())
+	}
+	return fmt.Sprintf("%s: invalid %s", err.Type,
+		strings.Join(reasons, ", "))
+}
+
+// collects field errors
+type validator struct {
+	errors []*FieldError
+}
+
+// add field error if condition is not met
+func (v *validator) check(ok bool, field string, reason string, args ...interface{}) {
+	if !ok {
+		v.errors = append(v.errors, &FieldError{Field: field,
+			Reason: fmt.Sprintf(reason, args...)})
+	}
+}
+
+// check non-empty field
+func (v *validator) required(value string, field string) {
+	v.check(len(value) != 0, field, "should not be empty")
+}
+
+// get validation result
+func (v *validator) result(name string) error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return &ValidationError{Type: name, Errors: v.errors}
+}
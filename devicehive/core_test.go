@@ -2,7 +2,11 @@ package devicehive
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -26,6 +30,67 @@ func TestTimestampFormat(t *testing.T) {
 	}
 }
 
+// Test Timestamp parsing of different formats
+func TestTimestampParse(t *testing.T) {
+	expected := time.Date(2015, time.October, 22, 14, 15, 16, 999000000, time.UTC)
+	for _, str := range []string{
+		"2015-10-22T14:15:16.999",
+		"2015-10-22T14:15:16.999000",
+		"2015-10-22T14:15:16.999Z",
+		"2015-10-22T17:15:16.999+03:00",
+		"2015-10-22 14:15:16.999"} {
+		ts, err := core.ParseTimestamp(str)
+		if err != nil {
+			t.Errorf("Timestamp: Cannot parse %q (error: %s)", str, err)
+			continue
+		}
+		if !ts.Equal(expected) {
+			t.Errorf("Timestamp: %q parsed as %s, expected %s", str, ts, expected)
+		}
+	}
+
+	// without fractional seconds
+	ts, err := core.ParseTimestamp("2015-10-22T14:15:16")
+	if err != nil || !ts.Equal(expected.Truncate(time.Second)) {
+		t.Errorf("Timestamp: Cannot parse timestamp without fractional seconds (error: %v)", err)
+	}
+
+	// empty
+	ts, err = core.ParseTimestamp("")
+	if err != nil || !ts.IsZero() {
+		t.Errorf("Timestamp: Empty string should give zero timestamp (error: %v)", err)
+	}
+
+	// bad
+	_, err = core.ParseTimestamp("22/10/2015")
+	if err == nil {
+		t.Errorf("Timestamp: Expected parse error")
+	}
+}
+
+// Test Timestamp JSON marshaling
+func TestTimestampJson(t *testing.T) {
+	ts := core.MustParseTimestamp("2015-10-22T14:15:16.123456")
+	testCheckJson(t, ts, `"2015-10-22T14:15:16.123456"`)
+	testCheckJson(t, core.Timestamp{}, `null`)
+
+	var ts2 core.Timestamp
+	err := json.Unmarshal([]byte(`"2015-10-22T14:15:16.123456Z"`), &ts2)
+	if err != nil || !ts2.Equal(ts.Time) {
+		t.Errorf("Timestamp: Cannot unmarshal %s (error: %v)", ts, err)
+	}
+	err = json.Unmarshal([]byte(`null`), &ts2)
+	if err != nil || !ts2.IsZero() {
+		t.Errorf("Timestamp: Cannot unmarshal null (error: %v)", err)
+	}
+
+	// text encoding (map keys) uses the same layout
+	testCheckJson(t, map[core.Timestamp]int{ts: 1}, `{"2015-10-22T14:15:16.123456":1}`)
+	if err = ts2.UnmarshalText([]byte("2015-10-22 14:15:16.123456")); err != nil || !ts2.Equal(ts.Time) {
+		t.Errorf("Timestamp: Cannot unmarshal text (error: %v)", err)
+	}
+}
+
 // check JSON marshaling
 func testCheckJson(t *testing.T, info interface{}, expectedJson string) {
 	jsonBytes, err := json.Marshal(info)
@@ -37,7 +102,18 @@ func testCheckJson(t *testing.T, info interface{}, expectedJson string) {
 			info, string(jsonBytes), expectedJson)
 	}
 
-	// TODO: check AssignJSON()
+	// check round trip
+	v := reflect.New(reflect.Indirect(reflect.ValueOf(info)).Type())
+	err = json.Unmarshal(jsonBytes, v.Interface())
+	if err != nil {
+		t.Errorf("Cannot parse %s (error: %s)", string(jsonBytes), err)
+		return
+	}
+	jsonBytes2, err := json.Marshal(v.Interface())
+	if err != nil || string(jsonBytes2) != string(jsonBytes) {
+		t.Errorf("JSON round trip mismatch\n\t   found:%s,\n\texpected:%s",
+			string(jsonBytes2), string(jsonBytes))
+	}
 
 	//t.Logf("%+v converted to %s", info, string(jsonBytes))
 }
@@ -46,7 +122,7 @@ func testCheckJson(t *testing.T, info interface{}, expectedJson string) {
 func TestJsonServiceInfo(t *testing.T) {
 	info := core.ServerInfo{
 		Version:      "1.2.3",
-		Timestamp:    "2015-10-22T14:15:16.999",
+		Timestamp:    core.MustParseTimestamp("2015-10-22T14:15:16.999"),
 		WebsocketUrl: "ws://devicehive.com"}
 	testCheckJson(t, info, `{"apiVersion":"1.2.3","serverTimestamp":"2015-10-22T14:15:16.999","webSocketServerUrl":"ws://devicehive.com"}`)
 
@@ -107,13 +183,15 @@ func TestJsonDevice(t *testing.T) {
 // Test Command JSON marshaling
 func TestJsonCommand(t *testing.T) {
 	command := core.NewCommand("cmd-name", "hello")
-	command.Timestamp = "2005-10-22"
-	testCheckJson(t, command, `{"timestamp":"2005-10-22","command":"cmd-name","parameters":"hello"}`)
+	testCheckJson(t, command, `{"command":"cmd-name","parameters":"hello"}`)
+
+	command.Timestamp = core.MustParseTimestamp("2005-10-22")
+	testCheckJson(t, command, `{"timestamp":"2005-10-22T00:00:00","command":"cmd-name","parameters":"hello"}`)
 
 	command.Id = 100
 	command.Result = "custom data"
 	command.Status = "done"
-	testCheckJson(t, command, `{"id":100,"timestamp":"2005-10-22","command":"cmd-name","parameters":"hello","status":"done","result":"custom data"}`)
+	testCheckJson(t, command, `{"id":100,"timestamp":"2005-10-22T00:00:00","command":"cmd-name","parameters":"hello","status":"done","result":"custom data"}`)
 }
 
 // Test Notification JSON marshaling
@@ -124,3 +202,370 @@ func TestJsonNotification(t *testing.T) {
 	notification.Id = 100
 	testCheckJson(t, notification, `{"id":100,"notification":"ntf-name","parameters":"hello"}`)
 }
+
+// test structure for typed decoding
+type testParams struct {
+	Led   string `json:"led"`
+	Value int    `json:"value"`
+}
+
+// Test typed decoding of command parameters
+func TestDecodeCommandParameters(t *testing.T) {
+	body := []byte(`{"id":1,"command":"set","parameters":{"led":"red","value":10},"result":[1,2,3]}`)
+
+	for _, raw := range []bool{false, true} {
+		core.SetRawJSON(raw)
+
+		var command core.Command
+		err := json.Unmarshal(body, &command)
+		if err != nil {
+			t.Errorf("raw:%t: Cannot unmarshal command (error: %s)", raw, err)
+			continue
+		}
+		if _, ok := command.Parameters.(json.RawMessage); ok != raw {
+			t.Errorf("raw:%t: unexpected parameters type %T", raw, command.Parameters)
+		}
+
+		var p testParams
+		err = command.DecodeParameters(&p)
+		if err != nil || p.Led != "red" || p.Value != 10 {
+			t.Errorf("raw:%t: Cannot decode parameters %+v (error: %v)", raw, p, err)
+		}
+
+		r, err := core.CommandResult[[]int](&command)
+		if err != nil || len(r) != 3 || r[2] != 3 {
+			t.Errorf("raw:%t: Cannot decode result %v (error: %v)", raw, r, err)
+		}
+
+		// marshal back
+		testCheckJson(t, command, string(body))
+	}
+	core.SetRawJSON(false)
+}
+
+// Test typed decoding of notification parameters and device data
+func TestDecodeNotificationParameters(t *testing.T) {
+	notification := core.NewNotification("ntf", map[string]interface{}{"led": "green", "value": 5.0})
+	p, err := core.NotificationParameters[testParams](notification)
+	if err != nil || p.Led != "green" || p.Value != 5 {
+		t.Errorf("Cannot decode parameters %+v (error: %v)", p, err)
+	}
+
+	device := core.NewDevice("id", "name", nil)
+	err = device.DecodeData(&p)
+	if err != nil {
+		t.Errorf("Cannot decode empty data (error: %s)", err)
+	}
+
+	device.Data = "not an object"
+	err = device.DecodeData(&p)
+	if err == nil {
+		t.Errorf("Expected decoding error")
+	}
+}
+
+// Payloads captured from different DeviceHive server versions.
+// Each payload is decoded, encoded and compared with canonical form.
+var testCapturedPayloads = []struct {
+	name      string
+	value     func() interface{}
+	payload   string
+	canonical string
+}{
+	{"ServerInfo/rest", func() interface{} { return &core.ServerInfo{} },
+		`{"apiVersion":"1.3.0","serverTimestamp":"2015-10-22T14:15:16.123456","webSocketServerUrl":"ws://playground.devicehive.com/api/websocket"}`,
+		`{"apiVersion":"1.3.0","serverTimestamp":"2015-10-22T14:15:16.123456","webSocketServerUrl":"ws://playground.devicehive.com/api/websocket"}`},
+	{"ServerInfo/ws", func() interface{} { return &core.ServerInfo{} },
+		`{"apiVersion":"2.0.0","serverTimestamp":"2015-10-22T14:15:16.123Z","restServerUrl":"http://playground.devicehive.com/api/rest"}`,
+		`{"apiVersion":"2.0.0","serverTimestamp":"2015-10-22T14:15:16.123","restServerUrl":"http://playground.devicehive.com/api/rest"}`},
+	{"Command/rest", func() interface{} { return &core.Command{} },
+		`{"id":123456,"timestamp":"2015-10-22T14:15:16.123456","userId":1,"command":"set-led","parameters":{"state":"on"},"lifetime":null,"status":null,"result":null}`,
+		`{"id":123456,"timestamp":"2015-10-22T14:15:16.123456","userId":1,"command":"set-led","parameters":{"state":"on"}}`},
+	{"Command/v2", func() interface{} { return &core.Command{} },
+		`{"id":123456,"timestamp":"2015-10-22T14:15:16.123","deviceId":"dev-id","networkId":1,"command":"set-led","lifetime":60,"status":"Done","result":"ok","isUpdated":true}`,
+		`{"id":123456,"timestamp":"2015-10-22T14:15:16.123","command":"set-led","lifetime":60,"status":"Done","result":"ok"}`},
+	{"Notification/rest", func() interface{} { return &core.Notification{} },
+		`{"id":42,"notification":"temperature","timestamp":"2015-10-22T14:15:16.123456","parameters":{"value":36.6}}`,
+		`{"id":42,"timestamp":"2015-10-22T14:15:16.123456","notification":"temperature","parameters":{"value":36.6}}`},
+	{"Notification/name", func() interface{} { return &core.Notification{} },
+		`{"id":42,"name":"temperature","deviceGuid":"dev-id","timestamp":"2015-10-22T14:15:16"}`,
+		`{"id":42,"timestamp":"2015-10-22T14:15:16","notification":"temperature"}`},
+	{"Device/rest", func() interface{} { return &core.Device{} },
+		`{"id":"dev-id","name":"dev-name","status":"Online","data":null,"network":{"id":1,"name":"net-name","key":null,"description":null},"deviceClass":{"id":2,"name":"class-name","version":"1.0","isPermanent":false,"offlineTimeout":null,"data":null,"equipment":[{"id":3,"name":"eqp-name","code":"eqp-code","type":"eqp-type","data":null}]}}`,
+		`{"id":"dev-id","name":"dev-name","status":"Online","network":{"id":1,"name":"net-name"},"deviceClass":{"id":2,"name":"class-name","version":"1.0","equipment":[{"id":3,"name":"eqp-name","code":"eqp-code","type":"eqp-type"}]}}`},
+	{"Device/guid", func() interface{} { return &core.Device{} },
+		`{"guid":"dev-id","name":"dev-name","data":{"a":1}}`,
+		`{"id":"dev-id","name":"dev-name","data":{"a":1}}`},
+}
+
+// Test captured payloads round trip
+func TestJsonCapturedPayloads(t *testing.T) {
+	for _, p := range testCapturedPayloads {
+		v := p.value()
+		err := json.Unmarshal([]byte(p.payload), v)
+		if err != nil {
+			t.Errorf("%s: Cannot parse payload (error: %s)", p.name, err)
+			continue
+		}
+
+		testCheckJson(t, v, p.canonical)
+	}
+}
+
+// Test strict mode reports unknown fields
+func TestJsonStrict(t *testing.T) {
+	core.SetStrictJSON(true)
+	defer core.SetStrictJSON(false)
+
+	var notification core.Notification
+	err := json.Unmarshal([]byte(`{"id":1,"notification":"a","deviceGuid":"x","foo":1,"bar":2}`), &notification)
+	uerr, ok := err.(*core.UnknownFieldsError)
+	if !ok {
+		t.Fatalf("Expected unknown fields error, found %v", err)
+	}
+	if uerr.Type != "Notification" || len(uerr.Fields) != 2 ||
+		uerr.Fields[0] != "bar" || uerr.Fields[1] != "foo" {
+		t.Errorf("Unexpected unknown fields error: %s", uerr)
+	}
+
+	// nested objects are also checked
+	var device core.Device
+	err = json.Unmarshal([]byte(`{"id":"x","network":{"name":"n","foo":1}}`), &device)
+	if _, ok := err.(*core.UnknownFieldsError); !ok {
+		t.Errorf("Expected unknown fields error for nested object, found %v", err)
+	}
+
+	// all known fields
+	for _, p := range testCapturedPayloads {
+		err := json.Unmarshal([]byte(p.payload), p.value())
+		if err != nil {
+			t.Errorf("%s: Unexpected error in strict mode: %s", p.name, err)
+		}
+	}
+}
+
+// Test bad field values are reported
+func TestJsonBadValues(t *testing.T) {
+	for _, payload := range []string{
+		`{"id":"abc"}`,
+		`{"id":-1}`,
+		`{"command":123}`,
+		`{"timestamp":"yesterday"}`,
+		`[1,2,3]`} {
+		var command core.Command
+		err := json.Unmarshal([]byte(payload), &command)
+		if err == nil {
+			t.Errorf("Expected error for %s", payload)
+		}
+	}
+}
+
+// Test 64-bit identifiers and numbers survive JSON decoding
+func TestJsonBigIdentifiers(t *testing.T) {
+	for _, id := range []uint64{0, 1, 1<<53 - 1, 1 << 53, 1<<53 + 1, 1<<63 + 1, 1<<64 - 1} {
+		ids := fmt.Sprintf("%d", id)
+
+		var command core.Command
+		err := json.Unmarshal([]byte(`{"id":`+ids+`,"userId":`+ids+`,"parameters":{"big":`+ids+`}}`), &command)
+		if err != nil || command.Id != id || command.UserId != id {
+			t.Errorf("Command: %d decoded as %d/%d (error: %v)", id, command.Id, command.UserId, err)
+		}
+		var p struct{ Big uint64 }
+		if err = command.DecodeParameters(&p); err != nil || p.Big != id {
+			t.Errorf("Command: parameter %d decoded as %d (error: %v)", id, p.Big, err)
+		}
+
+		var notification core.Notification
+		err = json.Unmarshal([]byte(`{"id":`+ids+`,"parameters":[`+ids+`]}`), &notification)
+		if err != nil || notification.Id != id {
+			t.Errorf("Notification: %d decoded as %d (error: %v)", id, notification.Id, err)
+		}
+		if b, _ := json.Marshal(notification.Parameters); string(b) != "["+ids+"]" {
+			t.Errorf("Notification: parameter %d encoded as %s", id, string(b))
+		}
+
+		var device core.Device
+		err = json.Unmarshal([]byte(`{"network":{"id":`+ids+`},"deviceClass":{"id":`+ids+`,"equipment":[{"id":`+ids+`}]}}`), &device)
+		if err != nil || device.Network.Id != id || device.DeviceClass.Id != id ||
+			device.DeviceClass.Equipment[0].Id != id {
+			t.Errorf("Device: %d decoded incorrectly %s (error: %v)", id, device, err)
+		}
+	}
+
+	// overflow
+	var command core.Command
+	err := json.Unmarshal([]byte(`{"id":18446744073709551616}`), &command)
+	if err == nil {
+		t.Errorf("Command: expected overflow error")
+	}
+}
+
+// test device for clone and diff
+func testCloneDevice() *core.Device {
+	class := core.NewDeviceClass("go-class", "1.0")
+	class.Data = map[string]interface{}{"a": []interface{}{1, 2}}
+	class.AddEquipment(core.NewEquipment("eq-1", "code-1", "sensor"),
+		core.NewEquipment("eq-2", "code-2", "led"))
+	device := core.NewDeviceWithNetwork("go-dev", "go-name", class, core.NewNetwork("go-net", "net-key"))
+	device.Data = map[string]interface{}{"x": map[string]interface{}{"y": "z"}}
+	return device
+}
+
+// Test deep copy
+func TestCloneDevice(t *testing.T) {
+	device := testCloneDevice()
+	clone := device.Clone()
+	if !device.Equal(clone) {
+		t.Fatalf("Clone: %s != %s", clone, device)
+	}
+
+	// modify the copy, the original should not be changed
+	clone.Network.Name = "other-net"
+	clone.DeviceClass.Equipment[0].Code = "other-code"
+	clone.DeviceClass.Data.(map[string]interface{})["a"].([]interface{})[0] = 100
+	clone.Data.(map[string]interface{})["x"].(map[string]interface{})["y"] = "w"
+
+	expected := core.FormatChanges([]core.Change{
+		{Path: "data.x.y", Old: "z", New: "w"},
+		{Path: "network.name", Old: "go-net", New: "other-net"},
+		{Path: "deviceClass.data.a[0]", Old: json.Number("1"), New: json.Number("100")},
+		{Path: "deviceClass.equipment[0].code", Old: "code-1", New: "other-code"},
+	})
+	if diff := core.FormatChanges(device.Diff(clone)); diff != expected {
+		t.Errorf("Diff:\n%s\nexpected:\n%s", diff, expected)
+	}
+	if device.Equal(clone) {
+		t.Errorf("Equal: modified copy is equal to original")
+	}
+
+	var nilDevice *core.Device
+	if nilDevice.Clone() != nil || !nilDevice.Equal(nil) || nilDevice.Equal(device) {
+		t.Errorf("Clone: nil device handled incorrectly")
+	}
+}
+
+// Test equality of different representations
+func TestEqualValues(t *testing.T) {
+	a := core.NewCommand("hello", map[string]interface{}{"n": 1, "f": 1.5})
+	b := core.NewCommand("hello", json.RawMessage(`{"f":1.50,"n":1.0}`))
+	if !a.Equal(b) {
+		t.Errorf("Equal: %s != %s", a, b)
+	}
+
+	b.Timestamp = core.MustParseTimestamp("2016-03-01T10:20:30.123")
+	b.Parameters = json.RawMessage(`{"f":1.5,"n":2}`)
+	changes := a.Diff(b)
+	if len(changes) != 2 || changes[0].Path != "timestamp" || changes[1].Path != "parameters.n" {
+		t.Errorf("Diff: unexpected changes:\n%s", core.FormatChanges(changes))
+	}
+
+	ntf := core.NewNotification("hello", []interface{}{"x"})
+	if changes := ntf.Diff(ntf.Clone()); len(changes) != 0 {
+		t.Errorf("Diff: unexpected changes:\n%s", core.FormatChanges(changes))
+	}
+	if changes := ntf.Diff(nil); len(changes) != 1 || changes[0].New != nil {
+		t.Errorf("Diff: unexpected changes:\n%s", core.FormatChanges(changes))
+	}
+}
+
+// Test client-side validation
+func TestValidate(t *testing.T) {
+	device := testCloneDevice()
+	if err := device.Validate(); err != nil {
+		t.Fatalf("Validate: unexpected error: %s", err)
+	}
+
+	device.Name = ""
+	device.Key = strings.Repeat("k", core.MaxDeviceKeyLength+1)
+	device.Network.Name = ""
+	device.DeviceClass.Version = ""
+	device.DeviceClass.Equipment[1].Code = "code-1"
+
+	err := device.Validate()
+	verr, ok := err.(*core.ValidationError)
+	if !ok {
+		t.Fatalf("Validate: unexpected error: %v", err)
+	}
+	var fields []string
+	for _, e := range verr.Errors {
+		fields = append(fields, e.Field)
+	}
+	expected := []string{"name", "key", "network.name", "deviceClass.version", "deviceClass.equipment[1].code"}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("Validate: %s\nunexpected fields: %q, expected: %q", err, fields, expected)
+	}
+
+	// network referenced by identifier
+	device = core.NewDeviceWithNetwork("dev-id", "name", nil, &core.Network{Id: 1})
+	if err := device.Validate(); err != nil {
+		t.Errorf("Validate: network referenced by id rejected: %s", err)
+	}
+
+	if err := core.NewCommand("", nil).Validate(); err == nil {
+		t.Errorf("Validate: command without name accepted")
+	}
+	if err := core.NewNotification("", nil).Validate(); err == nil {
+		t.Errorf("Validate: notification without name accepted")
+	}
+	if err := core.NewEquipment("name", "", "type").Validate(); err == nil {
+		t.Errorf("Validate: equipment without code accepted")
+	}
+}
+
+// Test validation is done before sending
+func TestValidateBeforeSend(t *testing.T) {
+	service, err := NewRestService("http://127.0.0.1:1/api", "")
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	device := core.NewDevice("dev-id", "", nil)
+	err = service.RegisterDevice(device, time.Second)
+	if _, ok := err.(*core.ValidationError); !ok {
+		t.Errorf("RegisterDevice: validation error expected, got %v", err)
+	}
+
+	// opt-out
+	core.SetValidation(false)
+	defer core.SetValidation(true)
+	err = service.RegisterDevice(device, time.Second)
+	if _, ok := err.(*core.ValidationError); ok || err == nil {
+		t.Errorf("RegisterDevice: network error expected, got %v", err)
+	}
+}
+
+// Test secrets are redacted
+func TestRedactSecrets(t *testing.T) {
+	device := core.NewDeviceWithNetwork("dev-id", "dev-name", nil, core.NewNetwork("net-name", "net-secret"))
+	device.Key = "dev-secret"
+
+	str := device.String()
+	if strings.Contains(str, "dev-secret") || strings.Contains(str, "net-secret") {
+		t.Errorf("String: secrets are not redacted: %s", str)
+	}
+
+	msg := core.RedactJSON([]byte(`{"action":"authenticate","accessKey":"access-secret","deviceKey":"dev-secret","device":{"key":"dev-secret"}}`))
+	if strings.Contains(msg, "secret") || !strings.Contains(msg, `"action":"authenticate"`) {
+		t.Errorf("RedactJSON: secrets are not redacted: %s", msg)
+	}
+
+	header := core.RedactHeader(http.Header{"Authorization": {"Bearer access-secret"}, "Auth-Devicekey": {"dev-secret"}}, core.SecretHeaders)
+	if header.Get("Authorization") != "Bearer ***" || header.Get("Auth-DeviceKey") != core.Redacted {
+		t.Errorf("RedactHeader: secrets are not redacted: %v", header)
+	}
+
+	service, err := NewRestService("http://127.0.0.1:1/api", "access-secret")
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+	if str := fmt.Sprintf("%s", service); strings.Contains(str, "access-secret") {
+		t.Errorf("String: access key is not redacted: %s", str)
+	}
+
+	// opt-in
+	SetRevealSecrets(true)
+	defer SetRevealSecrets(false)
+	if str := device.String(); !strings.Contains(str, "dev-secret") {
+		t.Errorf("String: secrets are not revealed: %s", str)
+	}
+}
@@ -0,0 +1,139 @@
+package devicehive
+
+import (
+	"encoding/json"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"github.com/devicehive/devicehive-go/devicehive/rest"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// in-memory /device/class server
+func testNewDeviceClassServer(t *testing.T) *httptest.Server {
+	var lock sync.Mutex
+	classes := make(map[uint64]json.RawMessage)
+	lastId := uint64(0)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		path := strings.TrimPrefix(r.URL.Path, "/device/class")
+		id, _ := strconv.ParseUint(strings.TrimPrefix(path, "/"), 10, 64)
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == "GET" && path == "":
+			var list []json.RawMessage
+			for i := uint64(1); i <= lastId; i++ {
+				if c, ok := classes[i]; ok {
+					list = append(list, c)
+				}
+			}
+			json.NewEncoder(w).Encode(list)
+		case r.Method == "POST" && path == "":
+			lastId++
+			var c map[string]interface{}
+			json.Unmarshal(body, &c)
+			c["id"] = lastId
+			classes[lastId], _ = json.Marshal(c)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": lastId})
+		case classes[id] == nil:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == "GET":
+			w.Write(classes[id])
+		case r.Method == "PUT":
+			var c map[string]interface{}
+			json.Unmarshal(classes[id], &c)
+			var u map[string]interface{}
+			json.Unmarshal(body, &u)
+			for k, v := range u {
+				c[k] = v
+			}
+			classes[id], _ = json.Marshal(c)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "DELETE":
+			delete(classes, id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+}
+
+// Test device class CRUD
+func TestDeviceClassCrud(t *testing.T) {
+	server := testNewDeviceClassServer(t)
+	defer server.Close()
+
+	service, err := rest.NewService(server.URL, "")
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	class := core.NewDeviceClass("go-class", "1.0")
+	class.OfflineTimeout = 60
+	class.Data = map[string]interface{}{"vendor": "go"}
+	class.AddEquipment(core.NewEquipment("temp", "t1", "sensor"),
+		core.NewEquipment("led", "l1", "led"))
+	if err = service.InsertDeviceClass(class, testWaitTimeout); err != nil || class.Id != 1 {
+		t.Fatalf("failed to insert device class %s (error: %v)", class, err)
+	}
+
+	// duplicate equipment code is rejected
+	bad := core.NewDeviceClass("bad", "1.0")
+	bad.AddEquipment(core.NewEquipment("a", "x", "t"), core.NewEquipment("b", "x", "t"))
+	if err = service.InsertDeviceClass(bad, testWaitTimeout); err == nil {
+		t.Errorf("invalid device class inserted")
+	}
+
+	// equipment edits
+	class.RemoveEquipment("l1")
+	class.FindEquipment("t1").Name = "temperature"
+	if err = service.UpdateDeviceClass(class, testWaitTimeout); err != nil {
+		t.Fatalf("failed to update device class (error: %s)", err)
+	}
+
+	got, err := service.GetDeviceClass(class.Id, testWaitTimeout)
+	if err != nil {
+		t.Fatalf("failed to get device class (error: %s)", err)
+	}
+	if changes := class.Diff(got); len(changes) != 0 {
+		t.Errorf("unexpected device class changes:\n%s", core.FormatChanges(changes))
+	}
+
+	// remove all the equipment, clear the offline timeout and data
+	class.Equipment = []*core.Equipment{}
+	class.OfflineTimeout = 0
+	class.Data = nil
+	if err = service.UpdateDeviceClass(class, testWaitTimeout); err != nil {
+		t.Fatalf("failed to update device class (error: %s)", err)
+	}
+	if got, err = service.GetDeviceClass(class.Id, testWaitTimeout); err != nil || len(got.Equipment) != 0 {
+		t.Errorf("equipment is not removed: %v (error: %v)", got, err)
+	} else if got.OfflineTimeout != 0 {
+		t.Errorf("offline timeout is not cleared: %v", got)
+	} else if got.Data != nil {
+		t.Errorf("data is not cleared: %v", got.Data)
+	}
+
+	list, err := service.GetDeviceClassListFilter(&core.DeviceClassFilter{NamePattern: "go-%"}, testWaitTimeout)
+	if err != nil || len(list) != 1 || list[0].Name != "go-class" {
+		t.Errorf("unexpected device class list: %v (error: %v)", list, err)
+	}
+
+	if err = service.DeleteDeviceClass(class, testWaitTimeout); err != nil {
+		t.Errorf("failed to delete device class (error: %s)", err)
+	}
+	if _, err = service.GetDeviceClass(class.Id, testWaitTimeout); err == nil {
+		t.Errorf("deleted device class is still available")
+	}
+}
@@ -0,0 +1,105 @@
+package devicehive
+
+import (
+	"encoding/json"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"github.com/devicehive/devicehive-go/devicehive/record"
+	"github.com/devicehive/devicehive-go/devicehive/rest"
+	"github.com/devicehive/devicehive-go/devicehive/ws"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Test partial device built from changes
+func TestDeviceDelta(t *testing.T) {
+	old := core.NewDeviceWithNetwork("dev-1", "name", core.NewDeviceClass("cls", "1.0"), core.NewNetwork("net", ""))
+	old.Data = map[string]interface{}{"a": 1}
+
+	if delta := old.Delta(old.Clone()); delta != nil {
+		t.Errorf("unexpected delta for the same device: %s", delta)
+	}
+
+	updated := old.Clone()
+	updated.Status = "Offline"
+	updated.Data = map[string]interface{}{"a": 2}
+	delta := old.Delta(updated)
+	if delta == nil {
+		t.Fatalf("no delta")
+	}
+	body, _ := json.Marshal(delta)
+	if string(body) != `{"id":"dev-1","status":"Offline","data":{"a":2}}` {
+		t.Errorf("unexpected delta: %s", body)
+	}
+}
+
+// Test REST partial update and delete through the Service interface
+func TestRestUpdateDevice(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body)+" "+r.Header.Get("Auth-DeviceID"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	rest_service, err := rest.NewService(server.URL, "")
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+	var service Service = rest_service
+
+	device := core.NewDevice("dev-1", "name", nil)
+	device.Key = "key"
+	if err = service.UpdateDevice(device, &core.Device{Status: "Offline"}, testWaitTimeout); err != nil {
+		t.Errorf("failed to update device (error: %s)", err)
+	}
+	if err = service.UpdateDevice(device, nil, testWaitTimeout); err != nil {
+		t.Errorf("failed to update device with nothing (error: %s)", err)
+	}
+	if err = service.DeleteDevice(device, testWaitTimeout); err != nil {
+		t.Errorf("failed to delete device (error: %s)", err)
+	}
+
+	expected := []string{`PUT /device/dev-1 {"status":"Offline"} dev-1`, `DELETE /device/dev-1  dev-1`}
+	if strings.Join(requests, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected requests:\n%s", strings.Join(requests, "\n"))
+	}
+}
+
+// Websocket cassette with device update and delete
+const testWsDeviceUpdateCassette = `
+{"kind":"ws","dir":"dial","url":"ws://localhost/device"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"device/save\",\"requestId\":1,\"deviceId\":\"dev-1\",\"device\":{\"data\":\"new data\"}}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"device/save\",\"requestId\":1,\"status\":\"success\"}"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"device/delete\",\"requestId\":2,\"deviceId\":\"dev-1\"}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"device/delete\",\"requestId\":2,\"status\":\"error\",\"code\":404,\"error\":\"Device not found\"}"}
+`
+
+// Test Websocket partial update and delete through the Service interface
+func TestWebsocketUpdateDevice(t *testing.T) {
+	player, err := record.NewPlayer(strings.NewReader(testWsDeviceUpdateCassette))
+	if err != nil {
+		t.Fatalf("Failed to load cassette (error: %s)", err)
+	}
+
+	ws_service, err := ws.NewServiceDial("ws://localhost", "", player.Dial())
+	if err != nil {
+		t.Fatalf("Failed to create WS service (error: %s)", err)
+	}
+	var service Service = ws_service
+
+	device := core.NewDevice("dev-1", "name", nil)
+	updated := device.Clone()
+	updated.Data = "new data"
+	if err = service.UpdateDevice(device, device.Delta(updated), testWaitTimeout); err != nil {
+		t.Errorf("Failed to update device (error: %s)", err)
+	}
+
+	err = service.DeleteDevice(device, testWaitTimeout)
+	if err == nil || !strings.Contains(err.Error(), "Device not found") {
+		t.Errorf("Delete error expected, got %v", err)
+	}
+}
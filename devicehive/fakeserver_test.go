@@ -0,0 +1,489 @@
+package devicehive_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"github.com/devicehive/devicehive-go/devicehive/ws"
+	"github.com/gorilla/websocket"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Response latency of the fake server.
+// Tiny timeouts should always expire first.
+const fakeLatency = time.Millisecond
+
+// In-memory DeviceHive server to run the conformance suite offline.
+// REST and Websocket are served from the same state, so commands
+// inserted via REST are pushed to Websocket subscribers.
+type fakeServer struct {
+	http *httptest.Server
+
+	lock          sync.Mutex
+	clock         time.Time // fake clock, advanced on every event
+	lastId        uint64
+	devices       map[string]*core.Device
+	commands      map[string][]*core.Command
+	notifications map[string][]*core.Notification
+	subscribers   map[string]map[*fakeConn]bool // command subscribers by device
+	changed       chan struct{}                 // closed on every new command
+	closed        chan struct{}
+}
+
+// error reported by the fake server
+type fakeError struct {
+	code    int
+	message string
+}
+
+// start new fake server, stopped on test cleanup
+func newFakeServer(t *testing.T) *fakeServer {
+	s := &fakeServer{
+		clock:         time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC),
+		devices:       make(map[string]*core.Device),
+		commands:      make(map[string][]*core.Command),
+		notifications: make(map[string][]*core.Notification),
+		subscribers:   make(map[string]map[*fakeConn]bool),
+		changed:       make(chan struct{}),
+		closed:        make(chan struct{}),
+	}
+	s.http = httptest.NewServer(s)
+	t.Cleanup(func() {
+		close(s.closed) // release long polls
+		s.http.Close()
+	})
+	return s
+}
+
+// REST service URL
+func (s *fakeServer) URL() string {
+	return s.http.URL
+}
+
+// get current time, should be called under lock
+func (s *fakeServer) now() core.Timestamp {
+	s.clock = s.clock.Add(time.Millisecond)
+	return core.NewTimestamp(s.clock)
+}
+
+// get server information
+func (s *fakeServer) info() *core.ServerInfo {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return &core.ServerInfo{Version: "fake", Timestamp: s.now()}
+}
+
+// register or update device
+func (s *fakeServer) registerDevice(deviceId string, device *core.Device) *fakeError {
+	if device == nil {
+		return &fakeError{http.StatusBadRequest, "no device provided"}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	device = device.Clone()
+	device.Id = deviceId
+	s.devices[deviceId] = device
+	return nil
+}
+
+// get device
+func (s *fakeServer) getDevice(deviceId string) (*core.Device, *fakeError) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	device, ok := s.devices[deviceId]
+	if !ok {
+		return nil, &fakeError{http.StatusNotFound, "Device not found"}
+	}
+	return device.Clone(), nil
+}
+
+// insert notification
+func (s *fakeServer) insertNotification(deviceId string, notification *core.Notification) (*core.Notification, *fakeError) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.devices[deviceId]; !ok {
+		return nil, &fakeError{http.StatusNotFound, "Device not found"}
+	}
+	if notification == nil {
+		return nil, &fakeError{http.StatusBadRequest, "no notification provided"}
+	}
+
+	notification = notification.Clone()
+	s.lastId += 1
+	notification.Id = s.lastId
+	notification.Timestamp = s.now()
+	s.notifications[deviceId] = append(s.notifications[deviceId], notification)
+	return &core.Notification{Id: notification.Id, Timestamp: notification.Timestamp}, nil
+}
+
+// get notification
+func (s *fakeServer) getNotification(deviceId string, notificationId uint64) (*core.Notification, *fakeError) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, notification := range s.notifications[deviceId] {
+		if notification.Id == notificationId {
+			return notification.Clone(), nil
+		}
+	}
+	return nil, &fakeError{http.StatusNotFound, "Notification not found"}
+}
+
+// insert command and push it to subscribers
+func (s *fakeServer) insertCommand(deviceId string, command *core.Command) (*core.Command, *fakeError) {
+	s.lock.Lock()
+	if _, ok := s.devices[deviceId]; !ok {
+		s.lock.Unlock()
+		return nil, &fakeError{http.StatusNotFound, "Device not found"}
+	}
+	if command == nil {
+		s.lock.Unlock()
+		return nil, &fakeError{http.StatusBadRequest, "no command provided"}
+	}
+
+	command = command.Clone()
+	s.lastId += 1
+	command.Id = s.lastId
+	command.Timestamp = s.now()
+	s.commands[deviceId] = append(s.commands[deviceId], command)
+	close(s.changed)
+	s.changed = make(chan struct{})
+
+	var conns []*fakeConn
+	for conn := range s.subscribers[deviceId] {
+		conns = append(conns, conn)
+	}
+	push := fakeMessage{"action": "command/insert", "deviceGuid": deviceId, "command": command.Clone()}
+	s.lock.Unlock()
+
+	for _, conn := range conns {
+		conn.push(push)
+	}
+	return &core.Command{Id: command.Id, Timestamp: command.Timestamp}, nil
+}
+
+// find command, should be called under lock
+func (s *fakeServer) findCommand(deviceId string, commandId uint64) (*core.Command, *fakeError) {
+	for _, command := range s.commands[deviceId] {
+		if command.Id == commandId {
+			return command, nil
+		}
+	}
+	return nil, &fakeError{http.StatusNotFound, "Command not found"}
+}
+
+// update command status and result
+func (s *fakeServer) updateCommand(deviceId string, commandId uint64, update *core.Command) *fakeError {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	command, err := s.findCommand(deviceId, commandId)
+	if err != nil {
+		return err
+	}
+	if update != nil {
+		if len(update.Status) != 0 {
+			command.Status = update.Status
+		}
+		if update.Result != nil {
+			command.Result = update.Result
+		}
+	}
+	return nil
+}
+
+// get command
+func (s *fakeServer) getCommand(deviceId string, commandId uint64) (*core.Command, *fakeError) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	command, err := s.findCommand(deviceId, commandId)
+	if err != nil {
+		return nil, err
+	}
+	return command.Clone(), nil
+}
+
+// get commands newer than timestamp, should be called under lock
+func (s *fakeServer) newCommands(deviceId string, timestamp core.Timestamp) []*core.Command {
+	var commands []*core.Command
+	for _, command := range s.commands[deviceId] {
+		if command.Timestamp.After(timestamp.Time) {
+			commands = append(commands, command.Clone())
+		}
+	}
+	return commands
+}
+
+// wait for commands newer than timestamp
+func (s *fakeServer) pollCommands(deviceId string, timestamp core.Timestamp, wait time.Duration) []*core.Command {
+	deadline := time.After(wait)
+	for {
+		s.lock.Lock()
+		commands := s.newCommands(deviceId, timestamp)
+		changed := s.changed
+		s.lock.Unlock()
+		if len(commands) != 0 {
+			return commands
+		}
+
+		select {
+		case <-changed:
+		case <-deadline:
+			return []*core.Command{}
+		case <-s.closed:
+			return []*core.Command{}
+		}
+	}
+}
+
+// subscribe Websocket connection to device commands
+func (s *fakeServer) subscribe(conn *fakeConn, deviceId string, timestamp core.Timestamp) {
+	s.lock.Lock()
+	if s.subscribers[deviceId] == nil {
+		s.subscribers[deviceId] = make(map[*fakeConn]bool)
+	}
+	s.subscribers[deviceId][conn] = true
+	var commands []*core.Command
+	if !timestamp.IsZero() {
+		commands = s.newCommands(deviceId, timestamp)
+	}
+	s.lock.Unlock()
+
+	for _, command := range commands {
+		conn.push(fakeMessage{"action": "command/insert", "deviceGuid": deviceId, "command": command})
+	}
+}
+
+// unsubscribe Websocket connection from device commands
+func (s *fakeServer) unsubscribe(conn *fakeConn, deviceId string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.subscribers[deviceId], conn)
+}
+
+// ServeHTTP() serves REST requests.
+func (s *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	time.Sleep(fakeLatency)
+	body, _ := io.ReadAll(r.Body)
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	result, err := s.serveRest(r.Method, path, r.URL.Query(), body)
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case err != nil:
+		w.WriteHeader(err.code)
+		json.NewEncoder(w).Encode(fakeMessage{"error": err.code, "message": err.message})
+	case result == nil:
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// dispatch REST request
+func (s *fakeServer) serveRest(method string, path []string, query map[string][]string, body []byte) (interface{}, *fakeError) {
+	route := method + " " + path[0]
+	if len(path) > 2 {
+		route += "/" + path[2]
+	}
+	if len(path) > 3 {
+		route += "/" + path[3]
+	}
+
+	// numeric identifier is the last path element
+	var id uint64
+	if len(path) > 3 {
+		id, _ = strconv.ParseUint(path[3], 10, 64)
+	}
+
+	switch {
+	case route == "GET info":
+		return s.info(), nil
+
+	case route == "PUT device" && len(path) == 2:
+		device := &core.Device{}
+		if err := json.Unmarshal(body, device); err != nil {
+			return nil, &fakeError{http.StatusBadRequest, err.Error()}
+		}
+		return nil, s.registerDevice(path[1], device)
+
+	case route == "GET device" && len(path) == 2:
+		return s.getDevice(path[1])
+
+	case route == "POST device/notification" && len(path) == 3:
+		notification := &core.Notification{}
+		if err := json.Unmarshal(body, notification); err != nil {
+			return nil, &fakeError{http.StatusBadRequest, err.Error()}
+		}
+		return s.insertNotification(path[1], notification)
+
+	case strings.HasPrefix(route, "GET device/notification/") && len(path) == 4:
+		return s.getNotification(path[1], id)
+
+	case route == "POST device/command" && len(path) == 3:
+		command := &core.Command{}
+		if err := json.Unmarshal(body, command); err != nil {
+			return nil, &fakeError{http.StatusBadRequest, err.Error()}
+		}
+		return s.insertCommand(path[1], command)
+
+	case route == "GET device/command/poll" && len(path) == 4:
+		timestamp, _ := core.ParseTimestamp(fakeQuery(query, "timestamp"))
+		wait, _ := strconv.Atoi(fakeQuery(query, "waitTimeout"))
+		return s.pollCommands(path[1], timestamp, time.Duration(wait)*time.Second), nil
+
+	case strings.HasPrefix(route, "GET device/command/") && len(path) == 4:
+		return s.getCommand(path[1], id)
+
+	case strings.HasPrefix(route, "PUT device/command/") && len(path) == 4:
+		update := &core.Command{}
+		if err := json.Unmarshal(body, update); err != nil {
+			return nil, &fakeError{http.StatusBadRequest, err.Error()}
+		}
+		return nil, s.updateCommand(path[1], id, update)
+	}
+
+	return nil, &fakeError{http.StatusNotFound, fmt.Sprintf("unknown request %s %s", method, strings.Join(path, "/"))}
+}
+
+// get the first query value
+func fakeQuery(query map[string][]string, name string) string {
+	if values := query[name]; len(values) != 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Websocket message
+type fakeMessage map[string]interface{}
+
+// Websocket request, all actions
+type fakeRequest struct {
+	Action         string             `json:"action"`
+	RequestId      json.Number        `json:"requestId"`
+	DeviceId       string             `json:"deviceId"`
+	DeviceGuid     string             `json:"deviceGuid"`
+	CommandId      uint64             `json:"commandId"`
+	NotificationId uint64             `json:"notificationId"`
+	Timestamp      core.Timestamp     `json:"timestamp"`
+	Device         *core.Device       `json:"device"`
+	Command        *core.Command      `json:"command"`
+	Notification   *core.Notification `json:"notification"`
+}
+
+// Dial() creates new Websocket connection.
+func (s *fakeServer) Dial(url string, headers http.Header) (ws.Conn, error) {
+	return &fakeConn{server: s, inbox: make(chan []byte, 1024),
+		done: make(chan struct{})}, nil
+}
+
+// fake Websocket connection
+type fakeConn struct {
+	server    *fakeServer
+	inbox     chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// send message to the client
+func (conn *fakeConn) push(message fakeMessage) {
+	data, _ := json.Marshal(message)
+	select {
+	case conn.inbox <- data:
+	case <-conn.done:
+	}
+}
+
+// WriteMessage() handles the client request.
+func (conn *fakeConn) WriteMessage(messageType int, data []byte) error {
+	time.Sleep(fakeLatency)
+
+	var request fakeRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		return err
+	}
+
+	response := fakeMessage{"action": request.Action, "requestId": request.RequestId}
+	result, err := conn.serveWs(&request)
+	if err != nil {
+		response["status"] = "error"
+		response["code"] = err.code
+		response["error"] = err.message
+	} else {
+		response["status"] = "success"
+		for k, v := range result {
+			response[k] = v
+		}
+	}
+
+	conn.push(response)
+	return nil
+}
+
+// dispatch Websocket request
+func (conn *fakeConn) serveWs(request *fakeRequest) (fakeMessage, *fakeError) {
+	s := conn.server
+	switch request.Action {
+	case "server/info":
+		return fakeMessage{"info": s.info()}, nil
+
+	case "device/save":
+		return nil, s.registerDevice(request.DeviceId, request.Device)
+
+	case "device/get":
+		device, err := s.getDevice(request.DeviceId)
+		return fakeMessage{"device": device}, err
+
+	case "notification/insert":
+		notification, err := s.insertNotification(request.DeviceId, request.Notification)
+		return fakeMessage{"notification": notification}, err
+
+	case "notification/get":
+		notification, err := s.getNotification(request.DeviceId, request.NotificationId)
+		return fakeMessage{"notification": notification}, err
+
+	case "command/insert":
+		command, err := s.insertCommand(request.DeviceGuid, request.Command)
+		return fakeMessage{"command": command}, err
+
+	case "command/update":
+		return nil, s.updateCommand(request.DeviceId, request.CommandId, request.Command)
+
+	case "command/get":
+		command, err := s.getCommand(request.DeviceId, request.CommandId)
+		return fakeMessage{"command": command}, err
+
+	case "command/subscribe":
+		s.subscribe(conn, request.DeviceId, request.Timestamp)
+		return nil, nil
+
+	case "command/unsubscribe":
+		s.unsubscribe(conn, request.DeviceId)
+		return nil, nil
+	}
+
+	return nil, &fakeError{http.StatusBadRequest, fmt.Sprintf("unknown action %q", request.Action)}
+}
+
+// ReadMessage() returns the next message for the client.
+func (conn *fakeConn) ReadMessage() (messageType int, data []byte, err error) {
+	select {
+	case data = <-conn.inbox:
+		return websocket.TextMessage, data, nil
+	case <-conn.done:
+		return 0, nil, io.EOF
+	}
+}
+
+// Close() closes the connection.
+func (conn *fakeConn) Close() error {
+	conn.closeOnce.Do(func() { close(conn.done) })
+	return nil
+}
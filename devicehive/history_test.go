@@ -0,0 +1,116 @@
+package devicehive
+
+import (
+	"encoding/json"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"github.com/devicehive/devicehive-go/devicehive/record"
+	"github.com/devicehive/devicehive-go/devicehive/rest"
+	"github.com/devicehive/devicehive-go/devicehive/ws"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Test command and notification history on REST transport
+func TestRestHistory(t *testing.T) {
+	var queries []string
+	var lock sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		lock.Unlock()
+
+		take, _ := strconv.Atoi(r.URL.Query().Get("take"))
+		skip, _ := strconv.Atoi(r.URL.Query().Get("skip"))
+		var items []interface{}
+		for i := skip; i < 5 && i < skip+take; i++ {
+			switch r.URL.Path {
+			case "/device/dev-1/command":
+				items = append(items, core.Command{Id: uint64(i + 1), Name: "cmd", Status: "Done"})
+			case "/device/dev-1/notification":
+				items = append(items, core.Notification{Id: uint64(i + 1), Name: "ntf"})
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(items)
+	}))
+	defer server.Close()
+
+	service, err := rest.NewService(server.URL, "")
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+	device := core.NewDevice("dev-1", "name", nil)
+
+	filter := &core.CommandFilter{Start: core.MustParseTimestamp("2016-01-02T03:04:05"),
+		Command: "cmd", Status: "Done", SortField: "timestamp", SortOrder: core.SortAsc}
+	it := service.IterateCommands(device, filter, 2, testWaitTimeout)
+	count := 0
+	for it.Next() {
+		if count++; it.Command().Id != uint64(count) {
+			t.Errorf("unexpected command %s", it.Command())
+		}
+	}
+	if it.Err() != nil || count != 5 || len(queries) != 4 {
+		t.Fatalf("unexpected result: count=%d, queries=%q (error: %v)", count, queries, it.Err())
+	}
+	if q := queries[2]; q != "/device/dev-1/command?command=cmd&skip=4&sortField=timestamp&sortOrder=ASC&start=2016-01-02T03%3A04%3A05&status=Done&take=2" {
+		t.Errorf("unexpected query %q", q)
+	}
+
+	notifications, err := service.GetNotificationList(device,
+		&core.NotificationFilter{Notification: "ntf", Take: 3}, testWaitTimeout)
+	if err != nil || len(notifications) != 3 || notifications[2].Id != 3 {
+		t.Errorf("unexpected notifications %v (error: %v)", notifications, err)
+	}
+	if q := queries[4]; q != "/device/dev-1/notification?notification=ntf&take=3" {
+		t.Errorf("unexpected query %q", q)
+	}
+}
+
+// Websocket cassette with history actions
+const testWsHistoryCassette = `
+{"kind":"ws","dir":"dial","url":"ws://localhost/device"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"command/list\",\"requestId\":1,\"deviceId\":\"dev-1\",\"command\":\"cmd\",\"take\":2}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"command/list\",\"requestId\":1,\"status\":\"success\",\"commands\":[{\"id\":1,\"command\":\"cmd\"},{\"id\":2,\"command\":\"cmd\"}]}"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"command/list\",\"requestId\":2,\"deviceId\":\"dev-1\",\"command\":\"cmd\",\"take\":2,\"skip\":2}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"command/list\",\"requestId\":2,\"status\":\"success\",\"commands\":[{\"id\":3,\"command\":\"cmd\"}]}"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"command/list\",\"requestId\":3,\"deviceId\":\"dev-1\",\"command\":\"cmd\",\"take\":2,\"skip\":3}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"command/list\",\"requestId\":3,\"status\":\"success\",\"commands\":[]}"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"notification/list\",\"requestId\":4,\"deviceId\":\"dev-1\"}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"notification/list\",\"requestId\":4,\"status\":\"error\",\"code\":400,\"error\":\"Unknown action\"}"}
+`
+
+// Test command and notification history through the Service interface on Websocket transport
+func TestWebsocketHistory(t *testing.T) {
+	player, err := record.NewPlayer(strings.NewReader(testWsHistoryCassette))
+	if err != nil {
+		t.Fatalf("Failed to load cassette (error: %s)", err)
+	}
+
+	ws_service, err := ws.NewServiceDial("ws://localhost", "", player.Dial())
+	if err != nil {
+		t.Fatalf("Failed to create WS service (error: %s)", err)
+	}
+	var service HistoryService = ws_service
+	device := core.NewDevice("dev-1", "name", nil)
+
+	it := service.IterateCommands(device, &core.CommandFilter{Command: "cmd"}, 2, testWaitTimeout)
+	count := 0
+	for it.Next() {
+		if count++; it.Command().Id != uint64(count) || it.Command().Name != "cmd" {
+			t.Errorf("Unexpected command %s", it.Command())
+		}
+	}
+	if it.Err() != nil || count != 3 {
+		t.Errorf("Unexpected result: count=%d (error: %v)", count, it.Err())
+	}
+
+	_, err = service.GetNotificationList(device, nil, testWaitTimeout)
+	if err == nil || !strings.Contains(err.Error(), "Unknown action") {
+		t.Errorf("Unsupported action error expected, got %v", err)
+	}
+}
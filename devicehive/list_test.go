@@ -0,0 +1,136 @@
+package devicehive
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"github.com/devicehive/devicehive-go/devicehive/rest"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// server with a number of devices and networks
+// paging is done with "take" and "skip" parameters
+// non-zero maxTake limits the page size like some servers do
+func testNewListServer(count, maxTake int, queries *[]string, lock *sync.Mutex) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		*queries = append(*queries, r.URL.RawQuery)
+		lock.Unlock()
+
+		take, _ := strconv.Atoi(r.URL.Query().Get("take"))
+		skip, _ := strconv.Atoi(r.URL.Query().Get("skip"))
+		if take == 0 {
+			take = 1000
+		}
+		if maxTake > 0 && take > maxTake {
+			take = maxTake
+		}
+		var items []interface{}
+		for i := skip; i < count && i < skip+take; i++ {
+			switch r.URL.Path {
+			case "/device":
+				items = append(items, core.Device{Id: fmt.Sprintf("dev-%d", i), Name: "name"})
+			case "/network":
+				items = append(items, core.Network{Id: uint64(i + 1), Name: "name"})
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(items)
+	}))
+}
+
+// Test device list filter and iterator
+func TestDeviceIterator(t *testing.T) {
+	var queries []string
+	var lock sync.Mutex
+	server := testNewListServer(250, 0, &queries, &lock)
+	defer server.Close()
+
+	service, err := rest.NewService(server.URL, "")
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	filter := &core.DeviceFilter{NamePattern: "go-%", Status: "Online",
+		NetworkId: 7, SortField: "name", SortOrder: core.SortDesc, Skip: 10}
+	it := service.IterateDevices(filter, 100, time.Second)
+	count := 0
+	for it.Next() {
+		if id := fmt.Sprintf("dev-%d", count+10); it.Device().Id != id {
+			t.Fatalf("unexpected device %s, expected %q", it.Device(), id)
+		}
+		count++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("iterator failed: %s", err)
+	}
+	if count != 240 {
+		t.Errorf("unexpected device count: %d", count)
+	}
+
+	expected := []string{
+		"namePattern=go-%25&networkId=7&skip=10&sortField=name&sortOrder=DESC&status=Online&take=100",
+		"namePattern=go-%25&networkId=7&skip=110&sortField=name&sortOrder=DESC&status=Online&take=100",
+		"namePattern=go-%25&networkId=7&skip=210&sortField=name&sortOrder=DESC&status=Online&take=100",
+		"namePattern=go-%25&networkId=7&skip=250&sortField=name&sortOrder=DESC&status=Online&take=100",
+	}
+	if fmt.Sprint(queries) != fmt.Sprint(expected) {
+		t.Errorf("unexpected queries:\n%q\nexpected:\n%q", queries, expected)
+	}
+}
+
+// Test network iterator with total limit
+func TestNetworkIterator(t *testing.T) {
+	var queries []string
+	var lock sync.Mutex
+	server := testNewListServer(250, 0, &queries, &lock)
+	defer server.Close()
+
+	service, err := rest.NewService(server.URL, "")
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	it := service.IterateNetworks(&core.NetworkFilter{Name: "name", Take: 25}, 10, time.Second)
+	count := 0
+	for it.Next() {
+		count++
+		if it.Network().Id != uint64(count) {
+			t.Fatalf("unexpected network %s", it.Network())
+		}
+	}
+	if it.Err() != nil || count != 25 || len(queries) != 3 || queries[2] != "name=name&skip=20&take=5" {
+		t.Errorf("unexpected result: count=%d, queries=%q (error: %v)", count, queries, it.Err())
+	}
+	if it.Next() || it.Network() != nil {
+		t.Errorf("iterator should be exhausted")
+	}
+}
+
+// Test iterator doesn't stop on a page truncated by the server
+func TestDeviceIteratorShortPage(t *testing.T) {
+	var queries []string
+	var lock sync.Mutex
+	server := testNewListServer(120, 50, &queries, &lock)
+	defer server.Close()
+
+	service, err := rest.NewService(server.URL, "")
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	it := service.IterateDevices(nil, 100, time.Second)
+	count := 0
+	for it.Next() {
+		count++
+	}
+	expected := []string{"take=100", "skip=50&take=100", "skip=100&take=100", "skip=120&take=100"}
+	if it.Err() != nil || count != 120 || fmt.Sprint(queries) != fmt.Sprint(expected) {
+		t.Errorf("unexpected result: count=%d, queries=%q (error: %v)", count, queries, it.Err())
+	}
+}
@@ -8,6 +8,7 @@ package log
 import (
 	"log"
 	"strings"
+	"sync/atomic"
 )
 
 // additional logging level type
@@ -21,14 +22,29 @@ const (
 	TRACE
 )
 
-// global logging level
-var logLevel = INFO
+// global logging level, INFO by default
+var logLevel atomic.Int32
 
 // initialize standard logger
 func init() {
+	logLevel.Store(int32(INFO))
 	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
 }
 
+// get global logging level
+func globalLevel() Level {
+	return Level(logLevel.Load())
+}
+
+// logger of global Warnf(), Infof(), etc.
+var std = NewEntry(nil, CORE)
+
+// SetLogger() changes the backend of global Warnf(), Infof(), etc.
+// StdLogger is used if backend is nil.
+func SetLogger(backend Logger) {
+	std.SetLogger(backend)
+}
+
 // convert custom logging level to string
 // return "UNKNOWN" for unknown levels
 func (level Level) String() string {
@@ -69,10 +85,9 @@ func parseLevel(name string) Level {
 }
 
 // SetLevel() sets global logging level
+// It might be changed at any time.
 func SetLevel(level Level) {
-	if logLevel != level {
-		old := logLevel
-		logLevel = level
+	if old := Level(logLevel.Swap(int32(level))); old != level {
 		Debugf("logging level changed from %q to %q", old, level)
 	}
 }
@@ -120,12 +135,11 @@ func Alwaysf(format string, v ...interface{}) {
 //	}
 //}
 
-// Warnf() is the same as log.Printf() with the "WARN: " prefix
+// Warnf() is the same as log.Printf() with the "WARN: " prefix by default,
+// the backend might be changed with SetLogger()
 // the message is printed if logging level is greater or equal to WARN
 func Warnf(format string, v ...interface{}) {
-	if logLevel >= WARN {
-		log.Printf("WARN "+format, v...)
-	}
+	std.Warnf(format, v...)
 }
 
 // Info() is the same as log.Print() with the "INFO: " prefix
@@ -136,12 +150,11 @@ func Warnf(format string, v ...interface{}) {
 //	}
 //}
 
-// Infof() is the same as log.Printf() with the "INFO: " prefix
+// Infof() is the same as log.Printf() with the "INFO: " prefix by default,
+// the backend might be changed with SetLogger()
 // the message is printed if logging level is greater or equal to INFO
 func Infof(format string, v ...interface{}) {
-	if logLevel >= INFO {
-		log.Printf("INFO "+format, v...)
-	}
+	std.Infof(format, v...)
 }
 
 // Debug() is the same as log.Print() with the "DEBUG: " prefix
@@ -152,12 +165,11 @@ func Infof(format string, v ...interface{}) {
 //	}
 //}
 
-// Debugf() is the same as log.Printf() with the "DEBUG: " prefix
+// Debugf() is the same as log.Printf() with the "DEBUG: " prefix by default,
+// the backend might be changed with SetLogger()
 // the message is printed if logging level is greater or equal to DEBUG
 func Debugf(format string, v ...interface{}) {
-	if logLevel >= DEBUG {
-		log.Printf("DEBUG "+format, v...)
-	}
+	std.Debugf(format, v...)
 }
 
 // Trace() is the same as log.Print() with the "TRACE: " prefix
@@ -168,10 +180,9 @@ func Debugf(format string, v ...interface{}) {
 //	}
 //}
 
-// Tracef() is the same as log.Printf() with the "TRACE: " prefix
+// Tracef() is the same as log.Printf() with the "TRACE: " prefix by default,
+// the backend might be changed with SetLogger()
 // the message is printed if logging level is greater or equal to TRACE
 func Tracef(format string, v ...interface{}) {
-	if logLevel >= TRACE {
-		log.Printf("TRACE "+format, v...)
-	}
+	std.Tracef(format, v...)
 }
@@ -0,0 +1,199 @@
+package log
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Logger is a pluggable logging backend.
+// Level filtering is done before the backend is called,
+// see SetComponentLevel().
+type Logger interface {
+	// Log writes the message with structured fields.
+	Log(level Level, msg string, fields []Field)
+}
+
+// Structured logging field.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Any creates a field with arbitrary key.
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Transport creates "transport" field.
+func Transport(name string) Field {
+	return Field{Key: "transport", Value: name}
+}
+
+// DeviceId creates "deviceId" field.
+func DeviceId(id string) Field {
+	return Field{Key: "deviceId", Value: id}
+}
+
+// Action creates "action" field.
+func Action(name string) Field {
+	return Field{Key: "action", Value: name}
+}
+
+// RequestId creates "requestId" field.
+func RequestId(id uint64) Field {
+	return Field{Key: "requestId", Value: id}
+}
+
+// Duration creates "duration" field.
+func Duration(d time.Duration) Field {
+	return Field{Key: "duration", Value: d}
+}
+
+// StdLogger is the default backend.
+// It prints messages using standard "log" package
+// the same way as Warnf(), Infof(), etc. do.
+// Fields are appended as key=value pairs.
+type StdLogger struct{}
+
+// Log writes the message using standard "log" package.
+func (StdLogger) Log(level Level, msg string, fields []Field) {
+	log.Print(formatText(level, msg, fields))
+}
+
+// format message as "LEVEL message key=value..."
+func formatText(level Level, msg string, fields []Field) string {
+	var buf strings.Builder
+	buf.WriteString(level.String())
+	buf.WriteByte(' ')
+	buf.WriteString(msg)
+	for _, f := range fields {
+		fmt.Fprintf(&buf, " %s=%v", f.Key, f.Value)
+	}
+	return buf.String()
+}
+
+// Logging components.
+const (
+	CORE = "core" // global Warnf(), Infof(), etc.
+	REST = "rest"
+	WS   = "ws"
+)
+
+// per-component logging levels
+var (
+	componentLevels    = make(map[string]Level)
+	componentLevelLock sync.RWMutex
+)
+
+// SetComponentLevel() sets logging level of the component.
+// It might be changed at any time.
+func SetComponentLevel(component string, level Level) {
+	componentLevelLock.Lock()
+	defer componentLevelLock.Unlock()
+	componentLevels[component] = level
+}
+
+// SetComponentLevelByName() sets logging level of the component using string name.
+func SetComponentLevelByName(component string, name string) {
+	SetComponentLevel(component, parseLevel(name))
+}
+
+// ResetComponentLevel() removes the component level,
+// so the global logging level is used.
+func ResetComponentLevel(component string) {
+	componentLevelLock.Lock()
+	defer componentLevelLock.Unlock()
+	delete(componentLevels, component)
+}
+
+// ComponentLevel() gets logging level of the component.
+// Global logging level is used if the component level is not set.
+func ComponentLevel(component string) Level {
+	componentLevelLock.RLock()
+	defer componentLevelLock.RUnlock()
+	if level, ok := componentLevels[component]; ok {
+		return level
+	}
+	return globalLevel()
+}
+
+// Entry is a component logger with a set of structured fields.
+// All messages are passed to the backend.
+type Entry struct {
+	component string
+	fields    []Field
+	backend   *atomic.Value // of loggerBox, shared by derived entries
+}
+
+// the same concrete type is required by atomic.Value
+type loggerBox struct {
+	Logger
+}
+
+// NewEntry creates a new component logger.
+// StdLogger is used if backend is nil.
+func NewEntry(backend Logger, component string, fields ...Field) *Entry {
+	entry := &Entry{component: component, fields: fields,
+		backend: &atomic.Value{}}
+	entry.SetLogger(backend)
+	return entry
+}
+
+// SetLogger() changes the backend of the entry and all derived entries.
+// StdLogger is used if backend is nil.
+func (entry *Entry) SetLogger(backend Logger) {
+	if backend == nil {
+		backend = StdLogger{}
+	}
+	entry.backend.Store(loggerBox{backend})
+}
+
+// Logger() gets the current backend.
+func (entry *Entry) Logger() Logger {
+	return entry.backend.Load().(loggerBox).Logger
+}
+
+// With() creates a derived entry with additional fields.
+func (entry *Entry) With(fields ...Field) *Entry {
+	all := make([]Field, 0, len(entry.fields)+len(fields))
+	all = append(all, entry.fields...)
+	all = append(all, fields...)
+	return &Entry{component: entry.component, fields: all,
+		backend: entry.backend}
+}
+
+// Enabled() checks if messages of the level are logged.
+func (entry *Entry) Enabled(level Level) bool {
+	return level <= ComponentLevel(entry.component)
+}
+
+// log the message if level is enabled
+func (entry *Entry) logf(level Level, format string, v []interface{}) {
+	if entry.Enabled(level) {
+		entry.Logger().Log(level, fmt.Sprintf(format, v...), entry.fields)
+	}
+}
+
+// Warnf() logs the message with WARN level.
+func (entry *Entry) Warnf(format string, v ...interface{}) {
+	entry.logf(WARN, format, v)
+}
+
+// Infof() logs the message with INFO level.
+func (entry *Entry) Infof(format string, v ...interface{}) {
+	entry.logf(INFO, format, v)
+}
+
+// Debugf() logs the message with DEBUG level.
+func (entry *Entry) Debugf(format string, v ...interface{}) {
+	entry.logf(DEBUG, format, v)
+}
+
+// Tracef() logs the message with TRACE level.
+func (entry *Entry) Tracef(format string, v ...interface{}) {
+	entry.logf(TRACE, format, v)
+}
@@ -0,0 +1,122 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// RotatingFile is a size-rotated log file.
+// When the file exceeds the maximum size it's renamed to "path.1",
+// the previous "path.1" is renamed to "path.2" and so on.
+// Only the given number of backups is kept.
+// It could be used with NewTextLogger() or NewJSONLogger().
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	lock sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewRotatingFile opens (or creates) the log file for appending.
+func NewRotatingFile(path string, maxSize int64, maxBackups int) (rf *RotatingFile, err error) {
+	rf = &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	err = rf.open()
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// open the log file for appending
+func (rf *RotatingFile) open() error {
+	file, err := os.OpenFile(rf.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	rf.file = file
+	rf.size = info.Size()
+	return nil
+}
+
+// Write appends data to the log file, the file is rotated if needed.
+func (rf *RotatingFile) Write(p []byte) (n int, err error) {
+	rf.lock.Lock()
+	defer rf.lock.Unlock()
+
+	if rf.file == nil {
+		return 0, os.ErrClosed
+	}
+	if rf.maxSize > 0 && rf.size > 0 && rf.size+int64(len(p)) > rf.maxSize {
+		if err = rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err = rf.file.Write(p)
+	rf.size += int64(n)
+	return
+}
+
+// rotate the log file
+// the log file is reopened on any error, so logging could continue
+func (rf *RotatingFile) rotate() (err error) {
+	if err = rf.file.Close(); err != nil {
+		return err
+	}
+	rf.file = nil
+
+	if err = rf.shift(); err != nil {
+		if e := rf.open(); e != nil {
+			return fmt.Errorf("%s (reopen failed: %s)", err, e)
+		}
+		return err
+	}
+
+	return rf.open()
+}
+
+// shift backups and move the log file away
+func (rf *RotatingFile) shift() error {
+	if rf.maxBackups <= 0 {
+		return os.Remove(rf.path)
+	}
+
+	if err := os.Remove(rf.backupPath(rf.maxBackups)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := rf.maxBackups - 1; i > 0; i-- {
+		err := os.Rename(rf.backupPath(i), rf.backupPath(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(rf.path, rf.backupPath(1))
+}
+
+// get path of the backup file
+func (rf *RotatingFile) backupPath(index int) string {
+	return fmt.Sprintf("%s.%d", rf.path, index)
+}
+
+// Close closes the log file.
+func (rf *RotatingFile) Close() error {
+	rf.lock.Lock()
+	defer rf.lock.Unlock()
+
+	if rf.file == nil {
+		return nil
+	}
+	err := rf.file.Close()
+	rf.file = nil
+	return err
+}
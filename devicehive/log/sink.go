@@ -0,0 +1,255 @@
+package log
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Timestamp layout used by text sinks,
+// the same as standard "log" with microseconds.
+const TextTimeLayout = "2006/01/02 15:04:05.000000"
+
+// Single log record.
+type Record struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Fields  []Field
+}
+
+// Get Record string representation, the same as text sink writes.
+func (record Record) String() string {
+	return record.Time.Format(TextTimeLayout) + " " +
+		formatText(record.Level, record.Message, record.Fields)
+}
+
+// MarshalJSON() encodes record as JSON object.
+// Fields are put at the top level next to "time", "level" and "msg".
+func (record Record) MarshalJSON() ([]byte, error) {
+	obj := make(map[string]interface{}, len(record.Fields)+3)
+	for _, f := range record.Fields {
+		obj[f.Key] = jsonValue(f.Value)
+	}
+	obj["time"] = record.Time.Format(time.RFC3339Nano)
+	obj["level"] = record.Level.String()
+	obj["msg"] = record.Message
+	return json.Marshal(obj)
+}
+
+// convert field value to be JSON friendly
+func jsonValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case json.Marshaler:
+		return x
+	case error:
+		return x.Error()
+	case fmt.Stringer:
+		return x.String()
+	}
+	return v
+}
+
+// text lines sink
+type textLogger struct {
+	lock sync.Mutex
+	w    io.Writer
+}
+
+// NewTextLogger creates a backend that writes text lines
+// "time LEVEL message key=value..." to the writer.
+func NewTextLogger(w io.Writer) Logger {
+	return &textLogger{w: w}
+}
+
+// Log writes the message as a text line.
+func (l *textLogger) Log(level Level, msg string, fields []Field) {
+	line := Record{Time: time.Now(), Level: level,
+		Message: msg, Fields: fields}.String() + "\n"
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	io.WriteString(l.w, line)
+}
+
+// JSON lines sink
+type jsonLogger struct {
+	lock sync.Mutex
+	w    io.Writer
+}
+
+// NewJSONLogger creates a backend that writes JSON lines to the writer.
+// Each line is a JSON object with "time", "level", "msg" and all the fields.
+func NewJSONLogger(w io.Writer) Logger {
+	return &jsonLogger{w: w}
+}
+
+// Log writes the message as a JSON line.
+func (l *jsonLogger) Log(level Level, msg string, fields []Field) {
+	line, err := Record{Time: time.Now(), Level: level,
+		Message: msg, Fields: fields}.MarshalJSON()
+	if err != nil {
+		line, _ = json.Marshal(map[string]string{"level": level.String(),
+			"msg": msg, "error": err.Error()})
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.w.Write(append(line, '\n'))
+}
+
+// fan-out sink
+type multiLogger []Logger
+
+// NewMultiLogger creates a backend that writes to all the backends.
+func NewMultiLogger(backends ...Logger) Logger {
+	return multiLogger(backends)
+}
+
+// Log writes the message to all the backends.
+func (l multiLogger) Log(level Level, msg string, fields []Field) {
+	for _, backend := range l {
+		backend.Log(level, msg, fields)
+	}
+}
+
+// RingBuffer keeps the last N log records in memory.
+type RingBuffer struct {
+	lock    sync.Mutex
+	records []Record
+	next    int  // position of the next record
+	full    bool // all records are used
+}
+
+// NewRingBuffer creates a ring buffer of the given size.
+func NewRingBuffer(size int) *RingBuffer {
+	if size < 1 {
+		size = 1
+	}
+	return &RingBuffer{records: make([]Record, size)}
+}
+
+// Log stores the message, the oldest one is dropped if buffer is full.
+func (ring *RingBuffer) Log(level Level, msg string, fields []Field) {
+	record := Record{Time: time.Now(), Level: level, Message: msg,
+		Fields: append([]Field(nil), fields...)}
+
+	ring.lock.Lock()
+	defer ring.lock.Unlock()
+	ring.records[ring.next] = record
+	ring.next = (ring.next + 1) % len(ring.records)
+	if ring.next == 0 {
+		ring.full = true
+	}
+}
+
+// Records() gets all the stored records, the oldest first.
+func (ring *RingBuffer) Records() []Record {
+	ring.lock.Lock()
+	defer ring.lock.Unlock()
+
+	if !ring.full {
+		return append([]Record(nil), ring.records[:ring.next]...)
+	}
+	res := make([]Record, 0, len(ring.records))
+	res = append(res, ring.records[ring.next:]...)
+	return append(res, ring.records[:ring.next]...)
+}
+
+// Reset() removes all the stored records.
+func (ring *RingBuffer) Reset() {
+	ring.lock.Lock()
+	defer ring.lock.Unlock()
+	for i := range ring.records {
+		ring.records[i] = Record{}
+	}
+	ring.next = 0
+	ring.full = false
+}
+
+// Dump() writes all the stored records as text lines, the oldest first.
+func (ring *RingBuffer) Dump(w io.Writer) error {
+	for _, record := range ring.Records() {
+		if _, err := fmt.Fprintln(w, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// maximum number of distinct messages tracked by rate limiter
+const rateLimitKeys = 1024
+
+// repeated message state
+type repeatState struct {
+	msg        string
+	last       time.Time // last time the message was passed
+	suppressed int       // number of suppressed repeats
+}
+
+// RateLimiter suppresses repeated identical warnings.
+// The least recently seen messages are forgotten
+// if there are too many distinct ones.
+type RateLimiter struct {
+	backend  Logger
+	interval time.Duration
+
+	lock sync.Mutex
+	seen map[string]*list.Element // of *repeatState
+	lru  *list.List               // most recently seen first
+}
+
+// NewRateLimiter creates a backend that passes identical WARN messages
+// to the backend at most once per interval. The number of suppressed
+// repeats is reported in "repeated" field of the next passed message.
+// Messages of other levels are passed as is.
+func NewRateLimiter(backend Logger, interval time.Duration) *RateLimiter {
+	return &RateLimiter{backend: backend, interval: interval,
+		seen: make(map[string]*list.Element), lru: list.New()}
+}
+
+// Log passes the message to the backend unless it's repeated too often.
+func (l *RateLimiter) Log(level Level, msg string, fields []Field) {
+	if level != WARN || l.interval <= 0 {
+		l.backend.Log(level, msg, fields)
+		return
+	}
+
+	now := time.Now()
+	l.lock.Lock()
+	state := l.touch(msg)
+	if !state.last.IsZero() && now.Sub(state.last) < l.interval {
+		state.suppressed++
+		l.lock.Unlock()
+		return
+	}
+	suppressed := state.suppressed
+	state.last = now
+	state.suppressed = 0
+	l.lock.Unlock()
+
+	if suppressed != 0 {
+		fields = append(fields[:len(fields):len(fields)], Any("repeated", suppressed))
+	}
+	l.backend.Log(level, msg, fields)
+}
+
+// get the message state and mark it as the most recently seen
+// the least recently seen message is removed if there are too many of them
+func (l *RateLimiter) touch(msg string) *repeatState {
+	if elem, ok := l.seen[msg]; ok {
+		l.lru.MoveToFront(elem)
+		return elem.Value.(*repeatState)
+	}
+
+	if l.lru.Len() >= rateLimitKeys {
+		oldest := l.lru.Remove(l.lru.Back()).(*repeatState)
+		delete(l.seen, oldest.msg)
+	}
+	state := &repeatState{msg: msg}
+	l.seen[msg] = l.lru.PushFront(state)
+	return state
+}
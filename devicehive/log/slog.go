@@ -0,0 +1,48 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+)
+
+// slog level used for TRACE messages.
+const SlogLevelTrace = slog.LevelDebug - 4
+
+// slog adapter
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger creates a backend that writes to the slog logger.
+// The default slog logger is used if logger is nil.
+func NewSlogLogger(logger *slog.Logger) Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &slogLogger{logger: logger}
+}
+
+// Log writes the message with fields as slog attributes.
+func (l *slogLogger) Log(level Level, msg string, fields []Field) {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, f := range fields {
+		attrs = append(attrs, slog.Any(f.Key, f.Value))
+	}
+	l.logger.LogAttrs(context.Background(), SlogLevel(level), msg, attrs...)
+}
+
+// SlogLevel converts logging level to slog level.
+func SlogLevel(level Level) slog.Level {
+	switch level {
+	case WARN:
+		return slog.LevelWarn
+	case INFO:
+		return slog.LevelInfo
+	case DEBUG:
+		return slog.LevelDebug
+	case TRACE:
+		return SlogLevelTrace
+	}
+
+	return slog.LevelError
+}
@@ -0,0 +1,37 @@
+//go:build !windows && !plan9
+
+package log
+
+import (
+	"log/syslog"
+)
+
+// syslog sink
+type syslogLogger struct {
+	w *syslog.Writer
+}
+
+// NewSyslogLogger creates a backend that forwards messages
+// to the local syslog daemon with the given tag.
+// Levels are mapped to syslog severities: WARN - warning,
+// INFO - info, DEBUG and TRACE - debug.
+func NewSyslogLogger(tag string) (Logger, error) {
+	w, err := syslog.New(syslog.LOG_USER|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogLogger{w: w}, nil
+}
+
+// Log forwards the message to syslog.
+func (l *syslogLogger) Log(level Level, msg string, fields []Field) {
+	line := formatText(level, msg, fields)
+	switch level {
+	case WARN:
+		l.w.Warning(line)
+	case INFO:
+		l.w.Info(line)
+	default:
+		l.w.Debug(line)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build windows || plan9
+
+package log
+
+import "fmt"
+
+// NewSyslogLogger is not supported on this platform.
+func NewSyslogLogger(tag string) (Logger, error) {
+	return nil, fmt.Errorf("syslog is not supported")
+}
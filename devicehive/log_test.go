@@ -0,0 +1,258 @@
+package devicehive
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// captures log messages
+type testLogger struct {
+	sync.Mutex
+	messages []string
+	fields   []map[string]interface{}
+}
+
+// Log stores the message and fields
+func (l *testLogger) Log(level log.Level, msg string, fields []log.Field) {
+	l.Lock()
+	defer l.Unlock()
+	m := make(map[string]interface{})
+	for _, f := range fields {
+		m[f.Key] = f.Value
+	}
+	l.messages = append(l.messages, level.String()+" "+msg)
+	l.fields = append(l.fields, m)
+}
+
+// Test per-instance logger with structured fields
+func TestLoggerPerService(t *testing.T) {
+	server := testNewInfoServer()
+	defer server.Close()
+
+	a, _ := NewRestService(server.URL, "")
+	b, _ := NewRestService(server.URL, "")
+	la, lb := &testLogger{}, &testLogger{}
+	a.(LoggerService).SetLogger(la)
+	b.(LoggerService).SetLogger(lb)
+
+	log.SetComponentLevel(log.REST, log.TRACE)
+	defer log.ResetComponentLevel(log.REST)
+
+	if _, err := a.GetServerInfo(time.Second); err != nil {
+		t.Fatalf("failed to get server info: %s", err)
+	}
+	if len(la.messages) == 0 || len(lb.messages) != 0 {
+		t.Fatalf("unexpected messages: %q, %q", la.messages, lb.messages)
+	}
+
+	found := false
+	for _, f := range la.fields {
+		if f["transport"] != "rest" {
+			t.Errorf("no transport field: %v", f)
+		}
+		if _, ok := f["duration"]; ok && f["action"] == "GET /info" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("no action and duration fields: %v", la.fields)
+	}
+
+	// runtime level change
+	log.SetComponentLevel(log.REST, log.WARN)
+	n := len(la.messages)
+	if _, err := a.GetServerInfo(time.Second); err != nil {
+		t.Fatalf("failed to get server info: %s", err)
+	}
+	if len(la.messages) != n {
+		t.Errorf("unexpected messages: %q", la.messages[n:])
+	}
+}
+
+// Test slog adapter
+func TestLoggerSlog(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: log.SlogLevelTrace})
+	entry := log.NewEntry(log.NewSlogLogger(slog.New(handler)), log.CORE,
+		log.Transport("ws")).With(log.RequestId(7), log.Action("server/info"))
+
+	log.SetComponentLevel(log.CORE, log.TRACE)
+	defer log.ResetComponentLevel(log.CORE)
+	entry.Tracef("hello %d", 42)
+
+	var rec map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("failed to parse %q: %s", buf.String(), err)
+	}
+	if rec["msg"] != "hello 42" || rec["transport"] != "ws" ||
+		rec["requestId"] != 7.0 || rec["action"] != "server/info" ||
+		!strings.HasPrefix(rec["level"].(string), "DEBUG") {
+		t.Errorf("unexpected record: %v", rec)
+	}
+}
+
+// Test ring buffer keeps the last records
+func TestLogRingBuffer(t *testing.T) {
+	ring := log.NewRingBuffer(3)
+	for i := 0; i < 5; i++ {
+		ring.Log(log.INFO, fmt.Sprintf("msg-%d", i), []log.Field{log.RequestId(uint64(i))})
+	}
+
+	records := ring.Records()
+	if len(records) != 3 || records[0].Message != "msg-2" || records[2].Message != "msg-4" {
+		t.Fatalf("unexpected records: %v", records)
+	}
+
+	var buf bytes.Buffer
+	if err := ring.Dump(&buf); err != nil {
+		t.Fatalf("failed to dump: %s", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 3 ||
+		!strings.HasSuffix(lines[0], "INFO msg-2 requestId=2") {
+		t.Errorf("unexpected dump:\n%s", buf.String())
+	}
+}
+
+// Test repeated warnings are suppressed
+func TestLogRateLimiter(t *testing.T) {
+	ring := log.NewRingBuffer(256)
+	limiter := log.NewRateLimiter(ring, 50*time.Millisecond)
+	for i := 0; i < 100; i++ {
+		limiter.Log(log.WARN, "failed to poll", nil)
+		limiter.Log(log.DEBUG, "polling", nil)
+	}
+	limiter.Log(log.WARN, "other warning", nil)
+	time.Sleep(60 * time.Millisecond)
+	limiter.Log(log.WARN, "failed to poll", nil)
+
+	var warnings []log.Record
+	for _, r := range ring.Records() {
+		if r.Level == log.WARN {
+			warnings = append(warnings, r)
+		}
+	}
+	if len(warnings) != 3 || warnings[1].Message != "other warning" {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+	if f := warnings[2].Fields; len(f) != 1 || f[0].Key != "repeated" || f[0].Value != 99 {
+		t.Errorf("unexpected repeat count: %v", f)
+	}
+}
+
+// Test rate limiter forgets the least recently seen warnings
+func TestLogRateLimiterEviction(t *testing.T) {
+	ring := log.NewRingBuffer(4096)
+	limiter := log.NewRateLimiter(ring, time.Hour)
+	limiter.Log(log.WARN, "first", nil)
+	for i := 0; i < 2000; i++ { // more than tracked
+		limiter.Log(log.WARN, fmt.Sprintf("warning-%d", i), nil)
+	}
+	limiter.Log(log.WARN, "first", nil)
+
+	records := ring.Records()
+	if len(records) != 2002 || records[2001].Message != "first" {
+		t.Errorf("forgotten warning is suppressed: %d records", len(records))
+	}
+}
+
+// Test global functions use the configured backend
+func TestLogGlobalBackend(t *testing.T) {
+	l := &testLogger{}
+	log.SetLogger(l)
+	defer log.SetLogger(nil)
+	log.SetComponentLevel(log.CORE, log.INFO)
+	defer log.ResetComponentLevel(log.CORE)
+
+	log.Infof("hello %d", 42)
+	log.Debugf("hidden")
+	if len(l.messages) != 1 || l.messages[0] != "INFO hello 42" {
+		t.Errorf("unexpected messages: %q", l.messages)
+	}
+}
+
+// Test JSON lines and file rotation
+func TestLogJsonRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.log")
+	file, err := log.NewRotatingFile(path, 256, 2)
+	if err != nil {
+		t.Fatalf("failed to open log file: %s", err)
+	}
+	defer file.Close()
+
+	logger := log.NewJSONLogger(file)
+	for i := 0; i < 20; i++ {
+		logger.Log(log.WARN, "hello", []log.Field{log.Transport("rest"),
+			log.Duration(1500 * time.Millisecond), log.Any("error", fmt.Errorf("failed"))})
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("failed to read %s: %s", name, err)
+		}
+		if len(data) > 256 {
+			t.Errorf("%s is too big: %d", name, len(data))
+		}
+		var rec map[string]interface{}
+		line := strings.SplitN(string(data), "\n", 2)[0]
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("failed to parse %q: %s", line, err)
+		}
+		if rec["msg"] != "hello" || rec["level"] != "WARN" || rec["transport"] != "rest" ||
+			rec["duration"] != "1.5s" || rec["error"] != "failed" {
+			t.Errorf("unexpected record: %v", rec)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); err == nil {
+		t.Errorf("too many backups")
+	}
+}
+
+// rotation failure should not close the log file
+func TestLogRotatingFileFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.log")
+	file, err := log.NewRotatingFile(path, 16, 1)
+	if err != nil {
+		t.Fatalf("failed to open log file: %s", err)
+	}
+	defer file.Close()
+
+	// non-empty directory in place of the backup
+	if err := os.MkdirAll(filepath.Join(path+".1", "busy"), 0755); err != nil {
+		t.Fatalf("failed to create directory: %s", err)
+	}
+
+	if _, err := file.Write([]byte("first line\n")); err != nil {
+		t.Fatalf("failed to write: %s", err)
+	}
+	if _, err := file.Write([]byte("second line\n")); err == nil {
+		t.Errorf("rotation error expected")
+	}
+
+	// log file is still open, rotation works once the backup is free
+	if err := os.RemoveAll(path + ".1"); err != nil {
+		t.Fatalf("failed to remove directory: %s", err)
+	}
+	if _, err := file.Write([]byte("third line\n")); err != nil {
+		t.Fatalf("failed to write after rotation error: %s", err)
+	}
+
+	for name, expected := range map[string]string{path: "third line\n", path + ".1": "first line\n"} {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("failed to read %s: %s", name, err)
+		}
+		if string(data) != expected {
+			t.Errorf("%s: unexpected content %q", name, data)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package devicehive
+
+import (
+	"encoding/json"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"github.com/devicehive/devicehive-go/devicehive/rest"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// long-poll server with per-device command queues
+// every request waits until one of requested devices has commands
+type testMultiPollServer struct {
+	*httptest.Server
+
+	lock       sync.Mutex
+	queues     map[string][]core.Command
+	requests   []string // requested device sets
+	timestamps []string // requested timestamps
+}
+
+// server time reported by /info
+const testMultiPollServerTime = "2016-01-02T03:04:05.123456"
+
+// create new multi-device poll server
+func testNewMultiPollServer(t *testing.T) *testMultiPollServer {
+	s := &testMultiPollServer{queues: make(map[string][]core.Command)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/info" {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]string{
+				"apiVersion": "2.0", "serverTimestamp": testMultiPollServerTime})
+			return
+		}
+		if r.URL.Path != "/device/command/poll" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		ids := strings.Split(r.URL.Query().Get("deviceGuids"), ",")
+		s.lock.Lock()
+		s.requests = append(s.requests, strings.Join(ids, ","))
+		s.timestamps = append(s.timestamps, r.URL.Query().Get("timestamp"))
+		s.lock.Unlock()
+
+		for {
+			var res []rest.DeviceCommand
+			s.lock.Lock()
+			for _, id := range ids {
+				for _, cmd := range s.queues[id] {
+					res = append(res, rest.DeviceCommand{DeviceId: id, Command: cmd})
+				}
+				delete(s.queues, id)
+			}
+			s.lock.Unlock()
+
+			if len(res) != 0 {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(res)
+				return
+			}
+
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(5 * time.Millisecond):
+			}
+		}
+	}))
+	return s
+}
+
+// push command to the device queue
+func (s *testMultiPollServer) push(deviceId string, cmd core.Command) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.queues[deviceId] = append(s.queues[deviceId], cmd)
+}
+
+// get the last requested device set
+func (s *testMultiPollServer) lastRequest() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.requests) == 0 {
+		return ""
+	}
+	return s.requests[len(s.requests)-1]
+}
+
+// wait for the command on listener
+func testWaitCommand(t *testing.T, listener *core.CommandListener, id uint64) {
+	select {
+	case cmd := <-listener.C:
+		if cmd.Id != id {
+			t.Errorf("unexpected command %s, expected %d", cmd, id)
+		}
+	case <-time.After(testWaitTimeout):
+		t.Errorf("no command %d received", id)
+	}
+}
+
+// wait for the poll request with the device set
+func testWaitPollRequest(t *testing.T, server *testMultiPollServer, ids string) {
+	deadline := time.Now().Add(testWaitTimeout)
+	for server.lastRequest() != ids {
+		if time.Now().After(deadline) {
+			t.Fatalf("no poll request for %q, last is %q", ids, server.lastRequest())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// Test multi-device command subscription
+func TestMultiPollCommands(t *testing.T) {
+	server := testNewMultiPollServer(t)
+	defer server.Close()
+
+	service, err := rest.NewService(server.URL, "access-key")
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	dev1 := core.NewDevice("dev-1", "one", nil)
+	dev2 := core.NewDevice("dev-2", "two", nil)
+	sub := service.SubscribeCommandsMulti([]*core.Device{dev1}, core.Timestamp{}, "")
+	defer sub.Close()
+	testWaitPollRequest(t, server, "dev-1")
+
+	// device is added at runtime, the poll is restarted
+	l2 := sub.Add(dev2)
+	testWaitPollRequest(t, server, "dev-1,dev-2")
+	if sub.Add(dev2).C != l2.C {
+		t.Errorf("new listener is created for the same device")
+	}
+
+	server.push("dev-2", core.Command{Id: 2, Name: "two"})
+	server.push("dev-1", core.Command{Id: 1, Name: "one"})
+	testWaitCommand(t, sub.Listener("dev-1"), 1)
+	testWaitCommand(t, l2, 2)
+
+	// device is removed at runtime
+	sub.Remove(dev1)
+	testWaitPollRequest(t, server, "dev-2")
+	if sub.Listener("dev-1") != nil {
+		t.Errorf("removed device still has listener")
+	}
+	if ids := sub.Devices(); len(ids) != 1 || ids[0] != "dev-2" {
+		t.Errorf("unexpected devices %q", ids)
+	}
+
+	server.push("dev-2", core.Command{Id: 3, Name: "three"})
+	testWaitCommand(t, l2, 3)
+
+	// "now" is the server time, not re-sent as zero timestamp
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	for _, ts := range server.timestamps {
+		if ts != testMultiPollServerTime {
+			t.Errorf("unexpected poll timestamps %q", server.timestamps)
+			break
+		}
+	}
+}
+
+// Test stalled listener doesn't block other devices and listeners are closed
+func TestMultiPollStalledListener(t *testing.T) {
+	server := testNewMultiPollServer(t)
+	defer server.Close()
+
+	service, err := rest.NewService(server.URL, "access-key")
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	dev1 := core.NewDevice("dev-1", "one", nil)
+	dev2 := core.NewDevice("dev-2", "two", nil)
+	sub := service.SubscribeCommandsMulti([]*core.Device{dev1, dev2}, core.Timestamp{}, "")
+	defer sub.Close()
+	l1, l2 := sub.Listener("dev-1"), sub.Listener("dev-2")
+	testWaitPollRequest(t, server, "dev-1,dev-2")
+
+	// nobody reads dev-1 commands
+	for i := uint64(1); i <= 10; i++ {
+		server.push("dev-1", core.Command{Id: i, Name: "stalled"})
+	}
+	server.push("dev-2", core.Command{Id: 100, Name: "two"})
+	testWaitCommand(t, l2, 100)
+
+	// removing the stalled device doesn't block
+	removed := make(chan struct{})
+	go func() {
+		sub.Remove(dev1)
+		close(removed)
+	}()
+	select {
+	case <-removed:
+	case <-time.After(testWaitTimeout):
+		t.Fatalf("stalled device is not removed")
+	}
+	for closed := false; !closed; {
+		select {
+		case _, ok := <-l1.C: // undelivered commands might be dropped
+			closed = !ok
+		case <-time.After(testWaitTimeout):
+			t.Fatalf("removed device listener is not closed")
+		}
+	}
+
+	sub.Close()
+	select {
+	case _, ok := <-l2.C:
+		if ok {
+			t.Errorf("unexpected command after close")
+		}
+	case <-time.After(testWaitTimeout):
+		t.Errorf("listener is not closed")
+	}
+}
@@ -0,0 +1,82 @@
+package devicehive
+
+import (
+	"errors"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"github.com/devicehive/devicehive-go/devicehive/record"
+	"github.com/devicehive/devicehive-go/devicehive/ws"
+	"strings"
+	"testing"
+)
+
+// Websocket cassette with network management actions
+const testWsNetworkCassette = `
+{"kind":"ws","dir":"dial","url":"ws://localhost/client"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"network/insert\",\"requestId\":1,\"network\":{\"name\":\"net-name\",\"key\":\"***\"}}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"network/insert\",\"requestId\":1,\"status\":\"success\",\"network\":{\"id\":42}}"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"network/update\",\"requestId\":2,\"networkId\":42,\"network\":{\"name\":\"net-name\",\"key\":\"***\",\"description\":\"updated\"}}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"network/update\",\"requestId\":2,\"status\":\"success\"}"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"network/get\",\"requestId\":3,\"networkId\":42}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"network/get\",\"requestId\":3,\"status\":\"success\",\"network\":{\"id\":42,\"name\":\"net-name\",\"description\":\"updated\"}}"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"network/list\",\"requestId\":4,\"namePattern\":\"net-%\"}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"network/list\",\"requestId\":4,\"status\":\"success\",\"networks\":[{\"id\":42,\"name\":\"net-name\"},{\"id\":43,\"name\":\"net-other\"}]}"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"network/delete\",\"requestId\":5,\"networkId\":42}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"network/delete\",\"requestId\":5,\"status\":\"error\",\"code\":404,\"error\":\"Network not found\"}"}
+`
+
+// Test network management through the Service interface on Websocket transport
+func TestWebsocketNetwork(t *testing.T) {
+	player, err := record.NewPlayer(strings.NewReader(testWsNetworkCassette))
+	if err != nil {
+		t.Fatalf("Failed to load cassette (error: %s)", err)
+	}
+
+	ws_service, err := ws.NewClientServiceDial("ws://localhost", "", player.Dial())
+	if err != nil {
+		t.Fatalf("Failed to create WS service (error: %s)", err)
+	}
+	var service Service = ws_service
+
+	network := core.NewNetwork("net-name", "net-key")
+	if err = service.InsertNetwork(network, testWaitTimeout); err != nil || network.Id != 42 {
+		t.Fatalf("Failed to insert network %s (error: %v)", network, err)
+	}
+
+	network.Description = "updated"
+	if err = service.UpdateNetwork(network, testWaitTimeout); err != nil {
+		t.Errorf("Failed to update network (error: %s)", err)
+	}
+
+	got, err := service.GetNetwork(42, testWaitTimeout)
+	if err != nil || got.Name != "net-name" || got.Description != "updated" {
+		t.Errorf("Unexpected network %v (error: %v)", got, err)
+	}
+
+	list, err := service.GetNetworkListFilter(&core.NetworkFilter{NamePattern: "net-%"}, testWaitTimeout)
+	if err != nil || len(list) != 2 || list[1].Id != 43 {
+		t.Errorf("Unexpected network list %v (error: %v)", list, err)
+	}
+
+	err = service.DeleteNetwork(network, testWaitTimeout)
+	if err == nil || !strings.Contains(err.Error(), "Network not found") {
+		t.Errorf("Delete error expected, got %v", err)
+	}
+}
+
+// Test network management is rejected on Websocket /device endpoint
+func TestWebsocketNetworkDeviceEndpoint(t *testing.T) {
+	player, err := record.NewPlayer(strings.NewReader(`{"kind":"ws","dir":"dial","url":"ws://localhost/device"}`))
+	if err != nil {
+		t.Fatalf("Failed to load cassette (error: %s)", err)
+	}
+
+	service, err := ws.NewServiceDial("ws://localhost", "", player.Dial())
+	if err != nil {
+		t.Fatalf("Failed to create WS service (error: %s)", err)
+	}
+
+	_, err = service.GetNetwork(42, testWaitTimeout)
+	if !errors.Is(err, ws.ErrNotSupported) {
+		t.Errorf("Not supported error expected, got %v", err)
+	}
+}
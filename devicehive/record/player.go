@@ -0,0 +1,257 @@
+package record
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/ws"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+)
+
+// Traffic player.
+// It serves recorded cassette back.
+type Player struct {
+	// JSON fields redacted in the cassette.
+	// Sent Websocket frames are redacted the same way before comparison.
+	SecretFields []string
+
+	lock sync.Mutex
+
+	// HTTP entries and "consumed" flags
+	http []*Entry
+	used []bool
+
+	// Websocket connections, each one starts with "dial" entry
+	conns [][]*Entry
+}
+
+// NewPlayer creates a new player reading cassette from the provided reader.
+func NewPlayer(r io.Reader) (player *Player, err error) {
+	player = &Player{SecretFields: DefaultSecretFields}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue // skip empty lines
+		}
+
+		entry := &Entry{}
+		err = json.Unmarshal(scanner.Bytes(), entry)
+		if err != nil {
+			return nil, fmt.Errorf("record: line %d: %s", line, err)
+		}
+
+		switch entry.Kind {
+		case KindHttp:
+			player.http = append(player.http, entry)
+			player.used = append(player.used, false)
+
+		case KindWs:
+			if entry.Direction == DirDial {
+				player.conns = append(player.conns, []*Entry{entry})
+			} else if n := len(player.conns); n != 0 {
+				player.conns[n-1] = append(player.conns[n-1], entry)
+			} else {
+				return nil, fmt.Errorf("record: line %d: no dial entry", line)
+			}
+
+		default:
+			return nil, fmt.Errorf("record: line %d: %q is unknown kind", line, entry.Kind)
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// LoadPlayer creates a new player reading cassette from the file.
+func LoadPlayer(path string) (player *Player, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	return NewPlayer(file)
+}
+
+// Transport() returns HTTP transport serving recorded responses.
+// Requests are matched by method, path and query. Host is ignored.
+func (player *Player) Transport() http.RoundTripper {
+	return &playTransport{player: player}
+}
+
+// replaying HTTP transport
+type playTransport struct {
+	player *Player
+}
+
+// find the first unused entry for the request
+func (player *Player) takeHttp(request *http.Request) *Entry {
+	player.lock.Lock()
+	defer player.lock.Unlock()
+
+	for i, entry := range player.http {
+		if player.used[i] || entry.Method != request.Method {
+			continue
+		}
+
+		u, err := url.Parse(entry.Url)
+		if err != nil || u.Path != request.URL.Path ||
+			u.RawQuery != request.URL.RawQuery {
+			continue
+		}
+
+		player.used[i] = true
+		return entry
+	}
+
+	return nil
+}
+
+// RoundTrip() implements http.RoundTripper interface.
+func (transport *playTransport) RoundTrip(request *http.Request) (response *http.Response, err error) {
+	if request.Body != nil {
+		request.Body.Close()
+	}
+
+	entry := transport.player.takeHttp(request)
+	if entry == nil {
+		return nil, fmt.Errorf("record: no recorded response for %s %s",
+			request.Method, request.URL)
+	}
+	if len(entry.Error) != 0 {
+		return nil, fmt.Errorf("%s", entry.Error)
+	}
+
+	header := entry.ResponseHeader
+	if header == nil {
+		header = http.Header{}
+	}
+
+	response = &http.Response{
+		Status:        entry.Status,
+		StatusCode:    entry.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewBufferString(entry.ResponseBody)),
+		ContentLength: int64(len(entry.ResponseBody)),
+		Request:       request}
+	return
+}
+
+// Dial() returns Websocket dial function serving recorded connections.
+// Each dial takes the next recorded connection, URL is ignored.
+func (player *Player) Dial() ws.DialFunc {
+	return func(url string, headers http.Header) (ws.Conn, error) {
+		player.lock.Lock()
+		defer player.lock.Unlock()
+
+		if len(player.conns) == 0 {
+			return nil, fmt.Errorf("record: no recorded connection for %q", url)
+		}
+		entries := player.conns[0]
+		player.conns = player.conns[1:]
+
+		if len(entries[0].Error) != 0 {
+			return nil, fmt.Errorf("%s", entries[0].Error)
+		}
+
+		conn := &playConn{entries: entries[1:], fields: player.SecretFields}
+		conn.cond = sync.NewCond(&conn.lock)
+		return conn, nil
+	}
+}
+
+// replaying Websocket connection
+// TX and RX frames are processed in recorded order
+type playConn struct {
+	lock    sync.Mutex
+	cond    *sync.Cond
+	entries []*Entry
+	fields  []string // secret fields
+	pos     int
+	closed  bool
+}
+
+// wait for the next entry in the provided direction
+// return nil if connection is closed or no more entries
+func (conn *playConn) next(dir string) *Entry {
+	for !conn.closed && conn.pos < len(conn.entries) &&
+		conn.entries[conn.pos].Direction != dir {
+		conn.cond.Wait()
+	}
+	if conn.closed || conn.pos >= len(conn.entries) {
+		return nil
+	}
+
+	entry := conn.entries[conn.pos]
+	conn.pos += 1
+	conn.cond.Broadcast()
+	return entry
+}
+
+// WriteMessage() checks the message is expected.
+// Message is compared with the recorded one, requestId is ignored.
+func (conn *playConn) WriteMessage(messageType int, data []byte) error {
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+
+	entry := conn.next(DirTx)
+	if entry == nil {
+		return fmt.Errorf("record: unexpected message %s", string(data))
+	}
+	if len(entry.Error) != 0 {
+		return fmt.Errorf("%s", entry.Error)
+	}
+
+	sent := normalizeFrame(data, conn.fields)
+	if expected := normalizeFrame([]byte(entry.Data), conn.fields); sent != expected {
+		return fmt.Errorf("record: message mismatch %s, expected %s", sent, expected)
+	}
+
+	return nil
+}
+
+// ReadMessage() returns recorded message.
+func (conn *playConn) ReadMessage() (messageType int, data []byte, err error) {
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+
+	entry := conn.next(DirRx)
+	if entry == nil {
+		// no more messages, wait until closed
+		for !conn.closed {
+			conn.cond.Wait()
+		}
+		return 0, nil, io.EOF
+	}
+	if len(entry.Error) != 0 {
+		return 0, nil, fmt.Errorf("%s", entry.Error)
+	}
+
+	return entry.MessageType, []byte(entry.Data), nil
+}
+
+// Close() closes the connection.
+func (conn *playConn) Close() error {
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+
+	conn.closed = true
+	conn.cond.Broadcast()
+	return nil
+}
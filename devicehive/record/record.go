@@ -0,0 +1,78 @@
+// Record/replay of REST and Websocket traffic.
+//
+// Recorder logs every HTTP request/response and every Websocket frame
+// to a JSONL cassette (one Entry per line) with secrets redacted.
+// Player serves recorded cassette back, so the scenario could be
+// reproduced deterministically without a server:
+//
+//	// record
+//	recorder := record.NewRecorder(file)
+//	restService.SetTransport(recorder.Transport(nil))
+//	wsService, err := ws.NewServiceDial(url, key, recorder.Dial(ws.DefaultDial))
+//
+//	// replay
+//	player, err := record.NewPlayer(file)
+//	restService.SetTransport(player.Transport())
+//	wsService, err := ws.NewServiceDial(url, key, player.Dial())
+package record
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Cassette entry kinds.
+const (
+	KindHttp = "http"
+	KindWs   = "ws"
+)
+
+// Websocket frame directions.
+const (
+	DirDial = "dial"
+	DirTx   = "tx"
+	DirRx   = "rx"
+)
+
+// Single cassette entry: HTTP exchange or Websocket frame.
+type Entry struct {
+	// Entry kind: "http" or "ws".
+	Kind string `json:"kind"`
+
+	// Milliseconds since recording started [informational].
+	Elapsed int64 `json:"elapsed"`
+
+	// HTTP method, URL and request headers. URL is also used for Websocket dial.
+	Method        string      `json:"method,omitempty"`
+	Url           string      `json:"url,omitempty"`
+	RequestHeader http.Header `json:"requestHeader,omitempty"`
+	RequestBody   string      `json:"requestBody,omitempty"`
+
+	// HTTP response.
+	StatusCode     int         `json:"statusCode,omitempty"`
+	Status         string      `json:"status,omitempty"`
+	ResponseHeader http.Header `json:"responseHeader,omitempty"`
+	ResponseBody   string      `json:"responseBody,omitempty"`
+
+	// Websocket frame: direction ("dial", "tx" or "rx"), type and data.
+	Direction   string `json:"dir,omitempty"`
+	MessageType int    `json:"messageType,omitempty"`
+	Data        string `json:"data,omitempty"`
+
+	// Transport error [optional].
+	Error string `json:"error,omitempty"`
+}
+
+// Get Entry string representation
+func (entry Entry) String() string {
+	switch entry.Kind {
+	case KindHttp:
+		return fmt.Sprintf("Entry{HTTP %s %s -> %d}",
+			entry.Method, entry.Url, entry.StatusCode)
+	case KindWs:
+		return fmt.Sprintf("Entry{WS %s %s}",
+			entry.Direction, entry.Data)
+	}
+
+	return fmt.Sprintf("Entry{%s}", entry.Kind)
+}
@@ -0,0 +1,180 @@
+package record
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/devicehive/devicehive-go/devicehive/log"
+	"github.com/devicehive/devicehive-go/devicehive/ws"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Traffic recorder.
+// All entries are written to a JSONL cassette.
+type Recorder struct {
+	// Headers and JSON fields to redact.
+	// Might be changed before recording started.
+	SecretHeaders []string
+	SecretFields  []string
+
+	lock    sync.Mutex
+	encoder *json.Encoder
+	start   time.Time
+	err     error
+}
+
+// NewRecorder creates a new recorder writing cassette to the provided writer.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{
+		SecretHeaders: DefaultSecretHeaders,
+		SecretFields:  DefaultSecretFields,
+		encoder:       json.NewEncoder(w),
+		start:         time.Now()}
+}
+
+// Err() returns the first write error.
+func (recorder *Recorder) Err() error {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	return recorder.err
+}
+
+// write entry to the cassette
+func (recorder *Recorder) write(entry *Entry) {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+
+	entry.Elapsed = int64(time.Since(recorder.start) / time.Millisecond)
+	if err := recorder.encoder.Encode(entry); err != nil {
+		log.Warnf("RECORD: failed to write %s (error: %s)", entry, err)
+		if recorder.err == nil {
+			recorder.err = err
+		}
+	}
+}
+
+// Transport() returns HTTP transport recording all requests.
+// Base transport might be nil, http.DefaultTransport is used then.
+func (recorder *Recorder) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &recordTransport{recorder: recorder, base: base}
+}
+
+// recording HTTP transport
+type recordTransport struct {
+	recorder *Recorder
+	base     http.RoundTripper
+}
+
+// RoundTrip() implements http.RoundTripper interface.
+func (transport *recordTransport) RoundTrip(request *http.Request) (response *http.Response, err error) {
+	recorder := transport.recorder
+	entry := &Entry{Kind: KindHttp,
+		Method:        request.Method,
+		Url:           request.URL.String(),
+		RequestHeader: recorder.redactHeader(request.Header)}
+
+	// request body
+	if request.Body != nil {
+		var body []byte
+		body, err = ioutil.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return
+		}
+		request.Body = ioutil.NopCloser(bytes.NewReader(body))
+		entry.RequestBody = recorder.redactBody(body)
+	}
+
+	response, err = transport.base.RoundTrip(request)
+	if err != nil {
+		entry.Error = err.Error()
+		recorder.write(entry)
+		return
+	}
+
+	// response body
+	body, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		entry.Error = err.Error()
+		recorder.write(entry)
+		return nil, err
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	entry.StatusCode = response.StatusCode
+	entry.Status = response.Status
+	entry.ResponseHeader = recorder.redactHeader(response.Header)
+	entry.ResponseBody = recorder.redactBody(body)
+	recorder.write(entry)
+
+	return
+}
+
+// Dial() wraps a Websocket dial function,
+// so each new connection will be recorded.
+func (recorder *Recorder) Dial(dial ws.DialFunc) ws.DialFunc {
+	return func(url string, headers http.Header) (ws.Conn, error) {
+		entry := &Entry{Kind: KindWs, Direction: DirDial,
+			Url:           url,
+			RequestHeader: recorder.redactHeader(headers)}
+
+		conn, err := dial(url, headers)
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		recorder.write(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		return &recordConn{recorder: recorder, conn: conn}, nil
+	}
+}
+
+// recording Websocket connection
+type recordConn struct {
+	recorder *Recorder
+	conn     ws.Conn
+}
+
+// WriteMessage() records and sends message.
+func (conn *recordConn) WriteMessage(messageType int, data []byte) error {
+	err := conn.conn.WriteMessage(messageType, data)
+
+	entry := &Entry{Kind: KindWs, Direction: DirTx,
+		MessageType: messageType,
+		Data:        conn.recorder.redactBody(data)}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	conn.recorder.write(entry)
+
+	return err
+}
+
+// ReadMessage() receives and records message.
+func (conn *recordConn) ReadMessage() (messageType int, data []byte, err error) {
+	messageType, data, err = conn.conn.ReadMessage()
+
+	entry := &Entry{Kind: KindWs, Direction: DirRx,
+		MessageType: messageType,
+		Data:        conn.recorder.redactBody(data)}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	conn.recorder.write(entry)
+
+	return
+}
+
+// Close() closes underlying connection.
+func (conn *recordConn) Close() error {
+	return conn.conn.Close()
+}
@@ -0,0 +1,51 @@
+package record
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+)
+
+// Replacement for all redacted secrets.
+const Redacted = core.Redacted
+
+// Default headers to redact.
+var DefaultSecretHeaders = append([]string(nil), core.SecretHeaders...)
+
+// Default JSON fields to redact.
+var DefaultSecretFields = append([]string(nil), core.SecretFields...)
+
+// copy headers with secrets redacted
+func (recorder *Recorder) redactHeader(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	return core.RedactHeader(header, recorder.SecretHeaders)
+}
+
+// redact secret fields of the JSON body
+// body is returned as is if it's not a JSON object
+func (recorder *Recorder) redactBody(body []byte) string {
+	return core.RedactJSONFields(body, recorder.SecretFields)
+}
+
+// normalize Websocket frame to compare with recorded one
+// secrets are redacted, request identifier is removed
+// frame is returned as is if it's not a JSON object
+func normalizeFrame(data []byte, fields []string) string {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var v map[string]interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return string(data)
+	}
+	delete(v, "requestId")
+
+	res, err := json.Marshal(v) // keys are sorted
+	if err != nil {
+		return string(data)
+	}
+	return core.RedactJSONFields(res, fields)
+}
@@ -0,0 +1,159 @@
+package devicehive
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"github.com/devicehive/devicehive-go/devicehive/record"
+	"github.com/devicehive/devicehive-go/devicehive/rest"
+	"github.com/devicehive/devicehive-go/devicehive/ws"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Test REST traffic is recorded with secrets redacted and replayed back
+func TestRecordReplayRest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/info":
+			fmt.Fprint(w, `{"apiVersion":"1.2.3","serverTimestamp":"2015-10-22T14:15:16.999"}`)
+		case r.Method == "PUT" && strings.HasPrefix(r.URL.Path, "/device/"):
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	device := core.NewDevice("rec-dev-id", "rec-dev-name", nil)
+	device.Key = "secret-device-key"
+
+	// record
+	cassette := &bytes.Buffer{}
+	recorder := record.NewRecorder(cassette)
+	service, err := rest.NewService(server.URL, "secret-access-key")
+	if err != nil {
+		t.Fatalf("Failed to create REST service (error: %s)", err)
+	}
+	service.SetTransport(recorder.Transport(nil))
+	if _, err = service.GetServerInfo(testWaitTimeout); err != nil {
+		t.Fatalf("Failed to get server info (error: %s)", err)
+	}
+	if err = service.RegisterDevice(device, testWaitTimeout); err != nil {
+		t.Fatalf("Failed to register device (error: %s)", err)
+	}
+	server.Close()
+
+	if recorder.Err() != nil {
+		t.Fatalf("Failed to record (error: %s)", recorder.Err())
+	}
+	for _, secret := range []string{"secret-access-key", "secret-device-key"} {
+		if strings.Contains(cassette.String(), secret) {
+			t.Errorf("%q is not redacted in cassette:\n%s", secret, cassette)
+		}
+	}
+
+	// replay (server is closed)
+	player, err := record.NewPlayer(bytes.NewReader(cassette.Bytes()))
+	if err != nil {
+		t.Fatalf("Failed to load cassette (error: %s)", err)
+	}
+	service, err = rest.NewService(server.URL, "")
+	if err != nil {
+		t.Fatalf("Failed to create REST service (error: %s)", err)
+	}
+	service.SetTransport(player.Transport())
+
+	info, err := service.GetServerInfo(testWaitTimeout)
+	if err != nil {
+		t.Fatalf("Failed to replay server info (error: %s)", err)
+	}
+	if info.Version != "1.2.3" {
+		t.Errorf("Unexpected server info replayed: %s", info)
+	}
+	if err = service.RegisterDevice(device, testWaitTimeout); err != nil {
+		t.Errorf("Failed to replay device registration (error: %s)", err)
+	}
+	if _, err = service.GetServerInfo(testWaitTimeout); err == nil {
+		t.Errorf("Expected 'no recorded response' error")
+	}
+}
+
+// Websocket cassette with server info request
+const testWsInfoCassette = `
+{"kind":"ws","dir":"dial","url":"ws://localhost/device"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"server/info\",\"requestId\":1}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"server/info\",\"requestId\":1,\"status\":\"success\",\"info\":{\"apiVersion\":\"1.2.3\",\"serverTimestamp\":\"2015-10-22T14:15:16.999\"}}"}
+`
+
+// Test Websocket traffic is replayed back
+func TestReplayWebsocket(t *testing.T) {
+	player, err := record.NewPlayer(strings.NewReader(testWsInfoCassette))
+	if err != nil {
+		t.Fatalf("Failed to load cassette (error: %s)", err)
+	}
+
+	service, err := ws.NewServiceDial("ws://localhost", "", player.Dial())
+	if err != nil {
+		t.Fatalf("Failed to create WS service (error: %s)", err)
+	}
+
+	info, err := service.GetServerInfo(testWaitTimeout)
+	if err != nil {
+		t.Fatalf("Failed to replay server info (error: %s)", err)
+	}
+	if info.Version != "1.2.3" || info.Timestamp.String() != "2015-10-22T14:15:16.999" {
+		t.Errorf("Unexpected server info replayed: %s", info)
+	}
+}
+
+// Websocket cassette with notification insert, notification id is above 2^53
+const testWsBigIdCassette = `
+{"kind":"ws","dir":"dial","url":"ws://localhost/device"}
+{"kind":"ws","dir":"tx","messageType":1,"data":"{\"action\":\"notification/insert\",\"requestId\":1,\"deviceId\":\"dev-id\",\"notification\":{\"notification\":\"ntf\",\"parameters\":1}}"}
+{"kind":"ws","dir":"rx","messageType":1,"data":"{\"action\":\"notification/insert\",\"requestId\":1,\"status\":\"success\",\"notification\":{\"id\":9007199254740993,\"timestamp\":\"2015-10-22T14:15:16.999\"}}"}
+`
+
+// Test Websocket identifiers above 2^53 are not corrupted
+func TestReplayWebsocketBigId(t *testing.T) {
+	player, err := record.NewPlayer(strings.NewReader(testWsBigIdCassette))
+	if err != nil {
+		t.Fatalf("Failed to load cassette (error: %s)", err)
+	}
+
+	service, err := ws.NewServiceDial("ws://localhost", "", player.Dial())
+	if err != nil {
+		t.Fatalf("Failed to create WS service (error: %s)", err)
+	}
+
+	device := core.NewDevice("dev-id", "dev-name", nil)
+	notification := core.NewNotification("ntf", 1)
+	err = service.InsertNotification(device, notification, testWaitTimeout)
+	if err != nil {
+		t.Fatalf("Failed to replay notification insert (error: %s)", err)
+	}
+	if notification.Id != 9007199254740993 {
+		t.Errorf("Notification id corrupted: %d", notification.Id)
+	}
+}
+
+// Test Websocket replay fails if the sent message differs from the recorded one
+func TestReplayWebsocketMismatch(t *testing.T) {
+	player, err := record.NewPlayer(strings.NewReader(testWsBigIdCassette))
+	if err != nil {
+		t.Fatalf("Failed to load cassette (error: %s)", err)
+	}
+
+	service, err := ws.NewServiceDial("ws://localhost", "", player.Dial())
+	if err != nil {
+		t.Fatalf("Failed to create WS service (error: %s)", err)
+	}
+
+	device := core.NewDevice("other-dev-id", "dev-name", nil)
+	err = service.InsertNotification(device, core.NewNotification("ntf", 1), testWaitTimeout)
+	if err == nil || !strings.Contains(err.Error(), "message mismatch") {
+		t.Errorf("Message mismatch error expected, got %v", err)
+	}
+}
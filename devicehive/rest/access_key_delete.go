@@ -0,0 +1,71 @@
+package rest
+
+import (
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"time"
+)
+
+// Prepare DeleteAccessKey task
+func (service *Service) prepareDeleteAccessKey(userId uint64, key *core.AccessKey) (task Task, err error) {
+	// create request
+	url := service.accessKeyUrl(userId, key.Id)
+	task.request, err = http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		service.logger.Warnf("REST: failed to create /accesskey/delete request (error: %s)", err)
+		return
+	}
+
+	// authorization
+	service.prepareAuthorization(task.request, nil)
+
+	return
+}
+
+// Process DeleteAccessKey task
+func (service *Service) processDeleteAccessKey(task Task) (err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode < http.StatusOK ||
+		task.response.StatusCode > http.StatusPartialContent {
+		service.logger.Warnf("REST: unexpected /accesskey/delete status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	return
+}
+
+// DeleteAccessKey() function deletes the access key of the user.
+func (service *Service) DeleteAccessKey(userId uint64, key *core.AccessKey, timeout time.Duration) (err error) {
+	service.logger.Tracef("REST: deleting user %d access key %d...", userId, key.Id)
+
+	task, err := service.prepareDeleteAccessKey(userId, key)
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /accesskey/delete task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /accesskey/delete task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		err = service.processDeleteAccessKey(task)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /accesskey/delete task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,89 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"time"
+)
+
+// get access key URL, zero keyId gives the key list URL
+func (service *Service) accessKeyUrl(userId, keyId uint64) string {
+	if keyId == 0 {
+		return fmt.Sprintf("%s/accesskey", service.userUrl(userId))
+	}
+	return fmt.Sprintf("%s/accesskey/%d", service.userUrl(userId), keyId)
+}
+
+// Prepare GetAccessKey task
+func (service *Service) prepareGetAccessKey(userId, keyId uint64) (task Task, err error) {
+	// create request
+	url := service.accessKeyUrl(userId, keyId)
+
+	task.request, err = http.NewRequest("GET", url, nil)
+	if err != nil {
+		service.logger.Warnf("REST: failed to create /accesskey/get request (error: %s)", err)
+		return
+	}
+
+	// authorization
+	service.prepareAuthorization(task.request, nil)
+
+	return
+}
+
+// Process GetAccessKey task
+func (service *Service) processGetAccessKey(task Task, key *core.AccessKey) (err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode != http.StatusOK {
+		service.logger.Warnf("REST: unexpected /accesskey/get status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	// unmarshal
+	err = json.Unmarshal(task.body, key)
+	if err != nil {
+		service.logger.Warnf("REST: failed to parse /accesskey/get body (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// GetAccessKey() function gets the access key of the user.
+// Use CurrentUser to get the key of the user the service is authorized as.
+func (service *Service) GetAccessKey(userId, keyId uint64, timeout time.Duration) (key *core.AccessKey, err error) {
+	service.logger.Tracef("REST: getting user %d access key %d...", userId, keyId)
+
+	task, err := service.prepareGetAccessKey(userId, keyId)
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /accesskey/get task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /accesskey/get task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		key = &core.AccessKey{Id: keyId}
+		err = service.processGetAccessKey(task, key)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /accesskey/get task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,99 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"time"
+)
+
+// Prepare InsertAccessKey task
+func (service *Service) prepareInsertAccessKey(userId uint64, key *core.AccessKey) (task Task, err error) {
+	// create request
+	url := service.accessKeyUrl(userId, 0)
+
+	key_data := *key
+	key_data.Id = 0   // do not put ID to the request body
+	key_data.Key = "" // generated by the server
+	body, err := json.Marshal(&key_data)
+	if err != nil {
+		service.logger.Warnf("REST: failed to format /accesskey/insert request (error: %s)", err)
+		return
+	}
+
+	task.request, err = http.NewRequest("POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		service.logger.Warnf("REST: failed to create /accesskey/insert request (error: %s)", err)
+		return
+	}
+	task.request.Header.Add("Content-Type", "application/json")
+
+	// authorization
+	service.prepareAuthorization(task.request, nil)
+
+	return
+}
+
+// Process InsertAccessKey task
+func (service *Service) processInsertAccessKey(task Task, key *core.AccessKey) (err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode < http.StatusOK ||
+		task.response.StatusCode > http.StatusPartialContent {
+		service.logger.Warnf("REST: unexpected /accesskey/insert status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	// unmarshal, server responds with {"id":..., "key":...}
+	err = json.Unmarshal(task.body, key)
+	if err != nil {
+		service.logger.Warnf("REST: failed to parse /accesskey/insert body (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// InsertAccessKey() function creates a new access key for the user.
+// Key identifier and generated key value are updated on success.
+func (service *Service) InsertAccessKey(userId uint64, key *core.AccessKey, timeout time.Duration) (err error) {
+	service.logger.Tracef("REST: inserting user %d access key %q...", userId, key.Label)
+
+	// client-side validation
+	err = core.Validate(key)
+	if err != nil {
+		service.logger.Warnf("REST: invalid access key (error: %s)", err)
+		return
+	}
+
+	task, err := service.prepareInsertAccessKey(userId, key)
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /accesskey/insert task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /accesskey/insert task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		err = service.processInsertAccessKey(task, key)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /accesskey/insert task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
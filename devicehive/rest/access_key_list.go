@@ -0,0 +1,80 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"time"
+)
+
+// Prepare GetAccessKeyList task
+func (service *Service) prepareGetAccessKeyList(userId uint64) (task Task, err error) {
+	// create request
+	url := service.accessKeyUrl(userId, 0)
+
+	task.request, err = http.NewRequest("GET", url, nil)
+	if err != nil {
+		service.logger.Warnf("REST: failed to create /accesskey/list request (error: %s)", err)
+		return
+	}
+
+	// authorization
+	service.prepareAuthorization(task.request, nil)
+
+	return
+}
+
+// Process GetAccessKeyList task
+func (service *Service) processGetAccessKeyList(task Task) (keys []core.AccessKey, err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode != http.StatusOK {
+		service.logger.Warnf("REST: unexpected /accesskey/list status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	// unmarshal
+	err = json.Unmarshal(task.body, &keys)
+	if err != nil {
+		service.logger.Warnf("REST: failed to parse /accesskey/list body (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// GetAccessKeyList() function gets all the access keys of the user.
+// Use CurrentUser to get the keys of the user the service is authorized as.
+func (service *Service) GetAccessKeyList(userId uint64, timeout time.Duration) (keys []core.AccessKey, err error) {
+	service.logger.Tracef("REST: getting user %d access key list...", userId)
+
+	task, err := service.prepareGetAccessKeyList(userId)
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /accesskey/list task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /accesskey/list task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		keys, err = service.processGetAccessKeyList(task)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /accesskey/list task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
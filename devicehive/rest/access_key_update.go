@@ -0,0 +1,92 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"time"
+)
+
+// Prepare UpdateAccessKey task
+func (service *Service) prepareUpdateAccessKey(userId uint64, key *core.AccessKey) (task Task, err error) {
+	// create request
+	url := service.accessKeyUrl(userId, key.Id)
+
+	key_data := *key
+	key_data.Id = 0   // do not put ID to the request body
+	key_data.Key = "" // could not be changed
+	body, err := json.Marshal(&key_data)
+	if err != nil {
+		service.logger.Warnf("REST: failed to format /accesskey/update request (error: %s)", err)
+		return
+	}
+
+	task.request, err = http.NewRequest("PUT", url, bytes.NewBuffer(body))
+	if err != nil {
+		service.logger.Warnf("REST: failed to create /accesskey/update request (error: %s)", err)
+		return
+	}
+	task.request.Header.Add("Content-Type", "application/json")
+
+	// authorization
+	service.prepareAuthorization(task.request, nil)
+
+	return
+}
+
+// Process UpdateAccessKey task
+func (service *Service) processUpdateAccessKey(task Task) (err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode < http.StatusOK ||
+		task.response.StatusCode > http.StatusPartialContent {
+		service.logger.Warnf("REST: unexpected /accesskey/update status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	return
+}
+
+// UpdateAccessKey() function updates the access key of the user.
+// Label, expiration date and permissions could be changed.
+func (service *Service) UpdateAccessKey(userId uint64, key *core.AccessKey, timeout time.Duration) (err error) {
+	service.logger.Tracef("REST: updating user %d access key %d...", userId, key.Id)
+
+	// client-side validation
+	err = core.Validate(key)
+	if err != nil {
+		service.logger.Warnf("REST: invalid access key (error: %s)", err)
+		return
+	}
+
+	task, err := service.prepareUpdateAccessKey(userId, key)
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /accesskey/update task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /accesskey/update task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		err = service.processUpdateAccessKey(task)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /accesskey/update task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
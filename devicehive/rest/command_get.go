@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"net/http"
 	"time"
 )
@@ -16,7 +15,7 @@ func (service *Service) prepareGetCommand(device *core.Device, commandId uint64)
 
 	task.request, err = http.NewRequest("GET", url, nil)
 	if err != nil {
-		log.Warnf("REST: failed to create /command/get request (error: %s)", err)
+		service.logger.Warnf("REST: failed to create /command/get request (error: %s)", err)
 		return
 	}
 
@@ -36,7 +35,7 @@ func (service *Service) processGetCommand(task Task, command *core.Command) (err
 
 	// check status code
 	if task.response.StatusCode != http.StatusOK {
-		log.Warnf("REST: unexpected /command/get status %s",
+		service.logger.Warnf("REST: unexpected /command/get status %s",
 			task.response.Status)
 		err = fmt.Errorf("unexpected status: %s",
 			task.response.Status)
@@ -46,7 +45,7 @@ func (service *Service) processGetCommand(task Task, command *core.Command) (err
 	// unmarshal
 	err = json.Unmarshal(task.body, command)
 	if err != nil {
-		log.Warnf("REST: failed to parse /command/get body (error: %s)", err)
+		service.logger.Warnf("REST: failed to parse /command/get body (error: %s)", err)
 		return
 	}
 
@@ -55,24 +54,24 @@ func (service *Service) processGetCommand(task Task, command *core.Command) (err
 
 // GetCommand() function get the command data.
 func (service *Service) GetCommand(device *core.Device, commandId uint64, timeout time.Duration) (command *core.Command, err error) {
-	log.Debugf("REST: getting command %q/%d...", device.Id, commandId)
+	service.logger.Debugf("REST: getting command %q/%d...", device.Id, commandId)
 
 	task, err := service.prepareGetCommand(device, commandId)
 	if err != nil {
-		log.Warnf("REST: failed to prepare /command/get task (error: %s)", err)
+		service.logger.Warnf("REST: failed to prepare /command/get task (error: %s)", err)
 		return
 	}
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("REST: failed to wait %s for /command/get task", timeout)
-		err = fmt.Errorf("timed out")
+		service.logger.Warnf("REST: failed to wait %s for /command/get task", timeout)
+		err = core.ErrTimeout
 
 	case task = <-service.doAsync(task):
 		command = &core.Command{Id: commandId}
 		err = service.processGetCommand(task, command)
 		if err != nil {
-			log.Warnf("REST: failed to process /command/get task (error: %s)", err)
+			service.logger.Warnf("REST: failed to process /command/get task (error: %s)", err)
 			return
 		}
 	}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"net/http"
 	"time"
 )
@@ -22,13 +21,13 @@ func (service *Service) prepareInsertCommand(device *core.Device, command *core.
 
 	body, err := json.Marshal(command)
 	if err != nil {
-		log.Warnf("REST: failed to format /command/insert request (error: %s)", err)
+		service.logger.Warnf("REST: failed to format /command/insert request (error: %s)", err)
 		return
 	}
 
 	task.request, err = http.NewRequest("POST", url, bytes.NewBuffer(body))
 	if err != nil {
-		log.Warnf("REST: failed to create /command/insert request (error: %s)", err)
+		service.logger.Warnf("REST: failed to create /command/insert request (error: %s)", err)
 		return
 	}
 	task.request.Header.Add("Content-Type", "application/json")
@@ -50,7 +49,7 @@ func (service *Service) processInsertCommand(task Task, command *core.Command) (
 	// check status code
 	if task.response.StatusCode < http.StatusOK ||
 		task.response.StatusCode > http.StatusPartialContent {
-		log.Warnf("REST: unexpected /command/insert status %s",
+		service.logger.Warnf("REST: unexpected /command/insert status %s",
 			task.response.Status)
 		err = fmt.Errorf("unexpected status: %s",
 			task.response.Status)
@@ -60,7 +59,7 @@ func (service *Service) processInsertCommand(task Task, command *core.Command) (
 	// unmarshal
 	err = json.Unmarshal(task.body, command)
 	if err != nil {
-		log.Warnf("REST: failed to parse /command/insert body (error: %s)", err)
+		service.logger.Warnf("REST: failed to parse /command/insert body (error: %s)", err)
 		return
 	}
 
@@ -69,23 +68,30 @@ func (service *Service) processInsertCommand(task Task, command *core.Command) (
 
 // InsertCommand() function inserts the device command.
 func (service *Service) InsertCommand(device *core.Device, command *core.Command, timeout time.Duration) (err error) {
-	log.Debugf("REST: inserting command %q to %q...", command.Name, device.Id)
+	service.logger.Debugf("REST: inserting command %q to %q...", command.Name, device.Id)
+
+	// client-side validation
+	err = core.Validate(command)
+	if err != nil {
+		service.logger.Warnf("REST: invalid command (error: %s)", err)
+		return
+	}
 
 	task, err := service.prepareInsertCommand(device, command)
 	if err != nil {
-		log.Warnf("REST: failed to prepare /command/insert task (error: %s)", err)
+		service.logger.Warnf("REST: failed to prepare /command/insert task (error: %s)", err)
 		return
 	}
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("REST: failed to wait %s for /command/insert task", timeout)
-		err = fmt.Errorf("timed out")
+		service.logger.Warnf("REST: failed to wait %s for /command/insert task", timeout)
+		err = core.ErrTimeout
 
 	case task = <-service.doAsync(task):
 		err = service.processInsertCommand(task, command)
 		if err != nil {
-			log.Warnf("REST: failed to process /command/insert task (error: %s)", err)
+			service.logger.Warnf("REST: failed to process /command/insert task (error: %s)", err)
 			return
 		}
 	}
@@ -0,0 +1,84 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"time"
+)
+
+// Prepare GetCommandList task
+func (service *Service) prepareGetCommandList(device *core.Device, filter *core.CommandFilter) (task Task, err error) {
+	// create request
+	query := filter.Query()
+	url := fmt.Sprintf("%s/device/%s/command", service.baseUrl, device.Id)
+	if len(query) != 0 {
+		url += "?" + query.Encode()
+	}
+
+	task.request, err = http.NewRequest("GET", url, nil)
+	if err != nil {
+		service.logger.Warnf("REST: failed to create /command/list request (error: %s)", err)
+		return
+	}
+
+	// authorization
+	service.prepareAuthorization(task.request, device)
+
+	return
+}
+
+// Process GetCommandList task
+func (service *Service) processGetCommandList(task Task) (commands []core.Command, err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode != http.StatusOK {
+		service.logger.Warnf("REST: unexpected /command/list status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	// unmarshal
+	err = json.Unmarshal(task.body, &commands)
+	if err != nil {
+		service.logger.Warnf("REST: failed to parse /command/list body (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// GetCommandList() function gets the command history of the device.
+// Nil filter means server's default.
+func (service *Service) GetCommandList(device *core.Device, filter *core.CommandFilter, timeout time.Duration) (commands []core.Command, err error) {
+	service.logger.Tracef("REST: getting command list %q (filter:%+v)...", device.Id, filter)
+
+	task, err := service.prepareGetCommandList(device, filter)
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /command/list task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /command/list task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		commands, err = service.processGetCommandList(task)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /command/list task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
@@ -4,18 +4,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"net/http"
 	"net/url"
 	"time"
 )
 
 // Prepare PollCommand task
-func (service *Service) preparePollCommand(device *core.Device, timestamp, names, waitTimeout string) (task Task, err error) {
+func (service *Service) preparePollCommand(device *core.Device, timestamp core.Timestamp, names, waitTimeout string) (task Task, err error) {
 	// create request
 	query := url.Values{}
-	if len(timestamp) != 0 {
-		query.Set("timestamp", timestamp)
+	if !timestamp.IsZero() {
+		query.Set("timestamp", timestamp.String())
 	}
 	if len(names) != 0 {
 		query.Set("names", names)
@@ -30,7 +29,7 @@ func (service *Service) preparePollCommand(device *core.Device, timestamp, names
 
 	task.request, err = http.NewRequest("GET", url, nil)
 	if err != nil {
-		log.Warnf("REST: failed to create /command/poll request (error: %s)", err)
+		service.logger.Warnf("REST: failed to create /command/poll request (error: %s)", err)
 		return
 	}
 
@@ -50,7 +49,7 @@ func (service *Service) processPollCommand(task Task) (commands []core.Command,
 
 	// check status code
 	if task.response.StatusCode != http.StatusOK {
-		log.Warnf("REST: unexpected /command/poll status %s",
+		service.logger.Warnf("REST: unexpected /command/poll status %s",
 			task.response.Status)
 		err = fmt.Errorf("unexpected status: %s",
 			task.response.Status)
@@ -60,7 +59,7 @@ func (service *Service) processPollCommand(task Task) (commands []core.Command,
 	// unmarshal
 	err = json.Unmarshal(task.body, &commands)
 	if err != nil {
-		log.Warnf("REST: failed to parse /command/poll body (error: %s)", err)
+		service.logger.Warnf("REST: failed to parse /command/poll body (error: %s)", err)
 		return
 	}
 
@@ -68,24 +67,24 @@ func (service *Service) processPollCommand(task Task) (commands []core.Command,
 }
 
 // GetCommand() function poll the commands.
-func (service *Service) PollCommands(device *core.Device, timestamp, names, waitTimeout string, timeout time.Duration) (commands []core.Command, err error) {
-	log.Debugf("REST: polling commands %q, timestamp:%q...", device.Id, timestamp)
+func (service *Service) PollCommands(device *core.Device, timestamp core.Timestamp, names, waitTimeout string, timeout time.Duration) (commands []core.Command, err error) {
+	service.logger.Debugf("REST: polling commands %q, timestamp:%q...", device.Id, timestamp)
 
 	task, err := service.preparePollCommand(device, timestamp, names, waitTimeout)
 	if err != nil {
-		log.Warnf("REST: failed to prepare /command/poll task (error: %s)", err)
+		service.logger.Warnf("REST: failed to prepare /command/poll task (error: %s)", err)
 		return
 	}
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("REST: failed to wait %s for /command/poll task", timeout)
-		err = fmt.Errorf("timed out")
+		service.logger.Warnf("REST: failed to wait %s for /command/poll task", timeout)
+		err = core.ErrTimeout
 
 	case task = <-service.doAsync(task):
 		commands, err = service.processPollCommand(task)
 		if err != nil {
-			log.Warnf("REST: failed to process /command/poll task (error: %s)", err)
+			service.logger.Warnf("REST: failed to process /command/poll task (error: %s)", err)
 			return
 		}
 	}
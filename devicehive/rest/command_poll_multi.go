@@ -0,0 +1,112 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Command received by multi-device polling.
+type DeviceCommand struct {
+	// Device identifier the command is sent to.
+	DeviceId string `json:"deviceGuid"`
+
+	// Command itself.
+	Command core.Command `json:"command"`
+}
+
+// Prepare PollCommandsMulti task
+func (service *Service) preparePollCommandsMulti(deviceIds []string, timestamp core.Timestamp, names, waitTimeout string) (task Task, err error) {
+	// create request
+	query := url.Values{}
+	query.Set("deviceGuids", strings.Join(deviceIds, ","))
+	if !timestamp.IsZero() {
+		query.Set("timestamp", timestamp.String())
+	}
+	if len(names) != 0 {
+		query.Set("names", names)
+	}
+	if len(waitTimeout) != 0 {
+		query.Set("waitTimeout", waitTimeout)
+	}
+	url := fmt.Sprintf("%s/device/command/poll?%s", service.baseUrl, query.Encode())
+
+	task.request, err = http.NewRequest("GET", url, nil)
+	if err != nil {
+		service.logger.Warnf("REST: failed to create /command/poll-multi request (error: %s)", err)
+		return
+	}
+
+	// authorization, access key only
+	service.prepareAuthorization(task.request, nil)
+
+	return
+}
+
+// Process PollCommandsMulti task
+func (service *Service) processPollCommandsMulti(task Task) (commands []DeviceCommand, err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode != http.StatusOK {
+		service.logger.Warnf("REST: unexpected /command/poll-multi status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	// unmarshal
+	err = json.Unmarshal(task.body, &commands)
+	if err != nil {
+		service.logger.Warnf("REST: failed to parse /command/poll-multi body (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// PollCommandsMulti() function polls the commands of several devices at once.
+// Device keys could not be used, access key authorization is required.
+func (service *Service) PollCommandsMulti(deviceIds []string, timestamp core.Timestamp, names, waitTimeout string, timeout time.Duration) (commands []DeviceCommand, err error) {
+	return service.pollCommandsMulti(context.Background(), deviceIds, timestamp, names, waitTimeout, timeout)
+}
+
+// poll the commands of several devices, cancellable
+func (service *Service) pollCommandsMulti(ctx context.Context, deviceIds []string, timestamp core.Timestamp, names, waitTimeout string, timeout time.Duration) (commands []DeviceCommand, err error) {
+	service.logger.Debugf("REST: polling commands %q, timestamp:%q...", deviceIds, timestamp)
+
+	task, err := service.preparePollCommandsMulti(deviceIds, timestamp, names, waitTimeout)
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /command/poll-multi task (error: %s)", err)
+		return
+	}
+	task.request = task.request.WithContext(ctx)
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /command/poll-multi task", timeout)
+		err = core.ErrTimeout
+
+	case <-ctx.Done():
+		err = ctx.Err()
+
+	case task = <-service.doAsync(task):
+		commands, err = service.processPollCommandsMulti(task)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /command/poll-multi task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
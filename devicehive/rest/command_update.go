@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"net/http"
 	"time"
 )
@@ -22,13 +21,13 @@ func (service *Service) prepareUpdateCommand(device *core.Device, command *core.
 
 	body, err := json.Marshal(command)
 	if err != nil {
-		log.Warnf("REST: failed to format /command/update request (error: %s)", err)
+		service.logger.Warnf("REST: failed to format /command/update request (error: %s)", err)
 		return
 	}
 
 	task.request, err = http.NewRequest("PUT", url, bytes.NewBuffer(body))
 	if err != nil {
-		log.Warnf("REST: failed to create /command/update request (error: %s)", err)
+		service.logger.Warnf("REST: failed to create /command/update request (error: %s)", err)
 		return
 	}
 	task.request.Header.Add("Content-Type", "application/json")
@@ -50,7 +49,7 @@ func (service *Service) processUpdateCommand(task Task, command *core.Command) (
 	// check status code
 	if task.response.StatusCode < http.StatusOK ||
 		task.response.StatusCode > http.StatusPartialContent {
-		log.Warnf("REST: unexpected /command/update status %s",
+		service.logger.Warnf("REST: unexpected /command/update status %s",
 			task.response.Status)
 		err = fmt.Errorf("unexpected status: %s",
 			task.response.Status)
@@ -61,7 +60,7 @@ func (service *Service) processUpdateCommand(task Task, command *core.Command) (
 	// unmarshal
 	//	err = json.Unmarshal(task.body, command)
 	//	if err != nil {
-	//		log.Warnf("REST: failed to parse /command/update body (error: %s)", err)
+	//		service.logger.Warnf("REST: failed to parse /command/update body (error: %s)", err)
 	//		return
 	//	}
 
@@ -70,23 +69,23 @@ func (service *Service) processUpdateCommand(task Task, command *core.Command) (
 
 // UpdateCommand() function updates the device command.
 func (service *Service) UpdateCommand(device *core.Device, command *core.Command, timeout time.Duration) (err error) {
-	log.Debugf("REST: updating command %q to %q...", command.Name, device.Id)
+	service.logger.Debugf("REST: updating command %q to %q...", command.Name, device.Id)
 
 	task, err := service.prepareUpdateCommand(device, command)
 	if err != nil {
-		log.Warnf("REST: failed to prepare /command/update task (error: %s)", err)
+		service.logger.Warnf("REST: failed to prepare /command/update task (error: %s)", err)
 		return
 	}
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("REST: failed to wait %s for /command/update task", timeout)
-		err = fmt.Errorf("timed out")
+		service.logger.Warnf("REST: failed to wait %s for /command/update task", timeout)
+		err = core.ErrTimeout
 
 	case task = <-service.doAsync(task):
 		err = service.processUpdateCommand(task, command)
 		if err != nil {
-			log.Warnf("REST: failed to process /command/update task (error: %s)", err)
+			service.logger.Warnf("REST: failed to process /command/update task (error: %s)", err)
 			return
 		}
 	}
@@ -0,0 +1,169 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Interval between command requests while waiting for
+// the final status after an intermediate one.
+var CommandRefreshInterval = 1 * time.Second
+
+// Prepare PollCommandResult task
+func (service *Service) preparePollCommandResult(device *core.Device, commandId uint64, waitTimeout string) (task Task, err error) {
+	// create request
+	query := url.Values{}
+	if len(waitTimeout) != 0 {
+		query.Set("waitTimeout", waitTimeout)
+	}
+	url := fmt.Sprintf("%s/device/%s/command/%d/poll", service.baseUrl, device.Id, commandId)
+	if len(query) != 0 {
+		url += "?" + query.Encode()
+	}
+
+	task.request, err = http.NewRequest("GET", url, nil)
+	if err != nil {
+		service.logger.Warnf("REST: failed to create /command/result request (error: %s)", err)
+		return
+	}
+
+	// authorization
+	service.prepareAuthorization(task.request, device)
+
+	return
+}
+
+// Process PollCommandResult task
+func (service *Service) processPollCommandResult(task Task) (command *core.Command, err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	switch task.response.StatusCode {
+	case http.StatusOK:
+		break // OK
+
+	case http.StatusNoContent:
+		return // not updated yet
+
+	default:
+		service.logger.Warnf("REST: unexpected /command/result status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	// unmarshal
+	command = &core.Command{}
+	err = json.Unmarshal(task.body, command)
+	if err != nil {
+		service.logger.Warnf("REST: failed to parse /command/result body (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// PollCommandResult() function waits for the command update.
+// Returns nil command if the command is not updated during waitTimeout.
+func (service *Service) PollCommandResult(device *core.Device, commandId uint64, waitTimeout string, timeout time.Duration) (command *core.Command, err error) {
+	service.logger.Debugf("REST: polling command result %q/%d...", device.Id, commandId)
+
+	task, err := service.preparePollCommandResult(device, commandId, waitTimeout)
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /command/result task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /command/result task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		command, err = service.processPollCommandResult(task)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /command/result task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
+
+// SendCommandAndWait() function inserts the command and waits until
+// the device reports the final status. The command's Status and Result
+// are updated in place.
+//
+// Update callback is called for every status change, including the final one.
+// It returns true to finish waiting. Nil update finishes on the first final
+// status (see core.Command.IsFinal). Timeout is the deadline for the whole call.
+//
+// The first update is received by long polling, intermediate statuses
+// are tracked by requesting the command every CommandRefreshInterval.
+func (service *Service) SendCommandAndWait(device *core.Device, command *core.Command, timeout time.Duration, update func(command *core.Command) bool) (err error) {
+	deadline := time.Now().Add(timeout)
+	if update == nil {
+		update = (*core.Command).IsFinal
+	}
+
+	err = service.InsertCommand(device, command, timeout)
+	if err != nil {
+		return
+	}
+
+	polled := false // first update is received
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			service.logger.Warnf("REST: failed to wait %s for command %d result", timeout, command.Id)
+			return core.ErrTimeout
+		}
+
+		var got *core.Command
+		if !polled {
+			wait := int(remaining / time.Second)
+			if wait > MultiPollWaitTimeout {
+				wait = MultiPollWaitTimeout
+			} else if wait < 1 {
+				wait = 1
+			}
+			got, err = service.PollCommandResult(device, command.Id,
+				strconv.Itoa(wait), remaining)
+			polled = got != nil
+		} else {
+			delay := CommandRefreshInterval
+			if delay >= remaining {
+				time.Sleep(remaining)
+				continue // deadline
+			}
+			time.Sleep(delay)
+			got, err = service.GetCommand(device, command.Id, remaining-delay)
+		}
+		if err != nil {
+			return
+		}
+		if got == nil {
+			continue // not updated yet
+		}
+
+		// status or result is changed
+		prev := command.Clone()
+		command.Status, command.Result = got.Status, got.Result
+		if !prev.Equal(command) {
+			service.logger.Debugf("REST: command %d is updated, status:%q", command.Id, command.Status)
+			if update(command) {
+				return nil // done
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package rest
+
+import (
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"time"
+)
+
+// Prepare DeleteDeviceClass task
+func (service *Service) prepareDeleteDeviceClass(deviceClass *core.DeviceClass) (task Task, err error) {
+	// create request
+	url := fmt.Sprintf("%s/device/class/%d", service.baseUrl, deviceClass.Id)
+	task.request, err = http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		service.logger.Warnf("REST: failed to create /device/class/delete request (error: %s)", err)
+		return
+	}
+
+	// authorization
+	service.prepareAuthorization(task.request, nil)
+
+	return
+}
+
+// Process DeleteDeviceClass task
+func (service *Service) processDeleteDeviceClass(task Task) (err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode < http.StatusOK ||
+		task.response.StatusCode > http.StatusPartialContent {
+		service.logger.Warnf("REST: unexpected /device/class/delete status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	return
+}
+
+// DeleteDeviceClass() function deletes the device class.
+func (service *Service) DeleteDeviceClass(deviceClass *core.DeviceClass, timeout time.Duration) (err error) {
+	service.logger.Tracef("REST: deleting device class %d...", deviceClass.Id)
+
+	task, err := service.prepareDeleteDeviceClass(deviceClass)
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /device/class/delete task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /device/class/delete task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		err = service.processDeleteDeviceClass(task)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /device/class/delete task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,80 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"time"
+)
+
+// Prepare GetDeviceClass task
+func (service *Service) prepareGetDeviceClass(deviceClassId uint64) (task Task, err error) {
+	// create request
+	url := fmt.Sprintf("%s/device/class/%d", service.baseUrl, deviceClassId)
+
+	task.request, err = http.NewRequest("GET", url, nil)
+	if err != nil {
+		service.logger.Warnf("REST: failed to create /device/class/get request (error: %s)", err)
+		return
+	}
+
+	// authorization
+	service.prepareAuthorization(task.request, nil)
+
+	return
+}
+
+// Process GetDeviceClass task
+func (service *Service) processGetDeviceClass(task Task, deviceClass *core.DeviceClass) (err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode != http.StatusOK {
+		service.logger.Warnf("REST: unexpected /device/class/get status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	// unmarshal
+	err = json.Unmarshal(task.body, deviceClass)
+	if err != nil {
+		service.logger.Warnf("REST: failed to parse /device/class/get body (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// GetDeviceClass() function gets the device class data.
+func (service *Service) GetDeviceClass(deviceClassId uint64, timeout time.Duration) (deviceClass *core.DeviceClass, err error) {
+	service.logger.Tracef("REST: getting device class %d...", deviceClassId)
+
+	task, err := service.prepareGetDeviceClass(deviceClassId)
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /device/class/get task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /device/class/get task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		deviceClass = &core.DeviceClass{Id: deviceClassId}
+		err = service.processGetDeviceClass(task, deviceClass)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /device/class/get task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,97 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"time"
+)
+
+// Prepare InsertDeviceClass task
+func (service *Service) prepareInsertDeviceClass(deviceClass *core.DeviceClass) (task Task, err error) {
+	// create request
+	url := fmt.Sprintf("%s/device/class", service.baseUrl)
+
+	cls_data := *deviceClass
+	cls_data.Id = 0 // do not put ID to the request body
+	body, err := json.Marshal(&cls_data)
+	if err != nil {
+		service.logger.Warnf("REST: failed to format /device/class/insert request (error: %s)", err)
+		return
+	}
+
+	task.request, err = http.NewRequest("POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		service.logger.Warnf("REST: failed to create /device/class/insert request (error: %s)", err)
+		return
+	}
+	task.request.Header.Add("Content-Type", "application/json")
+
+	// authorization
+	service.prepareAuthorization(task.request, nil)
+
+	return
+}
+
+// Process InsertDeviceClass task
+func (service *Service) processInsertDeviceClass(task Task, deviceClass *core.DeviceClass) (err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode < http.StatusOK ||
+		task.response.StatusCode > http.StatusPartialContent {
+		service.logger.Warnf("REST: unexpected /device/class/insert status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	// unmarshal
+	err = json.Unmarshal(task.body, deviceClass)
+	if err != nil {
+		service.logger.Warnf("REST: failed to parse /device/class/insert body (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// InsertDeviceClass() function inserts the device class.
+func (service *Service) InsertDeviceClass(deviceClass *core.DeviceClass, timeout time.Duration) (err error) {
+	service.logger.Tracef("REST: inserting device class %q...", deviceClass.Name)
+
+	// client-side validation
+	err = core.Validate(deviceClass)
+	if err != nil {
+		service.logger.Warnf("REST: invalid device class (error: %s)", err)
+		return
+	}
+
+	task, err := service.prepareInsertDeviceClass(deviceClass)
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /device/class/insert task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /device/class/insert task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		err = service.processInsertDeviceClass(task, deviceClass)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /device/class/insert task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
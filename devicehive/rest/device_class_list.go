@@ -0,0 +1,88 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"time"
+)
+
+// Prepare GetDeviceClassList task
+func (service *Service) prepareGetDeviceClassList(filter *core.DeviceClassFilter) (task Task, err error) {
+	// create request
+	query := filter.Query()
+	url := fmt.Sprintf("%s/device/class", service.baseUrl)
+	if len(query) != 0 {
+		url += "?" + query.Encode()
+	}
+
+	task.request, err = http.NewRequest("GET", url, nil)
+	if err != nil {
+		service.logger.Warnf("REST: failed to create /device/class/list request (error: %s)", err)
+		return
+	}
+
+	// authorization
+	service.prepareAuthorization(task.request, nil)
+
+	return
+}
+
+// Process GetDeviceClassList task
+func (service *Service) processGetDeviceClassList(task Task) (deviceClasses []core.DeviceClass, err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode != http.StatusOK {
+		service.logger.Warnf("REST: unexpected /device/class/list status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	// unmarshal
+	err = json.Unmarshal(task.body, &deviceClasses)
+	if err != nil {
+		service.logger.Warnf("REST: failed to parse /device/class/list body (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// GetDeviceClassList() function gets the device class list.
+func (service *Service) GetDeviceClassList(take, skip int, timeout time.Duration) (deviceClasses []core.DeviceClass, err error) {
+	return service.GetDeviceClassListFilter(&core.DeviceClassFilter{Take: take, Skip: skip}, timeout)
+}
+
+// GetDeviceClassListFilter() function gets the device class list using filter.
+func (service *Service) GetDeviceClassListFilter(filter *core.DeviceClassFilter, timeout time.Duration) (deviceClasses []core.DeviceClass, err error) {
+	service.logger.Tracef("REST: getting device class list (filter:%+v)...", filter)
+
+	task, err := service.prepareGetDeviceClassList(filter)
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /device/class/list task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /device/class/list task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		deviceClasses, err = service.processGetDeviceClassList(task)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /device/class/list task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
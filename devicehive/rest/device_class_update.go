@@ -0,0 +1,120 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"time"
+)
+
+// device class update request body
+// zero values are sent explicitly, so they can be cleared
+// (nil data is sent as null)
+type deviceClassUpdate struct {
+	Name           *string            `json:"name,omitempty"`
+	Version        *string            `json:"version,omitempty"`
+	IsPermanent    *bool              `json:"isPermanent,omitempty"`
+	OfflineTimeout *int               `json:"offlineTimeout,omitempty"`
+	Data           *json.RawMessage   `json:"data,omitempty"`
+	Equipment      *[]*core.Equipment `json:"equipment,omitempty"`
+}
+
+// Prepare UpdateDeviceClass task
+func (service *Service) prepareUpdateDeviceClass(deviceClass *core.DeviceClass) (task Task, err error) {
+	// create request
+	url := fmt.Sprintf("%s/device/class/%d", service.baseUrl, deviceClass.Id)
+
+	// ID is not put to the request body
+	cls_data := deviceClassUpdate{
+		Name:           &deviceClass.Name,
+		Version:        &deviceClass.Version,
+		IsPermanent:    &deviceClass.IsPermanent,
+		OfflineTimeout: &deviceClass.OfflineTimeout}
+
+	data, err := json.Marshal(deviceClass.Data)
+	if err != nil {
+		service.logger.Warnf("REST: failed to format /device/class/update data (error: %s)", err)
+		return
+	}
+	cls_data.Data = (*json.RawMessage)(&data)
+
+	// nil equipment list keeps the equipment,
+	// empty (but not nil) list removes all the equipment
+	if deviceClass.Equipment != nil {
+		cls_data.Equipment = &deviceClass.Equipment
+	}
+
+	body, err := json.Marshal(&cls_data)
+	if err != nil {
+		service.logger.Warnf("REST: failed to format /device/class/update request (error: %s)", err)
+		return
+	}
+
+	task.request, err = http.NewRequest("PUT", url, bytes.NewBuffer(body))
+	if err != nil {
+		service.logger.Warnf("REST: failed to create /device/class/update request (error: %s)", err)
+		return
+	}
+	task.request.Header.Add("Content-Type", "application/json")
+
+	// authorization
+	service.prepareAuthorization(task.request, nil)
+
+	return
+}
+
+// Process UpdateDeviceClass task
+func (service *Service) processUpdateDeviceClass(task Task) (err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode < http.StatusOK ||
+		task.response.StatusCode > http.StatusPartialContent {
+		service.logger.Warnf("REST: unexpected /device/class/update status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	return
+}
+
+// UpdateDeviceClass() function updates the device class.
+func (service *Service) UpdateDeviceClass(deviceClass *core.DeviceClass, timeout time.Duration) (err error) {
+	service.logger.Tracef("REST: updating device class %d...", deviceClass.Id)
+
+	// client-side validation
+	err = core.Validate(deviceClass)
+	if err != nil {
+		service.logger.Warnf("REST: invalid device class (error: %s)", err)
+		return
+	}
+
+	task, err := service.prepareUpdateDeviceClass(deviceClass)
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /device/class/update task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /device/class/update task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		err = service.processUpdateDeviceClass(task)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /device/class/update task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
@@ -3,7 +3,6 @@ package rest
 import (
 	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"net/http"
 	"time"
 )
@@ -14,7 +13,7 @@ func (service *Service) prepareDeleteDevice(device *core.Device) (task Task, err
 	url := fmt.Sprintf("%s/device/%s", service.baseUrl, device.Id)
 	task.request, err = http.NewRequest("DELETE", url, nil)
 	if err != nil {
-		log.Warnf("REST: failed to create /device/delete request (error: %s)", err)
+		service.logger.Warnf("REST: failed to create /device/delete request (error: %s)", err)
 		return
 	}
 
@@ -35,7 +34,7 @@ func (service *Service) processDeleteDevice(task Task) (err error) {
 	// check status code
 	if task.response.StatusCode < http.StatusOK ||
 		task.response.StatusCode > http.StatusPartialContent {
-		log.Warnf("REST: unexpected /device/delete status %s",
+		service.logger.Warnf("REST: unexpected /device/delete status %s",
 			task.response.Status)
 		err = fmt.Errorf("unexpected status: %s",
 			task.response.Status)
@@ -47,23 +46,23 @@ func (service *Service) processDeleteDevice(task Task) (err error) {
 
 // DeleteDevice() function deletes the device.
 func (service *Service) DeleteDevice(device *core.Device, timeout time.Duration) (err error) {
-	log.Debugf("REST: deleting device %q...", device.Id)
+	service.logger.Debugf("REST: deleting device %q...", device.Id)
 
 	task, err := service.prepareDeleteDevice(device)
 	if err != nil {
-		log.Warnf("REST: failed to prepare /device/delete task (error: %s)", err)
+		service.logger.Warnf("REST: failed to prepare /device/delete task (error: %s)", err)
 		return
 	}
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("REST: failed to wait %s for /device/delete task", timeout)
-		err = fmt.Errorf("timed out")
+		service.logger.Warnf("REST: failed to wait %s for /device/delete task", timeout)
+		err = core.ErrTimeout
 
 	case task = <-service.doAsync(task):
 		err = service.processDeleteDevice(task)
 		if err != nil {
-			log.Warnf("REST: failed to process /device/delete task (error: %s)", err)
+			service.logger.Warnf("REST: failed to process /device/delete task (error: %s)", err)
 			return
 		}
 	}
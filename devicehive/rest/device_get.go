@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"net/http"
 	"time"
 )
@@ -16,7 +15,7 @@ func (service *Service) prepareGetDevice(deviceId, deviceKey string) (task Task,
 
 	task.request, err = http.NewRequest("GET", url, nil)
 	if err != nil {
-		log.Warnf("REST: failed to create /device/get request (error: %s)", err)
+		service.logger.Warnf("REST: failed to create /device/get request (error: %s)", err)
 		return
 	}
 
@@ -37,7 +36,7 @@ func (service *Service) processGetDevice(task Task, device *core.Device) (err er
 
 	// check status code
 	if task.response.StatusCode != http.StatusOK {
-		log.Warnf("REST: unexpected /device/get status %s",
+		service.logger.Warnf("REST: unexpected /device/get status %s",
 			task.response.Status)
 		err = fmt.Errorf("unexpected status: %s",
 			task.response.Status)
@@ -47,7 +46,7 @@ func (service *Service) processGetDevice(task Task, device *core.Device) (err er
 	// unmarshal
 	err = json.Unmarshal(task.body, device)
 	if err != nil {
-		log.Warnf("REST: failed to parse /device/get body (error: %s)", err)
+		service.logger.Warnf("REST: failed to parse /device/get body (error: %s)", err)
 		return
 	}
 
@@ -56,24 +55,24 @@ func (service *Service) processGetDevice(task Task, device *core.Device) (err er
 
 // GetDevice() function get the device data.
 func (service *Service) GetDevice(deviceId, deviceKey string, timeout time.Duration) (device *core.Device, err error) {
-	log.Tracef("REST: getting device %q...", deviceId)
+	service.logger.Tracef("REST: getting device %q...", deviceId)
 
 	task, err := service.prepareGetDevice(deviceId, deviceKey)
 	if err != nil {
-		log.Warnf("REST: failed to prepare /device/get task (error: %s)", err)
+		service.logger.Warnf("REST: failed to prepare /device/get task (error: %s)", err)
 		return
 	}
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("REST: failed to wait %s for /device/get task", timeout)
-		err = fmt.Errorf("timed out")
+		service.logger.Warnf("REST: failed to wait %s for /device/get task", timeout)
+		err = core.ErrTimeout
 
 	case task = <-service.doAsync(task):
 		device = &core.Device{Id: deviceId, Key: deviceKey}
 		err = service.processGetDevice(task, device)
 		if err != nil {
-			log.Warnf("REST: failed to process /device/get task (error: %s)", err)
+			service.logger.Warnf("REST: failed to process /device/get task (error: %s)", err)
 			return
 		}
 	}
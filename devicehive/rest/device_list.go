@@ -4,22 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"net/http"
-	"net/url"
 	"time"
 )
 
 // Prepare GetDeviceList task
-func (service *Service) prepareGetDeviceList(take, skip int) (task Task, err error) {
+func (service *Service) prepareGetDeviceList(filter *core.DeviceFilter) (task Task, err error) {
 	// create request
-	query := url.Values{}
-	if take > 0 {
-		query.Set("take", fmt.Sprintf("%d", take))
-	}
-	if skip > 0 {
-		query.Set("skip", fmt.Sprintf("%d", skip))
-	}
+	query := filter.Query()
 	url := fmt.Sprintf("%s/device", service.baseUrl)
 	if len(query) != 0 {
 		url += "?" + query.Encode()
@@ -27,7 +19,7 @@ func (service *Service) prepareGetDeviceList(take, skip int) (task Task, err err
 
 	task.request, err = http.NewRequest("GET", url, nil)
 	if err != nil {
-		log.Warnf("REST: failed to create /device/list request (error: %s)", err)
+		service.logger.Warnf("REST: failed to create /device/list request (error: %s)", err)
 		return
 	}
 
@@ -47,7 +39,7 @@ func (service *Service) processGetDeviceList(task Task) (devices []core.Device,
 
 	// check status code
 	if task.response.StatusCode != http.StatusOK {
-		log.Warnf("REST: unexpected /device/list status %s",
+		service.logger.Warnf("REST: unexpected /device/list status %s",
 			task.response.Status)
 		err = fmt.Errorf("unexpected status: %s",
 			task.response.Status)
@@ -57,32 +49,37 @@ func (service *Service) processGetDeviceList(task Task) (devices []core.Device,
 	// unmarshal
 	err = json.Unmarshal(task.body, &devices)
 	if err != nil {
-		log.Warnf("REST: failed to parse /device/list body (error: %s)", err)
+		service.logger.Warnf("REST: failed to parse /device/list body (error: %s)", err)
 		return
 	}
 
 	return
 }
 
-// GetDeviceList() function get the device list.
+// GetDeviceList() function gets the device list.
 func (service *Service) GetDeviceList(take, skip int, timeout time.Duration) (devices []core.Device, err error) {
-	log.Tracef("REST: getting device list (take:%d, skip:%d)...", take, skip)
+	return service.GetDeviceListFilter(&core.DeviceFilter{Take: take, Skip: skip}, timeout)
+}
+
+// GetDeviceListFilter() function gets the device list using filter.
+func (service *Service) GetDeviceListFilter(filter *core.DeviceFilter, timeout time.Duration) (devices []core.Device, err error) {
+	service.logger.Tracef("REST: getting device list (filter:%+v)...", filter)
 
-	task, err := service.prepareGetDeviceList(take, skip)
+	task, err := service.prepareGetDeviceList(filter)
 	if err != nil {
-		log.Warnf("REST: failed to prepare /device/list task (error: %s)", err)
+		service.logger.Warnf("REST: failed to prepare /device/list task (error: %s)", err)
 		return
 	}
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("REST: failed to wait %s for /device/list task", timeout)
-		err = fmt.Errorf("timed out")
+		service.logger.Warnf("REST: failed to wait %s for /device/list task", timeout)
+		err = core.ErrTimeout
 
 	case task = <-service.doAsync(task):
 		devices, err = service.processGetDeviceList(task)
 		if err != nil {
-			log.Warnf("REST: failed to process /device/list task (error: %s)", err)
+			service.logger.Warnf("REST: failed to process /device/list task (error: %s)", err)
 			return
 		}
 	}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"net/http"
 	"time"
 )
@@ -19,13 +18,13 @@ func (service *Service) prepareRegisterDevice(device *core.Device) (task Task, e
 	dev_data.Id = "" // do not put ID to the request body
 	body, err := json.Marshal(&dev_data)
 	if err != nil {
-		log.Warnf("REST: failed to format /device/register request (error: %s)", err)
+		service.logger.Warnf("REST: failed to format /device/register request (error: %s)", err)
 		return
 	}
 
 	task.request, err = http.NewRequest("PUT", url, bytes.NewBuffer(body))
 	if err != nil {
-		log.Warnf("REST: failed to create /device/register request (error: %s)", err)
+		service.logger.Warnf("REST: failed to create /device/register request (error: %s)", err)
 		return
 	}
 	task.request.Header.Add("Content-Type", "application/json")
@@ -47,7 +46,7 @@ func (service *Service) processRegisterDevice(task Task) (err error) {
 	// check status code
 	if task.response.StatusCode < http.StatusOK ||
 		task.response.StatusCode > http.StatusPartialContent {
-		log.Warnf("REST: unexpected /device/register status %s",
+		service.logger.Warnf("REST: unexpected /device/register status %s",
 			task.response.Status)
 		err = fmt.Errorf("unexpected status: %s",
 			task.response.Status)
@@ -59,23 +58,30 @@ func (service *Service) processRegisterDevice(task Task) (err error) {
 
 // RegisterDevice() function registers the device.
 func (service *Service) RegisterDevice(device *core.Device, timeout time.Duration) (err error) {
-	log.Tracef("REST: registering device %q...", device.Id)
+	service.logger.Tracef("REST: registering device %q...", device.Id)
+
+	// client-side validation
+	err = core.Validate(device)
+	if err != nil {
+		service.logger.Warnf("REST: invalid device (error: %s)", err)
+		return
+	}
 
 	task, err := service.prepareRegisterDevice(device)
 	if err != nil {
-		log.Warnf("REST: failed to prepare /device/register task (error: %s)", err)
+		service.logger.Warnf("REST: failed to prepare /device/register task (error: %s)", err)
 		return
 	}
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("REST: failed to wait %s for /device/register task", timeout)
-		err = fmt.Errorf("timed out")
+		service.logger.Warnf("REST: failed to wait %s for /device/register task", timeout)
+		err = core.ErrTimeout
 
 	case task = <-service.doAsync(task):
 		err = service.processRegisterDevice(task)
 		if err != nil {
-			log.Warnf("REST: failed to process /device/register task (error: %s)", err)
+			service.logger.Warnf("REST: failed to process /device/register task (error: %s)", err)
 			return
 		}
 	}
@@ -0,0 +1,89 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"time"
+)
+
+// Prepare UpdateDevice task
+func (service *Service) prepareUpdateDevice(device *core.Device, update *core.Device) (task Task, err error) {
+	// create request
+	url := fmt.Sprintf("%s/device/%s", service.baseUrl, device.Id)
+
+	dev_data := *update
+	dev_data.Id = "" // do not put ID to the request body
+	body, err := json.Marshal(&dev_data)
+	if err != nil {
+		service.logger.Warnf("REST: failed to format /device/update request (error: %s)", err)
+		return
+	}
+
+	task.request, err = http.NewRequest("PUT", url, bytes.NewBuffer(body))
+	if err != nil {
+		service.logger.Warnf("REST: failed to create /device/update request (error: %s)", err)
+		return
+	}
+	task.request.Header.Add("Content-Type", "application/json")
+
+	// authorization
+	service.prepareAuthorization(task.request, device)
+
+	return
+}
+
+// Process UpdateDevice task
+func (service *Service) processUpdateDevice(task Task) (err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode < http.StatusOK ||
+		task.response.StatusCode > http.StatusPartialContent {
+		service.logger.Warnf("REST: unexpected /device/update status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	return
+}
+
+// UpdateDevice() function partially updates the registered device.
+// Device is used for identification and authorization, update contains
+// only the fields to change, empty fields are not sent (see core.Device.Delta).
+// Nil update does nothing.
+func (service *Service) UpdateDevice(device *core.Device, update *core.Device, timeout time.Duration) (err error) {
+	if update == nil {
+		return // nothing to update
+	}
+	service.logger.Tracef("REST: updating device %q...", device.Id)
+
+	task, err := service.prepareUpdateDevice(device, update)
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /device/update task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /device/update task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		err = service.processUpdateDevice(task)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /device/update task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,152 @@
+package rest
+
+import (
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"time"
+)
+
+// Default number of items requested per page.
+const DefaultPageSize = core.DefaultPageSize
+
+// DeviceIterator walks all the devices matching the filter page by page.
+//
+//	it := service.IterateDevices(filter, 0, timeout)
+//	for it.Next() {
+//		device := it.Device()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type DeviceIterator struct {
+	core.Pager[core.Device]
+}
+
+// Device() gets the current device.
+func (it *DeviceIterator) Device() *core.Device {
+	return it.Current()
+}
+
+// IterateDevices() creates the device iterator.
+// Filter's Take and Skip limit the whole sequence, not a page.
+// Zero pageSize means DefaultPageSize, timeout is used for each page request.
+func (service *Service) IterateDevices(filter *core.DeviceFilter, pageSize int, timeout time.Duration) *DeviceIterator {
+	f := core.DeviceFilter{}
+	if filter != nil {
+		f = *filter
+	}
+	fetch := func(take, skip int, timeout time.Duration) ([]core.Device, error) {
+		page := f // copy
+		page.Take, page.Skip = take, skip
+		return service.GetDeviceListFilter(&page, timeout)
+	}
+	return &DeviceIterator{core.NewPager(fetch, f.Take, f.Skip, pageSize, timeout)}
+}
+
+// NetworkIterator walks all the networks matching the filter page by page.
+type NetworkIterator struct {
+	core.Pager[core.Network]
+}
+
+// Network() gets the current network.
+func (it *NetworkIterator) Network() *core.Network {
+	return it.Current()
+}
+
+// IterateNetworks() creates the network iterator.
+// Filter's Take and Skip limit the whole sequence, not a page.
+// Zero pageSize means DefaultPageSize, timeout is used for each page request.
+func (service *Service) IterateNetworks(filter *core.NetworkFilter, pageSize int, timeout time.Duration) *NetworkIterator {
+	f := core.NetworkFilter{}
+	if filter != nil {
+		f = *filter
+	}
+	fetch := func(take, skip int, timeout time.Duration) ([]core.Network, error) {
+		page := f // copy
+		page.Take, page.Skip = take, skip
+		return service.GetNetworkListFilter(&page, timeout)
+	}
+	return &NetworkIterator{core.NewPager(fetch, f.Take, f.Skip, pageSize, timeout)}
+}
+
+// DeviceClassIterator walks all the device classes matching the filter page by page.
+type DeviceClassIterator struct {
+	core.Pager[core.DeviceClass]
+}
+
+// DeviceClass() gets the current device class.
+func (it *DeviceClassIterator) DeviceClass() *core.DeviceClass {
+	return it.Current()
+}
+
+// IterateDeviceClasses() creates the device class iterator.
+// Filter's Take and Skip limit the whole sequence, not a page.
+// Zero pageSize means DefaultPageSize, timeout is used for each page request.
+func (service *Service) IterateDeviceClasses(filter *core.DeviceClassFilter, pageSize int, timeout time.Duration) *DeviceClassIterator {
+	f := core.DeviceClassFilter{}
+	if filter != nil {
+		f = *filter
+	}
+	fetch := func(take, skip int, timeout time.Duration) ([]core.DeviceClass, error) {
+		page := f // copy
+		page.Take, page.Skip = take, skip
+		return service.GetDeviceClassListFilter(&page, timeout)
+	}
+	return &DeviceClassIterator{core.NewPager(fetch, f.Take, f.Skip, pageSize, timeout)}
+}
+
+// UserIterator walks all the users matching the filter page by page.
+type UserIterator struct {
+	core.Pager[core.User]
+}
+
+// User() gets the current user.
+func (it *UserIterator) User() *core.User {
+	return it.Current()
+}
+
+// IterateUsers() creates the user iterator.
+// Filter's Take and Skip limit the whole sequence, not a page.
+// Zero pageSize means DefaultPageSize, timeout is used for each page request.
+func (service *Service) IterateUsers(filter *core.UserFilter, pageSize int, timeout time.Duration) *UserIterator {
+	f := core.UserFilter{}
+	if filter != nil {
+		f = *filter
+	}
+	fetch := func(take, skip int, timeout time.Duration) ([]core.User, error) {
+		page := f // copy
+		page.Take, page.Skip = take, skip
+		return service.GetUserListFilter(&page, timeout)
+	}
+	return &UserIterator{core.NewPager(fetch, f.Take, f.Skip, pageSize, timeout)}
+}
+
+// IterateCommands() creates the command history iterator of the device.
+// Filter's Take and Skip limit the whole sequence, not a page.
+// Zero pageSize means DefaultPageSize, timeout is used for each page request.
+func (service *Service) IterateCommands(device *core.Device, filter *core.CommandFilter, pageSize int, timeout time.Duration) *core.CommandIterator {
+	f := core.CommandFilter{}
+	if filter != nil {
+		f = *filter
+	}
+	fetch := func(take, skip int, timeout time.Duration) ([]core.Command, error) {
+		page := f // copy
+		page.Take, page.Skip = take, skip
+		return service.GetCommandList(device, &page, timeout)
+	}
+	return &core.CommandIterator{Pager: core.NewPager(fetch, f.Take, f.Skip, pageSize, timeout)}
+}
+
+// IterateNotifications() creates the notification history iterator of the device.
+// Filter's Take and Skip limit the whole sequence, not a page.
+// Zero pageSize means DefaultPageSize, timeout is used for each page request.
+func (service *Service) IterateNotifications(device *core.Device, filter *core.NotificationFilter, pageSize int, timeout time.Duration) *core.NotificationIterator {
+	f := core.NotificationFilter{}
+	if filter != nil {
+		f = *filter
+	}
+	fetch := func(take, skip int, timeout time.Duration) ([]core.Notification, error) {
+		page := f // copy
+		page.Take, page.Skip = take, skip
+		return service.GetNotificationList(device, &page, timeout)
+	}
+	return &core.NotificationIterator{Pager: core.NewPager(fetch, f.Take, f.Skip, pageSize, timeout)}
+}
@@ -3,7 +3,6 @@ package rest
 import (
 	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"net/http"
 	"time"
 )
@@ -14,7 +13,7 @@ func (service *Service) prepareDeleteNetwork(network *core.Network) (task Task,
 	url := fmt.Sprintf("%s/network/%d", service.baseUrl, network.Id)
 	task.request, err = http.NewRequest("DELETE", url, nil)
 	if err != nil {
-		log.Warnf("REST: failed to create /network/delete request (error: %s)", err)
+		service.logger.Warnf("REST: failed to create /network/delete request (error: %s)", err)
 		return
 	}
 
@@ -34,7 +33,7 @@ func (service *Service) processDeleteNetwork(task Task) (err error) {
 
 	// check status code
 	if task.response.StatusCode != http.StatusOK {
-		log.Warnf("REST: unexpected /network/delete status %s",
+		service.logger.Warnf("REST: unexpected /network/delete status %s",
 			task.response.Status)
 		err = fmt.Errorf("unexpected status: %s",
 			task.response.Status)
@@ -46,23 +45,23 @@ func (service *Service) processDeleteNetwork(task Task) (err error) {
 
 // DeleteNetwork() function deletes the network.
 func (service *Service) DeleteNetwork(network *core.Network, timeout time.Duration) (err error) {
-	log.Tracef("REST: deleting network %d...", network.Id)
+	service.logger.Tracef("REST: deleting network %d...", network.Id)
 
 	task, err := service.prepareDeleteNetwork(network)
 	if err != nil {
-		log.Warnf("REST: failed to prepare /network/delete task (error: %s)", err)
+		service.logger.Warnf("REST: failed to prepare /network/delete task (error: %s)", err)
 		return
 	}
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("REST: failed to wait %s for /network/delete task", timeout)
-		err = fmt.Errorf("timed out")
+		service.logger.Warnf("REST: failed to wait %s for /network/delete task", timeout)
+		err = core.ErrTimeout
 
 	case task = <-service.doAsync(task):
 		err = service.processDeleteNetwork(task)
 		if err != nil {
-			log.Warnf("REST: failed to process /network/delete task (error: %s)", err)
+			service.logger.Warnf("REST: failed to process /network/delete task (error: %s)", err)
 			return
 		}
 	}
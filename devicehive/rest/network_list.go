@@ -4,22 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"net/http"
-	"net/url"
 	"time"
 )
 
 // Prepare GetNetworkList task
-func (service *Service) prepareGetNetworkList(take, skip int) (task Task, err error) {
+func (service *Service) prepareGetNetworkList(filter *core.NetworkFilter) (task Task, err error) {
 	// create request
-	query := url.Values{}
-	if take > 0 {
-		query.Set("take", fmt.Sprintf("%d", take))
-	}
-	if skip > 0 {
-		query.Set("skip", fmt.Sprintf("%d", skip))
-	}
+	query := filter.Query()
 	url := fmt.Sprintf("%s/network", service.baseUrl)
 	if len(query) != 0 {
 		url += "?" + query.Encode()
@@ -27,7 +19,7 @@ func (service *Service) prepareGetNetworkList(take, skip int) (task Task, err er
 
 	task.request, err = http.NewRequest("GET", url, nil)
 	if err != nil {
-		log.Warnf("REST: failed to create /network/list request (error: %s)", err)
+		service.logger.Warnf("REST: failed to create /network/list request (error: %s)", err)
 		return
 	}
 
@@ -47,7 +39,7 @@ func (service *Service) processGetNetworkList(task Task) (networks []core.Networ
 
 	// check status code
 	if task.response.StatusCode != http.StatusOK {
-		log.Warnf("REST: unexpected /network/list status %s",
+		service.logger.Warnf("REST: unexpected /network/list status %s",
 			task.response.Status)
 		err = fmt.Errorf("unexpected status: %s",
 			task.response.Status)
@@ -57,32 +49,37 @@ func (service *Service) processGetNetworkList(task Task) (networks []core.Networ
 	// unmarshal
 	err = json.Unmarshal(task.body, &networks)
 	if err != nil {
-		log.Warnf("REST: failed to parse /network/list body (error: %s)", err)
+		service.logger.Warnf("REST: failed to parse /network/list body (error: %s)", err)
 		return
 	}
 
 	return
 }
 
-// GetNetworkList() function get the network list.
+// GetNetworkList() function gets the network list.
 func (service *Service) GetNetworkList(take, skip int, timeout time.Duration) (networks []core.Network, err error) {
-	log.Tracef("REST: getting network list (take:%d, skip:%d)...", take, skip)
+	return service.GetNetworkListFilter(&core.NetworkFilter{Take: take, Skip: skip}, timeout)
+}
+
+// GetNetworkListFilter() function gets the network list using filter.
+func (service *Service) GetNetworkListFilter(filter *core.NetworkFilter, timeout time.Duration) (networks []core.Network, err error) {
+	service.logger.Tracef("REST: getting network list (filter:%+v)...", filter)
 
-	task, err := service.prepareGetNetworkList(take, skip)
+	task, err := service.prepareGetNetworkList(filter)
 	if err != nil {
-		log.Warnf("REST: failed to prepare /network/list task (error: %s)", err)
+		service.logger.Warnf("REST: failed to prepare /network/list task (error: %s)", err)
 		return
 	}
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("REST: failed to wait %s for /network/list task", timeout)
-		err = fmt.Errorf("timed out")
+		service.logger.Warnf("REST: failed to wait %s for /network/list task", timeout)
+		err = core.ErrTimeout
 
 	case task = <-service.doAsync(task):
 		networks, err = service.processGetNetworkList(task)
 		if err != nil {
-			log.Warnf("REST: failed to process /network/list task (error: %s)", err)
+			service.logger.Warnf("REST: failed to process /network/list task (error: %s)", err)
 			return
 		}
 	}
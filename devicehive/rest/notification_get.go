@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"net/http"
 	"time"
 )
@@ -16,7 +15,7 @@ func (service *Service) prepareGetNotification(device *core.Device, notification
 
 	task.request, err = http.NewRequest("GET", url, nil)
 	if err != nil {
-		log.Warnf("REST: failed to create /notification/get request (error: %s)", err)
+		service.logger.Warnf("REST: failed to create /notification/get request (error: %s)", err)
 		return
 	}
 
@@ -36,7 +35,7 @@ func (service *Service) processGetNotification(task Task, notification *core.Not
 
 	// check status code
 	if task.response.StatusCode != http.StatusOK {
-		log.Warnf("REST: unexpected /notification/get status %s",
+		service.logger.Warnf("REST: unexpected /notification/get status %s",
 			task.response.Status)
 		err = fmt.Errorf("unexpected status: %s",
 			task.response.Status)
@@ -46,7 +45,7 @@ func (service *Service) processGetNotification(task Task, notification *core.Not
 	// unmarshal
 	err = json.Unmarshal(task.body, notification)
 	if err != nil {
-		log.Warnf("REST: failed to parse /notification/get body (error: %s)", err)
+		service.logger.Warnf("REST: failed to parse /notification/get body (error: %s)", err)
 		return
 	}
 
@@ -55,24 +54,24 @@ func (service *Service) processGetNotification(task Task, notification *core.Not
 
 // GetNotification() function get the notification data.
 func (service *Service) GetNotification(device *core.Device, notificationId uint64, timeout time.Duration) (notification *core.Notification, err error) {
-	log.Tracef("REST: getting notification %q/%d...", device.Id, notificationId)
+	service.logger.Tracef("REST: getting notification %q/%d...", device.Id, notificationId)
 
 	task, err := service.prepareGetNotification(device, notificationId)
 	if err != nil {
-		log.Warnf("REST: failed to prepare /notification/get task (error: %s)", err)
+		service.logger.Warnf("REST: failed to prepare /notification/get task (error: %s)", err)
 		return
 	}
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("REST: failed to wait %s for /notification/get task", timeout)
-		err = fmt.Errorf("timed out")
+		service.logger.Warnf("REST: failed to wait %s for /notification/get task", timeout)
+		err = core.ErrTimeout
 
 	case task = <-service.doAsync(task):
 		notification = &core.Notification{Id: notificationId}
 		err = service.processGetNotification(task, notification)
 		if err != nil {
-			log.Warnf("REST: failed to process /notification/get task (error: %s)", err)
+			service.logger.Warnf("REST: failed to process /notification/get task (error: %s)", err)
 			return
 		}
 	}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"net/http"
 	"time"
 )
@@ -21,13 +20,13 @@ func (service *Service) prepareInsertNotification(device *core.Device, notificat
 
 	body, err := json.Marshal(notification)
 	if err != nil {
-		log.Warnf("REST: failed to format /notification/insert request (error: %s)", err)
+		service.logger.Warnf("REST: failed to format /notification/insert request (error: %s)", err)
 		return
 	}
 
 	task.request, err = http.NewRequest("POST", url, bytes.NewBuffer(body))
 	if err != nil {
-		log.Warnf("REST: failed to create /notification/insert request (error: %s)", err)
+		service.logger.Warnf("REST: failed to create /notification/insert request (error: %s)", err)
 		return
 	}
 	task.request.Header.Add("Content-Type", "application/json")
@@ -49,7 +48,7 @@ func (service *Service) processInsertNotification(task Task, notification *core.
 	// check status code
 	if task.response.StatusCode < http.StatusOK ||
 		task.response.StatusCode > http.StatusPartialContent {
-		log.Warnf("REST: unexpected /notification/insert status %s",
+		service.logger.Warnf("REST: unexpected /notification/insert status %s",
 			task.response.Status)
 		err = fmt.Errorf("unexpected status: %s",
 			task.response.Status)
@@ -59,7 +58,7 @@ func (service *Service) processInsertNotification(task Task, notification *core.
 	// unmarshal
 	err = json.Unmarshal(task.body, notification)
 	if err != nil {
-		log.Warnf("REST: failed to parse /notification/insert body (error: %s)", err)
+		service.logger.Warnf("REST: failed to parse /notification/insert body (error: %s)", err)
 		return
 	}
 
@@ -68,23 +67,30 @@ func (service *Service) processInsertNotification(task Task, notification *core.
 
 // InsertNotification() function inserts the device notification.
 func (service *Service) InsertNotification(device *core.Device, notification *core.Notification, timeout time.Duration) (err error) {
-	log.Tracef("REST: inserting notification %q to %q...", notification.Name, device.Id)
+	service.logger.Tracef("REST: inserting notification %q to %q...", notification.Name, device.Id)
+
+	// client-side validation
+	err = core.Validate(notification)
+	if err != nil {
+		service.logger.Warnf("REST: invalid notification (error: %s)", err)
+		return
+	}
 
 	task, err := service.prepareInsertNotification(device, notification)
 	if err != nil {
-		log.Warnf("REST: failed to prepare /notification/insert task (error: %s)", err)
+		service.logger.Warnf("REST: failed to prepare /notification/insert task (error: %s)", err)
 		return
 	}
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("REST: failed to wait %s for /notification/insert task", timeout)
-		err = fmt.Errorf("timed out")
+		service.logger.Warnf("REST: failed to wait %s for /notification/insert task", timeout)
+		err = core.ErrTimeout
 
 	case task = <-service.doAsync(task):
 		err = service.processInsertNotification(task, notification)
 		if err != nil {
-			log.Warnf("REST: failed to process /notification/insert task (error: %s)", err)
+			service.logger.Warnf("REST: failed to process /notification/insert task (error: %s)", err)
 			return
 		}
 	}
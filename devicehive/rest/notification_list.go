@@ -0,0 +1,84 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"time"
+)
+
+// Prepare GetNotificationList task
+func (service *Service) prepareGetNotificationList(device *core.Device, filter *core.NotificationFilter) (task Task, err error) {
+	// create request
+	query := filter.Query()
+	url := fmt.Sprintf("%s/device/%s/notification", service.baseUrl, device.Id)
+	if len(query) != 0 {
+		url += "?" + query.Encode()
+	}
+
+	task.request, err = http.NewRequest("GET", url, nil)
+	if err != nil {
+		service.logger.Warnf("REST: failed to create /notification/list request (error: %s)", err)
+		return
+	}
+
+	// authorization
+	service.prepareAuthorization(task.request, device)
+
+	return
+}
+
+// Process GetNotificationList task
+func (service *Service) processGetNotificationList(task Task) (notifications []core.Notification, err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode != http.StatusOK {
+		service.logger.Warnf("REST: unexpected /notification/list status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	// unmarshal
+	err = json.Unmarshal(task.body, &notifications)
+	if err != nil {
+		service.logger.Warnf("REST: failed to parse /notification/list body (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// GetNotificationList() function gets the notification history of the device.
+// Nil filter means server's default.
+func (service *Service) GetNotificationList(device *core.Device, filter *core.NotificationFilter, timeout time.Duration) (notifications []core.Notification, err error) {
+	service.logger.Tracef("REST: getting notification list %q (filter:%+v)...", device.Id, filter)
+
+	task, err := service.prepareGetNotificationList(device, filter)
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /notification/list task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /notification/list task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		notifications, err = service.processGetNotificationList(task)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /notification/list task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
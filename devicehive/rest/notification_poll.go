@@ -4,18 +4,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"net/http"
 	"net/url"
 	"time"
 )
 
 // Prepare PollNotification task
-func (service *Service) preparePollNotification(device *core.Device, timestamp, names, waitTimeout string) (task Task, err error) {
+func (service *Service) preparePollNotification(device *core.Device, timestamp core.Timestamp, names, waitTimeout string) (task Task, err error) {
 	// create request
 	query := url.Values{}
-	if len(timestamp) != 0 {
-		query.Set("timestamp", timestamp)
+	if !timestamp.IsZero() {
+		query.Set("timestamp", timestamp.String())
 	}
 	if len(names) != 0 {
 		query.Set("names", names)
@@ -32,7 +31,7 @@ func (service *Service) preparePollNotification(device *core.Device, timestamp,
 
 	task.request, err = http.NewRequest("GET", url, nil)
 	if err != nil {
-		log.Warnf("REST: failed to create /notification/poll request (error: %s)", err)
+		service.logger.Warnf("REST: failed to create /notification/poll request (error: %s)", err)
 		return
 	}
 
@@ -52,7 +51,7 @@ func (service *Service) processPollNotification(task Task) (notifications []core
 
 	// check status code
 	if task.response.StatusCode != http.StatusOK {
-		log.Warnf("REST: unexpected /notification/poll status %s",
+		service.logger.Warnf("REST: unexpected /notification/poll status %s",
 			task.response.Status)
 		err = fmt.Errorf("unexpected status: %s",
 			task.response.Status)
@@ -62,7 +61,7 @@ func (service *Service) processPollNotification(task Task) (notifications []core
 	// unmarshal
 	err = json.Unmarshal(task.body, &notifications)
 	if err != nil {
-		log.Warnf("REST: failed to parse /notification/poll body (error: %s)", err)
+		service.logger.Warnf("REST: failed to parse /notification/poll body (error: %s)", err)
 		return
 	}
 
@@ -70,24 +69,24 @@ func (service *Service) processPollNotification(task Task) (notifications []core
 }
 
 // GetNotification() function poll the notifications.
-func (service *Service) PollNotifications(device *core.Device, timestamp, names, waitTimeout string, timeout time.Duration) (notifications []core.Notification, err error) {
-	log.Tracef("REST: polling notifications %q...", device.Id)
+func (service *Service) PollNotifications(device *core.Device, timestamp core.Timestamp, names, waitTimeout string, timeout time.Duration) (notifications []core.Notification, err error) {
+	service.logger.Tracef("REST: polling notifications %q...", device.Id)
 
 	task, err := service.preparePollNotification(device, timestamp, names, waitTimeout)
 	if err != nil {
-		log.Warnf("REST: failed to prepare /notification/poll task (error: %s)", err)
+		service.logger.Warnf("REST: failed to prepare /notification/poll task (error: %s)", err)
 		return
 	}
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("REST: failed to wait %s for /notification/poll task", timeout)
-		err = fmt.Errorf("timed out")
+		service.logger.Warnf("REST: failed to wait %s for /notification/poll task", timeout)
+		err = core.ErrTimeout
 
 	case task = <-service.doAsync(task):
 		notifications, err = service.processPollNotification(task)
 		if err != nil {
-			log.Warnf("REST: failed to process /notification/poll task (error: %s)", err)
+			service.logger.Warnf("REST: failed to process /notification/poll task (error: %s)", err)
 			return
 		}
 	}
@@ -0,0 +1,112 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Notification received by multi-device polling.
+type DeviceNotification struct {
+	// Device identifier the notification is sent by.
+	DeviceId string `json:"deviceGuid"`
+
+	// Notification itself.
+	Notification core.Notification `json:"notification"`
+}
+
+// Prepare PollNotificationsMulti task
+func (service *Service) preparePollNotificationsMulti(deviceIds []string, timestamp core.Timestamp, names, waitTimeout string) (task Task, err error) {
+	// create request
+	query := url.Values{}
+	query.Set("deviceGuids", strings.Join(deviceIds, ","))
+	if !timestamp.IsZero() {
+		query.Set("timestamp", timestamp.String())
+	}
+	if len(names) != 0 {
+		query.Set("names", names)
+	}
+	if len(waitTimeout) != 0 {
+		query.Set("waitTimeout", waitTimeout)
+	}
+	url := fmt.Sprintf("%s/device/notification/poll?%s", service.baseUrl, query.Encode())
+
+	task.request, err = http.NewRequest("GET", url, nil)
+	if err != nil {
+		service.logger.Warnf("REST: failed to create /notification/poll-multi request (error: %s)", err)
+		return
+	}
+
+	// authorization, access key only
+	service.prepareAuthorization(task.request, nil)
+
+	return
+}
+
+// Process PollNotificationsMulti task
+func (service *Service) processPollNotificationsMulti(task Task) (notifications []DeviceNotification, err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode != http.StatusOK {
+		service.logger.Warnf("REST: unexpected /notification/poll-multi status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	// unmarshal
+	err = json.Unmarshal(task.body, &notifications)
+	if err != nil {
+		service.logger.Warnf("REST: failed to parse /notification/poll-multi body (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// PollNotificationsMulti() function polls the notifications of several devices at once.
+// Device keys could not be used, access key authorization is required.
+func (service *Service) PollNotificationsMulti(deviceIds []string, timestamp core.Timestamp, names, waitTimeout string, timeout time.Duration) (notifications []DeviceNotification, err error) {
+	return service.pollNotificationsMulti(context.Background(), deviceIds, timestamp, names, waitTimeout, timeout)
+}
+
+// poll the notifications of several devices, cancellable
+func (service *Service) pollNotificationsMulti(ctx context.Context, deviceIds []string, timestamp core.Timestamp, names, waitTimeout string, timeout time.Duration) (notifications []DeviceNotification, err error) {
+	service.logger.Debugf("REST: polling notifications %q, timestamp:%q...", deviceIds, timestamp)
+
+	task, err := service.preparePollNotificationsMulti(deviceIds, timestamp, names, waitTimeout)
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /notification/poll-multi task (error: %s)", err)
+		return
+	}
+	task.request = task.request.WithContext(ctx)
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /notification/poll-multi task", timeout)
+		err = core.ErrTimeout
+
+	case <-ctx.Done():
+		err = ctx.Err()
+
+	case task = <-service.doAsync(task):
+		notifications, err = service.processPollNotificationsMulti(task)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /notification/poll-multi task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
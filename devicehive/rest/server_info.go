@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"net/http"
 	"time"
 )
@@ -15,7 +14,7 @@ func (service *Service) prepareGetServerInfo() (task Task, err error) {
 	url := fmt.Sprintf("%s/info", service.baseUrl)
 	task.request, err = http.NewRequest("GET", url, nil)
 	if err != nil {
-		log.Warnf("REST: failed to create /info request (error: %s)", err)
+		service.logger.Warnf("REST: failed to create /info request (error: %s)", err)
 		return
 	}
 
@@ -35,7 +34,7 @@ func (service *Service) processGetServerInfo(task Task, info *core.ServerInfo) (
 
 	// check status code
 	if task.response.StatusCode != http.StatusOK {
-		log.Warnf("REST: unexpected /info status %s",
+		service.logger.Warnf("REST: unexpected /info status %s",
 			task.response.Status)
 		err = fmt.Errorf("unexpected status: %s",
 			task.response.Status)
@@ -45,7 +44,7 @@ func (service *Service) processGetServerInfo(task Task, info *core.ServerInfo) (
 	// unmarshal
 	err = json.Unmarshal(task.body, info)
 	if err != nil {
-		log.Warnf("REST: failed to parse /info body (error: %s)", err)
+		service.logger.Warnf("REST: failed to parse /info body (error: %s)", err)
 		return
 	}
 
@@ -54,24 +53,24 @@ func (service *Service) processGetServerInfo(task Task, info *core.ServerInfo) (
 
 // GetServerInfo() function gets the main server's information.
 func (service *Service) GetServerInfo(timeout time.Duration) (info *core.ServerInfo, err error) {
-	log.Tracef("REST: getting server info...")
+	service.logger.Tracef("REST: getting server info...")
 
 	task, err := service.prepareGetServerInfo()
 	if err != nil {
-		log.Warnf("REST: failed to prepare /info task (error: %s)", err)
+		service.logger.Warnf("REST: failed to prepare /info task (error: %s)", err)
 		return
 	}
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("REST: failed to wait %s for /info task", timeout)
-		err = fmt.Errorf("timed out")
+		service.logger.Warnf("REST: failed to wait %s for /info task", timeout)
+		err = core.ErrTimeout
 
 	case task = <-service.doAsync(task):
 		info = &core.ServerInfo{}
 		err = service.processGetServerInfo(task, info)
 		if err != nil {
-			log.Warnf("REST: failed to process /info task (error: %s)", err)
+			service.logger.Warnf("REST: failed to process /info task (error: %s)", err)
 			return
 		}
 	}
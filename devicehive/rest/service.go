@@ -22,6 +22,9 @@ type Service struct {
 	// HTTP client is used to perform all requests
 	client *http.Client
 
+	// Logger with "transport" field
+	logger *log.Entry
+
 	// set of command/notification listeners
 	commandListeners map[string]*core.CommandListener
 	notificationListeners map[string]*core.NotificationListener
@@ -30,13 +33,14 @@ type Service struct {
 // Get string representation of a service.
 func (service *Service) String() string {
 	return fmt.Sprintf("RestService{baseUrl:%q, accessKey:%q}",
-		service.baseUrl, service.accessKey)
+		service.baseUrl, core.RedactSecret(service.accessKey))
 }
 
 // NewService creates new service.
 func NewService(baseUrl, accessKey string) (service *Service, err error) {
-	log.Tracef("REST: creating service (url:%q)", baseUrl)
-	service = &Service{accessKey: accessKey}
+	service = &Service{accessKey: accessKey,
+		logger: log.NewEntry(nil, log.REST, log.Transport("rest"))}
+	service.logger.Tracef("REST: creating service (url:%q)", baseUrl)
 
 	// remove trailing slashes from URL
 	for len(baseUrl) > 1 && strings.HasSuffix(baseUrl, "/") {
@@ -46,13 +50,12 @@ func NewService(baseUrl, accessKey string) (service *Service, err error) {
 	// parse URL
 	service.baseUrl, err = url.Parse(baseUrl)
 	if err != nil {
-		log.Warnf("REST: failed to parse URL (error: %s)", err)
+		service.logger.Warnf("REST: failed to parse URL (error: %s)", err)
 		return
 	}
 
 	// initialize HTTP client
 	service.client = &http.Client{}
-	// TODO: client.Transport
 	// TODO: client.CookieJar
 	// TODO: client.Timeout
 
@@ -61,6 +64,18 @@ func NewService(baseUrl, accessKey string) (service *Service, err error) {
 	return
 }
 
+// SetTransport changes the HTTP transport used to perform all requests.
+// nil means http.DefaultTransport.
+func (service *Service) SetTransport(transport http.RoundTripper) {
+	service.client.Transport = transport
+}
+
+// SetLogger changes the logging backend.
+// nil means log.StdLogger.
+func (service *Service) SetLogger(logger log.Logger) {
+	service.logger.SetLogger(logger)
+}
+
 // Adds Authorization header if access key is not empty
 func (service *Service) prepareAuthorization(request *http.Request, device *core.Device) {
 	// access key
@@ -75,6 +90,15 @@ func (service *Service) prepareAuthorization(request *http.Request, device *core
 	}
 }
 
+// copy headers with secrets redacted (for logging)
+// authorization scheme is kept
+func redactHeader(header http.Header) http.Header {
+	if core.IsRevealSecrets() {
+		return header
+	}
+	return core.RedactHeader(header, core.SecretHeaders)
+}
+
 // Asynchronous request/task
 type Task struct {
 	request  *http.Request
@@ -90,34 +114,49 @@ func (service *Service) doAsync(task Task) <-chan Task {
 	go func() {
 		defer func() { ch <- task }()
 
-		log.Tracef("REST: sending: %+v", task.request)
+		logger := service.logger.With(log.Action(task.request.Method + " " + task.request.URL.Path))
+		if deviceId := task.request.Header.Get("Auth-DeviceID"); len(deviceId) != 0 {
+			logger = logger.With(log.DeviceId(deviceId))
+		}
+		start := time.Now()
+
+		if logger.Enabled(log.TRACE) {
+			logger.Tracef("REST: sending %s %s request (headers: %v)",
+				task.request.Method, task.request.URL, redactHeader(task.request.Header))
+		}
 		task.response, task.err = service.client.Do(task.request)
 		if task.err != nil {
-			log.Warnf("REST: failed to do %s %s request (error: %s)",
+			logger.With(log.Duration(time.Since(start))).Warnf("REST: failed to do %s %s request (error: %s)",
 				task.request.Method, task.request.URL, task.err)
 			return
 		}
-		log.Tracef("REST: got %s %s response: %+v",
-			task.request.Method, task.request.URL, task.response)
+		logger = logger.With(log.Duration(time.Since(start)))
+		if logger.Enabled(log.TRACE) {
+			logger.Tracef("REST: got %s %s response: %s (headers: %v)",
+				task.request.Method, task.request.URL, task.response.Status,
+				redactHeader(task.response.Header))
+		}
 
 		// read body
 		defer task.response.Body.Close()
 		task.body, task.err = ioutil.ReadAll(task.response.Body)
 		if task.err != nil {
-			log.Warnf("REST: failed to read %s %s response body (error: %s)",
+			logger.Warnf("REST: failed to read %s %s response body (error: %s)",
 				task.request.Method, task.request.URL, task.err)
 			return
 		}
 
-		log.Debugf("REST: got %s %s body: %s",
-			task.request.Method, task.request.URL, string(task.body))
+		if logger.Enabled(log.DEBUG) {
+			logger.Debugf("REST: got %s %s body: %s",
+				task.request.Method, task.request.URL, core.RedactJSON(task.body))
+		}
 	}()
 
 	return ch
 }
 
 // subscribe for commands
-func (service *Service) SubscribeCommands(device *core.Device, timestamp string, timeout time.Duration) (listener *core.CommandListener, err error) {
+func (service *Service) SubscribeCommands(device *core.Device, timestamp core.Timestamp, timeout time.Duration) (listener *core.CommandListener, err error) {
 	if listener, ok := service.commandListeners[device.Id]; ok {
 		return listener, nil
 	}
@@ -127,23 +166,23 @@ func (service *Service) SubscribeCommands(device *core.Device, timestamp string,
 	service.commandListeners[device.Id] = listener
 
 	go func(deviceId string) {
-		log.Debugf("REST: start command polling %q", deviceId)
+		service.logger.Debugf("REST: start command polling %q", deviceId)
 		for {
 			names := ""
 			wait := "30"
 			cmds, err := service.PollCommands(device, timestamp, names, wait, 60*time.Second)
 			if err != nil {
-				log.Warnf("REST: failed to poll commands (error: %s)", err)
+				service.logger.Warnf("REST: failed to poll commands (error: %s)", err)
 				// TODO: break? wait and try again?
 			}
 			if listener, ok := service.commandListeners[deviceId]; ok {
 				for _, cmd := range cmds {
-					log.Debugf("REST: got command %s received", cmd)
+					service.logger.Debugf("REST: got command %s received", cmd)
 					timestamp = cmd.Timestamp
 					listener.C <- &cmd
 				}
 			} else {
-				log.Debugf("REST: stop command polling %q", deviceId)
+				service.logger.Debugf("REST: stop command polling %q", deviceId)
 				return // stop
 			}
 		}
@@ -159,7 +198,7 @@ func (service *Service) UnsubscribeCommands(device *core.Device, timeout time.Du
 }
 
 // subscribe for notifications
-func (service *Service) SubscribeNotifications(device *core.Device, timestamp string, timeout time.Duration) (listener *core.NotificationListener, err error) {
+func (service *Service) SubscribeNotifications(device *core.Device, timestamp core.Timestamp, timeout time.Duration) (listener *core.NotificationListener, err error) {
 	if listener, ok := service.notificationListeners[device.Id]; ok {
 		return listener, nil
 	}
@@ -169,23 +208,23 @@ func (service *Service) SubscribeNotifications(device *core.Device, timestamp st
 	service.notificationListeners[device.Id] = listener
 
 	go func(deviceId string) {
-		log.Debugf("REST: start notification polling %q", deviceId)
+		service.logger.Debugf("REST: start notification polling %q", deviceId)
 		for {
 			names := ""
 			wait := "30"
 			ntfs, err := service.PollNotifications(device, timestamp, names, wait, 60*time.Second)
 			if err != nil {
-				log.Warnf("REST: failed to poll notifications (error: %s)", err)
+				service.logger.Warnf("REST: failed to poll notifications (error: %s)", err)
 				// TODO: break? wait and try again?
 			}
 			if listener, ok := service.notificationListeners[deviceId]; ok {
 				for _, ntf := range ntfs {
-					log.Debugf("REST: got notification %s received", ntf)
+					service.logger.Debugf("REST: got notification %s received", ntf)
 					timestamp = ntf.Timestamp
 					listener.C <- &ntf
 				}
 			} else {
-				log.Debugf("REST: stop notification polling %q", deviceId)
+				service.logger.Debugf("REST: stop notification polling %q", deviceId)
 				return // stop
 			}
 		}
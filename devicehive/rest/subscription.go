@@ -0,0 +1,458 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Long-poll wait timeout used by multi-device subscriptions, seconds.
+const MultiPollWaitTimeout = 30
+
+// delay before the next poll attempt after a failure
+var multiPollRetryDelay = 1 * time.Second
+
+// maximum number of undelivered items per device, the oldest are dropped
+const multiPollQueueSize = 1024
+
+// item received by multi-device poll
+type polledItem[T any] struct {
+	deviceId  string
+	item      *T
+	timestamp core.Timestamp
+}
+
+// per-device delivery queue
+// items are delivered by a dedicated goroutine, so a slow consumer
+// doesn't block other devices. The channel is closed when stopped.
+type deviceQueue[T any] struct {
+	ch    chan *T
+	lock  sync.Mutex
+	items []*T
+	ready chan struct{} // items are added
+	stop  chan struct{} // device is removed or poller is closed
+}
+
+// create and start new delivery queue
+func newDeviceQueue[T any](ch chan *T) *deviceQueue[T] {
+	q := &deviceQueue[T]{ch: ch,
+		ready: make(chan struct{}, 1),
+		stop:  make(chan struct{})}
+	go q.run()
+	return q
+}
+
+// add item to the queue
+// returns false if the oldest item is dropped
+func (q *deviceQueue[T]) push(item *T) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	dropped := len(q.items) >= multiPollQueueSize
+	if dropped {
+		q.items = q.items[1:]
+	}
+	q.items = append(q.items, item)
+	select {
+	case q.ready <- struct{}{}:
+	default: // already signalled
+	}
+	return !dropped
+}
+
+// delivery loop
+func (q *deviceQueue[T]) run() {
+	defer close(q.ch)
+
+	for {
+		q.lock.Lock()
+		var item *T
+		if len(q.items) != 0 {
+			item = q.items[0]
+		}
+		q.lock.Unlock()
+
+		// nothing to deliver, wait for items
+		if item == nil {
+			select {
+			case <-q.ready:
+				continue
+			case <-q.stop:
+				return
+			}
+		}
+
+		select {
+		case q.ch <- item:
+			q.lock.Lock()
+			q.items[0] = nil // release the item
+			q.items = q.items[1:]
+			q.lock.Unlock()
+		case <-q.stop:
+			return
+		}
+	}
+}
+
+// polls a set of devices, cancelled when the set is changed
+type multiPollFunc[T any] func(ctx context.Context, deviceIds []string, timestamp core.Timestamp) ([]polledItem[T], error)
+
+// single long-poll loop for a set of devices
+// results are demultiplexed to per-device channels
+type multiPoller[T any] struct {
+	service *Service
+	kind    string // "command" or "notification", for logging
+	poll    multiPollFunc[T]
+
+	lock      sync.Mutex
+	queues    map[string]*deviceQueue[T]
+	timestamp core.Timestamp     // last received item, server time initially
+	cancel    context.CancelFunc // cancels the current poll
+	wake      chan struct{}      // device set is changed
+	done      chan struct{}      // closed by close()
+	closed    bool
+}
+
+// create and start new poll loop
+func newMultiPoller[T any](service *Service, kind string, timestamp core.Timestamp, poll multiPollFunc[T]) *multiPoller[T] {
+	p := &multiPoller[T]{service: service, kind: kind, poll: poll,
+		queues:    make(map[string]*deviceQueue[T]),
+		timestamp: timestamp,
+		wake:      make(chan struct{}, 1),
+		done:      make(chan struct{})}
+	go p.run()
+	return p
+}
+
+// restart the current poll with the new device set
+// should be called under lock
+func (p *multiPoller[T]) restart() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	select {
+	case p.wake <- struct{}{}:
+	default: // already signalled
+	}
+}
+
+// add device channel, existing channel is returned if device is already added
+// channel is closed immediately if poller is closed
+func (p *multiPoller[T]) add(deviceId string, ch chan *T) chan *T {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if old, ok := p.queues[deviceId]; ok {
+		return old.ch
+	}
+	q := newDeviceQueue(ch)
+	p.queues[deviceId] = q
+	if p.closed {
+		close(q.stop)
+	} else {
+		p.restart()
+	}
+	return ch
+}
+
+// remove device channel, the channel is closed
+func (p *multiPoller[T]) remove(deviceId string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if q, ok := p.queues[deviceId]; ok {
+		delete(p.queues, deviceId)
+		if !p.closed {
+			close(q.stop)
+			p.restart()
+		}
+	}
+}
+
+// get device channel, nil if device is not added
+func (p *multiPoller[T]) get(deviceId string) chan *T {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if q, ok := p.queues[deviceId]; ok {
+		return q.ch
+	}
+	return nil
+}
+
+// get sorted device identifiers
+func (p *multiPoller[T]) devices() []string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.deviceIds()
+}
+
+// get sorted device identifiers, should be called under lock
+func (p *multiPoller[T]) deviceIds() []string {
+	ids := make([]string, 0, len(p.queues))
+	for id := range p.queues {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// stop the poll loop, all device channels are closed
+func (p *multiPoller[T]) close() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if !p.closed {
+		p.closed = true
+		if p.cancel != nil {
+			p.cancel()
+		}
+		close(p.done)
+		for _, q := range p.queues {
+			close(q.stop)
+		}
+	}
+}
+
+// poll loop
+func (p *multiPoller[T]) run() {
+	logger := p.service.logger
+	logger.Debugf("REST: start multi-device %s polling", p.kind)
+	defer logger.Debugf("REST: stop multi-device %s polling", p.kind)
+
+	for {
+		p.lock.Lock()
+		if p.closed {
+			p.lock.Unlock()
+			return
+		}
+		ids := p.deviceIds()
+		timestamp := p.timestamp
+		ctx, cancel := context.WithCancel(context.Background())
+		p.cancel = cancel
+		p.lock.Unlock()
+
+		// zero timestamp means "now", it's replaced with the server time
+		// so the items created between polls are not missed
+		if timestamp.IsZero() {
+			cancel()
+			if err := p.seed(); err != nil {
+				logger.Warnf("REST: failed to get server time for %s polling (error: %s)", p.kind, err)
+				select {
+				case <-time.After(multiPollRetryDelay):
+				case <-p.done:
+					return
+				}
+			}
+			continue
+		}
+
+		// nothing to poll, wait for devices
+		if len(ids) == 0 {
+			cancel()
+			select {
+			case <-p.wake:
+				continue
+			case <-p.done:
+				return
+			}
+		}
+
+		items, err := p.poll(ctx, ids, timestamp)
+		cancelled := ctx.Err() != nil
+		cancel()
+		if err != nil {
+			if cancelled {
+				continue // device set is changed or closed
+			}
+			logger.Warnf("REST: failed to poll %ss of %d devices (error: %s)", p.kind, len(ids), err)
+			select {
+			case <-time.After(multiPollRetryDelay):
+			case <-p.wake:
+			case <-p.done:
+				return
+			}
+			continue
+		}
+
+		p.dispatch(items)
+	}
+}
+
+// initialize the timestamp with the current server time
+func (p *multiPoller[T]) seed() error {
+	info, err := p.service.GetServerInfo(MultiPollWaitTimeout * time.Second)
+	if err != nil {
+		return err
+	}
+	if info.Timestamp.IsZero() {
+		return fmt.Errorf("no server timestamp")
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.timestamp.IsZero() {
+		p.timestamp = info.Timestamp
+	}
+	return nil
+}
+
+// put polled items to device queues
+func (p *multiPoller[T]) dispatch(items []polledItem[T]) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, it := range items {
+		if it.timestamp.After(p.timestamp.Time) {
+			p.timestamp = it.timestamp
+		}
+
+		q := p.queues[it.deviceId]
+		if q == nil || p.closed {
+			p.service.logger.Debugf("REST: drop %s of removed device %q", p.kind, it.deviceId)
+			continue
+		}
+		if !q.push(it.item) {
+			p.service.logger.Warnf("REST: %s queue of device %q is full, the oldest is dropped", p.kind, it.deviceId)
+		}
+	}
+}
+
+// CommandSubscription delivers commands of a set of devices received
+// by a single long-poll request. Devices could be added or removed
+// at any time, the current poll is restarted with the new set.
+// Each device has its own delivery queue, so a slow listener
+// doesn't delay commands of other devices.
+// Access key authorization is required, device keys are not used.
+type CommandSubscription struct {
+	poller *multiPoller[core.Command]
+}
+
+// SubscribeCommandsMulti() creates a multi-device command subscription.
+// Commands created after the timestamp are received
+// (zero means "now", the server time is requested before the first poll).
+// Names is an optional comma-separated list of command names.
+// Use Add() to get per-device listeners.
+func (service *Service) SubscribeCommandsMulti(devices []*core.Device, timestamp core.Timestamp, names string) *CommandSubscription {
+	wait := strconv.Itoa(MultiPollWaitTimeout)
+	timeout := 2 * MultiPollWaitTimeout * time.Second
+	poll := func(ctx context.Context, deviceIds []string, timestamp core.Timestamp) ([]polledItem[core.Command], error) {
+		commands, err := service.pollCommandsMulti(ctx, deviceIds, timestamp, names, wait, timeout)
+		items := make([]polledItem[core.Command], 0, len(commands))
+		for i := range commands {
+			cmd := &commands[i].Command
+			items = append(items, polledItem[core.Command]{
+				deviceId: commands[i].DeviceId, item: cmd, timestamp: cmd.Timestamp})
+		}
+		return items, err
+	}
+
+	sub := &CommandSubscription{poller: newMultiPoller(service, "command", timestamp, poll)}
+	for _, device := range devices {
+		sub.Add(device)
+	}
+	return sub
+}
+
+// Add() adds the device to the subscription.
+// Listener of already added device shares the same channel.
+func (sub *CommandSubscription) Add(device *core.Device) *core.CommandListener {
+	listener := core.NewCommandListener()
+	return &core.CommandListener{C: sub.poller.add(device.Id, listener.C)}
+}
+
+// Remove() removes the device from the subscription.
+// Device listener channel is closed, undelivered commands are dropped.
+func (sub *CommandSubscription) Remove(device *core.Device) {
+	sub.poller.remove(device.Id)
+}
+
+// Listener() gets the device listener, nil if device is not added.
+func (sub *CommandSubscription) Listener(deviceId string) *core.CommandListener {
+	if ch := sub.poller.get(deviceId); ch != nil {
+		return &core.CommandListener{C: ch}
+	}
+	return nil
+}
+
+// Devices() gets the sorted list of subscribed device identifiers.
+func (sub *CommandSubscription) Devices() []string {
+	return sub.poller.devices()
+}
+
+// Close() stops polling. All listener channels are closed,
+// undelivered commands are dropped.
+func (sub *CommandSubscription) Close() {
+	sub.poller.close()
+}
+
+// NotificationSubscription delivers notifications of a set of devices
+// received by a single long-poll request. Devices could be added or removed
+// at any time, the current poll is restarted with the new set.
+// Each device has its own delivery queue, so a slow listener
+// doesn't delay notifications of other devices.
+// Access key authorization is required, device keys are not used.
+type NotificationSubscription struct {
+	poller *multiPoller[core.Notification]
+}
+
+// SubscribeNotificationsMulti() creates a multi-device notification subscription.
+// Notifications created after the timestamp are received
+// (zero means "now", the server time is requested before the first poll).
+// Names is an optional comma-separated list of notification names.
+// Use Add() to get per-device listeners.
+func (service *Service) SubscribeNotificationsMulti(devices []*core.Device, timestamp core.Timestamp, names string) *NotificationSubscription {
+	wait := strconv.Itoa(MultiPollWaitTimeout)
+	timeout := 2 * MultiPollWaitTimeout * time.Second
+	poll := func(ctx context.Context, deviceIds []string, timestamp core.Timestamp) ([]polledItem[core.Notification], error) {
+		notifications, err := service.pollNotificationsMulti(ctx, deviceIds, timestamp, names, wait, timeout)
+		items := make([]polledItem[core.Notification], 0, len(notifications))
+		for i := range notifications {
+			ntf := &notifications[i].Notification
+			items = append(items, polledItem[core.Notification]{
+				deviceId: notifications[i].DeviceId, item: ntf, timestamp: ntf.Timestamp})
+		}
+		return items, err
+	}
+
+	sub := &NotificationSubscription{poller: newMultiPoller(service, "notification", timestamp, poll)}
+	for _, device := range devices {
+		sub.Add(device)
+	}
+	return sub
+}
+
+// Add() adds the device to the subscription.
+// Listener of already added device shares the same channel.
+func (sub *NotificationSubscription) Add(device *core.Device) *core.NotificationListener {
+	listener := core.NewNotificationListener()
+	return &core.NotificationListener{C: sub.poller.add(device.Id, listener.C)}
+}
+
+// Remove() removes the device from the subscription.
+// Device listener channel is closed, undelivered notifications are dropped.
+func (sub *NotificationSubscription) Remove(device *core.Device) {
+	sub.poller.remove(device.Id)
+}
+
+// Listener() gets the device listener, nil if device is not added.
+func (sub *NotificationSubscription) Listener(deviceId string) *core.NotificationListener {
+	if ch := sub.poller.get(deviceId); ch != nil {
+		return &core.NotificationListener{C: ch}
+	}
+	return nil
+}
+
+// Devices() gets the sorted list of subscribed device identifiers.
+func (sub *NotificationSubscription) Devices() []string {
+	return sub.poller.devices()
+}
+
+// Close() stops polling. All listener channels are closed,
+// undelivered notifications are dropped.
+func (sub *NotificationSubscription) Close() {
+	sub.poller.close()
+}
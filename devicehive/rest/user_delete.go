@@ -0,0 +1,71 @@
+package rest
+
+import (
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"time"
+)
+
+// Prepare DeleteUser task
+func (service *Service) prepareDeleteUser(user *core.User) (task Task, err error) {
+	// create request
+	url := fmt.Sprintf("%s/user/%d", service.baseUrl, user.Id)
+	task.request, err = http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		service.logger.Warnf("REST: failed to create /user/delete request (error: %s)", err)
+		return
+	}
+
+	// authorization
+	service.prepareAuthorization(task.request, nil)
+
+	return
+}
+
+// Process DeleteUser task
+func (service *Service) processDeleteUser(task Task) (err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode < http.StatusOK ||
+		task.response.StatusCode > http.StatusPartialContent {
+		service.logger.Warnf("REST: unexpected /user/delete status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	return
+}
+
+// DeleteUser() function deletes the user.
+func (service *Service) DeleteUser(user *core.User, timeout time.Duration) (err error) {
+	service.logger.Tracef("REST: deleting user %d...", user.Id)
+
+	task, err := service.prepareDeleteUser(user)
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /user/delete task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /user/delete task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		err = service.processDeleteUser(task)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /user/delete task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
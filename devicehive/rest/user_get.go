@@ -0,0 +1,92 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"time"
+)
+
+// CurrentUser identifier refers to the user the service is authorized as.
+const CurrentUser uint64 = 0
+
+// get user URL, CurrentUser gives "/user/current"
+func (service *Service) userUrl(userId uint64) string {
+	if userId == CurrentUser {
+		return fmt.Sprintf("%s/user/current", service.baseUrl)
+	}
+	return fmt.Sprintf("%s/user/%d", service.baseUrl, userId)
+}
+
+// Prepare GetUser task
+func (service *Service) prepareGetUser(userId uint64) (task Task, err error) {
+	// create request
+	url := service.userUrl(userId)
+
+	task.request, err = http.NewRequest("GET", url, nil)
+	if err != nil {
+		service.logger.Warnf("REST: failed to create /user/get request (error: %s)", err)
+		return
+	}
+
+	// authorization
+	service.prepareAuthorization(task.request, nil)
+
+	return
+}
+
+// Process GetUser task
+func (service *Service) processGetUser(task Task, user *core.User) (err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode != http.StatusOK {
+		service.logger.Warnf("REST: unexpected /user/get status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	// unmarshal
+	err = json.Unmarshal(task.body, user)
+	if err != nil {
+		service.logger.Warnf("REST: failed to parse /user/get body (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// GetUser() function gets the user data.
+// Use CurrentUser to get the user the service is authorized as.
+func (service *Service) GetUser(userId uint64, timeout time.Duration) (user *core.User, err error) {
+	service.logger.Tracef("REST: getting user %d...", userId)
+
+	task, err := service.prepareGetUser(userId)
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /user/get task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /user/get task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		user = &core.User{Id: userId}
+		err = service.processGetUser(task, user)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /user/get task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
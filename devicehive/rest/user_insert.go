@@ -0,0 +1,98 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"time"
+)
+
+// Prepare InsertUser task
+func (service *Service) prepareInsertUser(user *core.User) (task Task, err error) {
+	// create request
+	url := fmt.Sprintf("%s/user", service.baseUrl)
+
+	user_data := *user
+	user_data.Id = 0                       // do not put ID to the request body
+	user_data.LastLogin = core.Timestamp{} // read only
+	body, err := json.Marshal(&user_data)
+	if err != nil {
+		service.logger.Warnf("REST: failed to format /user/insert request (error: %s)", err)
+		return
+	}
+
+	task.request, err = http.NewRequest("POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		service.logger.Warnf("REST: failed to create /user/insert request (error: %s)", err)
+		return
+	}
+	task.request.Header.Add("Content-Type", "application/json")
+
+	// authorization
+	service.prepareAuthorization(task.request, nil)
+
+	return
+}
+
+// Process InsertUser task
+func (service *Service) processInsertUser(task Task, user *core.User) (err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode < http.StatusOK ||
+		task.response.StatusCode > http.StatusPartialContent {
+		service.logger.Warnf("REST: unexpected /user/insert status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	// unmarshal
+	err = json.Unmarshal(task.body, user)
+	if err != nil {
+		service.logger.Warnf("REST: failed to parse /user/insert body (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// InsertUser() function inserts the user.
+func (service *Service) InsertUser(user *core.User, timeout time.Duration) (err error) {
+	service.logger.Tracef("REST: inserting user %q...", user.Login)
+
+	// client-side validation
+	err = core.Validate(user)
+	if err != nil {
+		service.logger.Warnf("REST: invalid user (error: %s)", err)
+		return
+	}
+
+	task, err := service.prepareInsertUser(user)
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /user/insert task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /user/insert task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		err = service.processInsertUser(task, user)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /user/insert task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,88 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"time"
+)
+
+// Prepare GetUserList task
+func (service *Service) prepareGetUserList(filter *core.UserFilter) (task Task, err error) {
+	// create request
+	query := filter.Query()
+	url := fmt.Sprintf("%s/user", service.baseUrl)
+	if len(query) != 0 {
+		url += "?" + query.Encode()
+	}
+
+	task.request, err = http.NewRequest("GET", url, nil)
+	if err != nil {
+		service.logger.Warnf("REST: failed to create /user/list request (error: %s)", err)
+		return
+	}
+
+	// authorization
+	service.prepareAuthorization(task.request, nil)
+
+	return
+}
+
+// Process GetUserList task
+func (service *Service) processGetUserList(task Task) (users []core.User, err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode != http.StatusOK {
+		service.logger.Warnf("REST: unexpected /user/list status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	// unmarshal
+	err = json.Unmarshal(task.body, &users)
+	if err != nil {
+		service.logger.Warnf("REST: failed to parse /user/list body (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// GetUserList() function gets the user list.
+func (service *Service) GetUserList(take, skip int, timeout time.Duration) (users []core.User, err error) {
+	return service.GetUserListFilter(&core.UserFilter{Take: take, Skip: skip}, timeout)
+}
+
+// GetUserListFilter() function gets the user list using filter.
+func (service *Service) GetUserListFilter(filter *core.UserFilter, timeout time.Duration) (users []core.User, err error) {
+	service.logger.Tracef("REST: getting user list (filter:%+v)...", filter)
+
+	task, err := service.prepareGetUserList(filter)
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /user/list task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /user/list task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		users, err = service.processGetUserList(task)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /user/list task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,158 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"time"
+)
+
+// Prepare user-network task
+func (service *Service) prepareUserNetwork(method string, userId, networkId uint64, name string) (task Task, err error) {
+	// create request
+	url := fmt.Sprintf("%s/network/%d", service.userUrl(userId), networkId)
+
+	task.request, err = http.NewRequest(method, url, nil)
+	if err != nil {
+		service.logger.Warnf("REST: failed to create %s request (error: %s)", name, err)
+		return
+	}
+
+	// authorization
+	service.prepareAuthorization(task.request, nil)
+
+	return
+}
+
+// Process GetUserNetwork task
+func (service *Service) processGetUserNetwork(task Task, network *core.Network) (err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode != http.StatusOK {
+		service.logger.Warnf("REST: unexpected /user/network/get status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	// unmarshal, user-network object {"network":{...}}
+	var res struct {
+		Network *core.Network `json:"network"`
+	}
+	res.Network = network
+	err = json.Unmarshal(task.body, &res)
+	if err != nil {
+		service.logger.Warnf("REST: failed to parse /user/network/get body (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// Process AssignNetwork or UnassignNetwork task
+func (service *Service) processUserNetwork(task Task, name string) (err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode < http.StatusOK ||
+		task.response.StatusCode > http.StatusPartialContent {
+		service.logger.Warnf("REST: unexpected %s status %s",
+			name, task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	return
+}
+
+// GetUserNetwork() function gets the network assigned to the user.
+// Returns an error if the network is not assigned.
+func (service *Service) GetUserNetwork(userId, networkId uint64, timeout time.Duration) (network *core.Network, err error) {
+	service.logger.Tracef("REST: getting user %d network %d...", userId, networkId)
+
+	task, err := service.prepareUserNetwork("GET", userId, networkId, "/user/network/get")
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /user/network/get task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /user/network/get task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		network = &core.Network{Id: networkId}
+		err = service.processGetUserNetwork(task, network)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /user/network/get task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
+
+// AssignNetwork() function gives the user access to the network.
+func (service *Service) AssignNetwork(userId, networkId uint64, timeout time.Duration) (err error) {
+	service.logger.Tracef("REST: assigning network %d to user %d...", networkId, userId)
+
+	task, err := service.prepareUserNetwork("PUT", userId, networkId, "/user/network/assign")
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /user/network/assign task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /user/network/assign task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		err = service.processUserNetwork(task, "/user/network/assign")
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /user/network/assign task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
+
+// UnassignNetwork() function revokes the user access to the network.
+func (service *Service) UnassignNetwork(userId, networkId uint64, timeout time.Duration) (err error) {
+	service.logger.Tracef("REST: unassigning network %d from user %d...", networkId, userId)
+
+	task, err := service.prepareUserNetwork("DELETE", userId, networkId, "/user/network/unassign")
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /user/network/unassign task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /user/network/unassign task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		err = service.processUserNetwork(task, "/user/network/unassign")
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /user/network/unassign task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,93 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"net/http"
+	"time"
+)
+
+// Prepare UpdateUser task
+func (service *Service) prepareUpdateUser(user *core.User) (task Task, err error) {
+	// create request
+	url := service.userUrl(user.Id)
+
+	user_data := *user
+	user_data.Id = 0                       // do not put ID to the request body
+	user_data.LastLogin = core.Timestamp{} // read only
+	body, err := json.Marshal(&user_data)
+	if err != nil {
+		service.logger.Warnf("REST: failed to format /user/update request (error: %s)", err)
+		return
+	}
+
+	task.request, err = http.NewRequest("PUT", url, bytes.NewBuffer(body))
+	if err != nil {
+		service.logger.Warnf("REST: failed to create /user/update request (error: %s)", err)
+		return
+	}
+	task.request.Header.Add("Content-Type", "application/json")
+
+	// authorization
+	service.prepareAuthorization(task.request, nil)
+
+	return
+}
+
+// Process UpdateUser task
+func (service *Service) processUpdateUser(task Task) (err error) {
+	// check task error first
+	if task.err != nil {
+		err = task.err
+		return
+	}
+
+	// check status code
+	if task.response.StatusCode < http.StatusOK ||
+		task.response.StatusCode > http.StatusPartialContent {
+		service.logger.Warnf("REST: unexpected /user/update status %s",
+			task.response.Status)
+		err = fmt.Errorf("unexpected status: %s",
+			task.response.Status)
+		return
+	}
+
+	return
+}
+
+// UpdateUser() function updates the user.
+// Empty password is not changed. Use CurrentUser identifier
+// to update the user the service is authorized as.
+func (service *Service) UpdateUser(user *core.User, timeout time.Duration) (err error) {
+	service.logger.Tracef("REST: updating user %d...", user.Id)
+
+	// client-side validation
+	err = core.Validate(user)
+	if err != nil {
+		service.logger.Warnf("REST: invalid user (error: %s)", err)
+		return
+	}
+
+	task, err := service.prepareUpdateUser(user)
+	if err != nil {
+		service.logger.Warnf("REST: failed to prepare /user/update task (error: %s)", err)
+		return
+	}
+
+	select {
+	case <-time.After(timeout):
+		service.logger.Warnf("REST: failed to wait %s for /user/update task", timeout)
+		err = core.ErrTimeout
+
+	case task = <-service.doAsync(task):
+		err = service.processUpdateUser(task)
+		if err != nil {
+			service.logger.Warnf("REST: failed to process /user/update task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
@@ -15,22 +15,80 @@ const (
 	DateTimeLayout = core.DateTimeLayout
 )
 
+// Timestamp in DeviceHive format, UTC.
+type Timestamp = core.Timestamp
+
 // Abstract DeviceHive /device API.
 type Service interface {
 	GetServerInfo(timeout time.Duration) (info *core.ServerInfo, err error)
 
 	RegisterDevice(device *core.Device, timeout time.Duration) (err error)
 	GetDevice(deviceId, deviceKey string, timeout time.Duration) (device *core.Device, err error)
+	UpdateDevice(device *core.Device, update *core.Device, timeout time.Duration) (err error)
+	DeleteDevice(device *core.Device, timeout time.Duration) (err error)
 
 	GetCommand(device *core.Device, commandId uint64, timeout time.Duration) (command *core.Command, err error)
 	UpdateCommand(device *core.Device, command *core.Command, timeout time.Duration) (err error)
-	SubscribeCommands(device *core.Device, timestamp string, timeout time.Duration) (listener *core.CommandListener, err error)
+	SubscribeCommands(device *core.Device, timestamp core.Timestamp, timeout time.Duration) (listener *core.CommandListener, err error)
 	UnsubscribeCommands(device *core.Device, timeout time.Duration) (err error)
 
 	GetNotification(device *core.Device, notificationId uint64, timeout time.Duration) (notification *core.Notification, err error)
 	InsertNotification(device *core.Device, notification *core.Notification, timeout time.Duration) (err error)
+
+	NetworkService
+}
+
+// The following interfaces are implemented by both REST and Websocket
+// services, but not required from other Service implementations.
+// Use type assertion to check if a service supports them.
+
+// Abstract DeviceHive command sending API.
+type CommandService interface {
+	InsertCommand(device *core.Device, command *core.Command, timeout time.Duration) (err error)
+	SendCommandAndWait(device *core.Device, command *core.Command, timeout time.Duration, update func(command *core.Command) bool) (err error)
+}
+
+// Service with pluggable logging backend.
+type LoggerService interface {
+	SetLogger(logger log.Logger)
+}
+
+// Abstract DeviceHive network management API.
+// REST transport supports it on any service. Websocket transport
+// supports network/* actions on /client endpoint only, see NewClientService().
+// Websocket /device service fails with ws.ErrNotSupported.
+type NetworkService interface {
+	GetNetwork(networkId uint64, timeout time.Duration) (network *core.Network, err error)
+	GetNetworkList(take, skip int, timeout time.Duration) (networks []core.Network, err error)
+	GetNetworkListFilter(filter *core.NetworkFilter, timeout time.Duration) (networks []core.Network, err error)
+	InsertNetwork(network *core.Network, timeout time.Duration) (err error)
+	UpdateNetwork(network *core.Network, timeout time.Duration) (err error)
+	DeleteNetwork(network *core.Network, timeout time.Duration) (err error)
 }
 
+// Abstract DeviceHive command and notification history API.
+// Websocket transport uses command/list and notification/list actions,
+// so the server should support them on the device connection.
+type HistoryService interface {
+	GetCommandList(device *core.Device, filter *core.CommandFilter, timeout time.Duration) (commands []core.Command, err error)
+	GetNotificationList(device *core.Device, filter *core.NotificationFilter, timeout time.Duration) (notifications []core.Notification, err error)
+	IterateCommands(device *core.Device, filter *core.CommandFilter, pageSize int, timeout time.Duration) *core.CommandIterator
+	IterateNotifications(device *core.Device, filter *core.NotificationFilter, pageSize int, timeout time.Duration) *core.NotificationIterator
+}
+
+// both transports implement all the optional interfaces
+type fullService interface {
+	Service
+	CommandService
+	LoggerService
+	HistoryService
+}
+
+var (
+	_ fullService = (*rest.Service)(nil)
+	_ fullService = (*ws.Service)(nil)
+)
+
 // NewRestService creates a new REST service.
 // Base REST URL should be provided.
 // Access key is optional, might be empty.
@@ -45,14 +103,22 @@ func NewWebsocketService(baseUrl, accessKey string) (service Service, err error)
 	return ws.NewService(baseUrl, accessKey)
 }
 
+// NewWebsocketClientService creates a new Websocket /client service.
+// Base Websocket URL should be provided.
+// Access key is optional, might be empty.
+func NewWebsocketClientService(baseUrl, accessKey string) (service Service, err error) {
+	return ws.NewClientService(baseUrl, accessKey)
+}
+
 // NewService creates a new service (either REST or Websocket).
 // Base URL should be provided.
-// If protocol is "ws://" or "wss://" Websocket service will be created,
+// If protocol is "ws://" or "wss://" Websocket /device service will be created,
 // otherwise REST service will be used as a fallback.
 // Access key is optional, might be empty.
+// Websocket /device service doesn't support network management,
+// use NewClientService() for that.
 func NewService(baseUrl, accessKey string) (service Service, err error) {
-	url := strings.ToLower(baseUrl)
-	if strings.HasPrefix(url, `ws://`) || strings.HasPrefix(url, `wss://`) {
+	if isWebsocketUrl(baseUrl) {
 		return NewWebsocketService(baseUrl, accessKey)
 	}
 
@@ -60,6 +126,24 @@ func NewService(baseUrl, accessKey string) (service Service, err error) {
 	return NewRestService(baseUrl, accessKey)
 }
 
+// NewClientService creates a new client service (either REST or Websocket).
+// It's the same as NewService() but Websocket service is connected
+// to /client endpoint, which supports network management.
+func NewClientService(baseUrl, accessKey string) (service Service, err error) {
+	if isWebsocketUrl(baseUrl) {
+		return NewWebsocketClientService(baseUrl, accessKey)
+	}
+
+	// use REST service as a fallback
+	return NewRestService(baseUrl, accessKey)
+}
+
+// check if URL protocol is "ws://" or "wss://"
+func isWebsocketUrl(baseUrl string) bool {
+	url := strings.ToLower(baseUrl)
+	return strings.HasPrefix(url, `ws://`) || strings.HasPrefix(url, `wss://`)
+}
+
 // NewDevice creates a new device without network.
 // No user data by default.
 func NewDevice(id, name string, class *core.DeviceClass) *core.Device {
@@ -111,3 +195,16 @@ func NewNotification(name string, parameters interface{}) *core.Notification {
 func SetLogLevel(level string) {
 	log.SetLevelByName(level)
 }
+
+// SetComponentLogLevel changes the logging level of the component:
+// "core" "rest" "ws". It might be changed at any time.
+func SetComponentLogLevel(component, level string) {
+	log.SetComponentLevelByName(component, level)
+}
+
+// SetRevealSecrets enables or disables revealing of secrets
+// (access keys, device and network keys) in String() and log output.
+// Secrets are redacted by default, reveal them for local debugging only.
+func SetRevealSecrets(enable bool) {
+	core.SetRevealSecrets(enable)
+}
@@ -13,6 +13,7 @@ import (
 )
 
 var (
+	testLive               = false
 	testRestServerUrl      = "http://playground.devicehive.com/api/rest"
 	testWsServerUrl        = "ws://playground.devicehive.com/api/websocket"
 	testAccessKey          = ""
@@ -33,6 +34,7 @@ var (
 
 // initialize test environment
 func init() {
+	flag.BoolVar(&testLive, "live", testLive, "run tests against the live server (see -rest-url and -ws-url)")
 	flag.StringVar(&testRestServerUrl, "rest-url", testRestServerUrl, "REST service URL")
 	flag.StringVar(&testWsServerUrl, "ws-url", testWsServerUrl, "Websocket service URL")
 	flag.StringVar(&testAccessKey, "access-key", testAccessKey, "key to access playground")
@@ -52,11 +54,19 @@ func init() {
 	flag.IntVar(&testBatchLen, "batch-len", testBatchLen, "batch length")
 
 	flag.StringVar(&testLogLevel, "log-level", testLogLevel, "Logging level: WARN INFO DEBUG TRACE or NOLOG")
+	testing.Init() // register "-test.*" flags before parsing
 	flag.Parse()
 
 	log.SetLevelByName(testLogLevel)
 }
 
+// skip the test unless the live server is enabled
+func testRequireLive(t *testing.T) {
+	if !testLive {
+		t.Skip("live server is disabled, use -live flag")
+	}
+}
+
 // creates new REST service
 func testNewRest(t *testing.T) (service *rest.Service) {
 	if len(testRestServerUrl) == 0 {
@@ -133,7 +143,7 @@ func testCheckGetServerInfo(t *testing.T, service Service) {
 		t.Error("No API version")
 	}
 
-	if len(info.Timestamp) == 0 {
+	if info.Timestamp.IsZero() {
 		t.Error("No server timestamp")
 	}
 
@@ -142,12 +152,14 @@ func testCheckGetServerInfo(t *testing.T, service Service) {
 
 // Test GetServerInfo method
 func TestGetServerInfo(t *testing.T) {
+	testRequireLive(t)
 	testCheckGetServerInfo(t, testNewRestService(t))
 	testCheckGetServerInfo(t, testNewWsService(t))
 }
 
 // Test GetServerInfo method (invalid server address)
 func TestGetServerInfoBadAddress(t *testing.T) {
+	testRequireLive(t)
 	// REST
 	if len(testRestServerUrl) != 0 {
 		rs, err := rest.NewService(strings.Replace(testRestServerUrl, ".", "_", -1), "")
@@ -174,6 +186,7 @@ func TestGetServerInfoBadAddress(t *testing.T) {
 
 // Test GetServerInfo method (invalid path)
 func TestGetServerInfoBadPath(t *testing.T) {
+	testRequireLive(t)
 	// REST
 	if len(testRestServerUrl) != 0 {
 		rs, err := NewService(strings.Replace(testRestServerUrl, "rest", "reZZZt", -1), "")
@@ -269,6 +282,7 @@ func _TestRegisterDeviceNoNet(t *testing.T) {
 
 // Test RegisterDevice method
 func TestRegisterDevice1(t *testing.T) {
+	testRequireLive(t)
 	device := testNewDevice()
 	device.Network = testNewNetwork()
 
@@ -376,6 +390,7 @@ func (s TimeStat) String() string {
 }
 
 func TestBatchCommandInsert(t *testing.T) {
+	testRequireLive(t)
 	device := testNewDevice()
 	device.Network = testNewNetwork()
 
@@ -421,7 +436,7 @@ func TestBatchCommandInsert(t *testing.T) {
 	}()
 
 	// receiver
-	listener, err := s2.SubscribeCommands(device, "", testWaitTimeout)
+	listener, err := s2.SubscribeCommands(device, core.Timestamp{}, testWaitTimeout)
 	if err != nil {
 		t.Errorf("failed to subscribe commands: %s", err)
 		return
@@ -510,6 +525,7 @@ func testCheckInsertNotification(t *testing.T, service Service, device *core.Dev
 
 // Test InsertNotification method
 func TestInsertNotification(t *testing.T) {
+	testRequireLive(t)
 	// create device (REST)
 	device := testNewDevice()
 	device.Network = testNewNetwork()
@@ -524,6 +540,7 @@ func TestInsertNotification(t *testing.T) {
 }
 
 func TestBatchNotificationInsert(t *testing.T) {
+	testRequireLive(t)
 	device := testNewDevice()
 	device.Network = testNewNetwork()
 
@@ -569,7 +586,7 @@ func TestBatchNotificationInsert(t *testing.T) {
 	}()
 
 	// receiver
-	listener, err := s2.SubscribeNotifications(device, "", testWaitTimeout)
+	listener, err := s2.SubscribeNotifications(device, core.Timestamp{}, testWaitTimeout)
 	if err != nil {
 		t.Errorf("failed to subscribe notifications: %s", err)
 		return
@@ -0,0 +1,395 @@
+// Transport-agnostic conformance test suite.
+//
+// This package exercises the full devicehive.Service contract, so
+// REST, Websocket or any custom implementation (hybrid, fake, etc.)
+// could be verified to behave identically:
+//
+//	func TestConformance(t *testing.T) {
+//		servicetest.Run(t, func(t *testing.T) devicehive.Service {
+//			service, err := devicehive.NewService(url, accessKey)
+//			if err != nil {
+//				t.Fatalf("failed to create service (error: %s)", err)
+//			}
+//			return service
+//		})
+//	}
+package servicetest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"testing"
+	"time"
+)
+
+// Factory creates a new service under test.
+// If nil service is returned the test is skipped.
+type Factory func(t *testing.T) devicehive.Service
+
+// Command inserter is used to put test commands to the server.
+// The Service interface has no InsertCommand method,
+// so an auxiliary client might be used.
+type CommandInserter interface {
+	InsertCommand(device *core.Device, command *core.Command, timeout time.Duration) (err error)
+}
+
+// Conformance suite configuration.
+type Config struct {
+	// Creates a new service under test [required].
+	NewService Factory
+
+	// Creates an auxiliary command inserter [optional].
+	// If nil the service under test is used (if it implements CommandInserter).
+	NewInserter func(t *testing.T) CommandInserter
+
+	// Test device identifier, key and name.
+	// Each test appends its own suffix to get unique device.
+	DeviceId   string
+	DeviceKey  string
+	DeviceName string
+
+	// Test device class.
+	DeviceClassName    string
+	DeviceClassVersion string
+
+	// Test network [optional].
+	Network *core.Network
+
+	// Timeout for all synchronous operations.
+	Timeout time.Duration
+
+	// Timeout to wait asynchronous commands.
+	WaitTimeout time.Duration
+
+	// Time to make sure nothing is received after unsubscribe.
+	QuietPeriod time.Duration
+}
+
+// DefaultConfig creates a default configuration for the provided factory.
+func DefaultConfig(factory Factory) Config {
+	return Config{
+		NewService:         factory,
+		DeviceId:           "go-conformance-dev-id",
+		DeviceKey:          "go-conformance-dev-key",
+		DeviceName:         "go-conformance-dev-name",
+		DeviceClassName:    "go-conformance-class",
+		DeviceClassVersion: "1.0.0",
+		Timeout:            60 * time.Second,
+		WaitTimeout:        30 * time.Second,
+		QuietPeriod:        3 * time.Second,
+	}
+}
+
+// Run runs the conformance suite with default configuration.
+func Run(t *testing.T, factory Factory) {
+	RunConfig(t, DefaultConfig(factory))
+}
+
+// RunConfig runs the conformance suite with custom configuration.
+func RunConfig(t *testing.T, config Config) {
+	s := &suite{config: config}
+
+	t.Run("ServerInfo", s.testServerInfo)
+	t.Run("Timeout", s.testTimeout)
+	t.Run("RegisterDevice", s.testRegisterDevice)
+	t.Run("GetUnknownDevice", s.testGetUnknownDevice)
+	t.Run("Notification", s.testNotification)
+	t.Run("GetUnknownNotification", s.testGetUnknownNotification)
+	t.Run("Command", s.testCommand)
+	t.Run("SubscribeCommands", s.testSubscribeCommands)
+}
+
+// conformance suite
+type suite struct {
+	config Config
+}
+
+// create new service under test, skip the test if not available
+func (s *suite) newService(t *testing.T) devicehive.Service {
+	service := s.config.NewService(t)
+	if service == nil {
+		t.Skip("no service available")
+	}
+	return service
+}
+
+// get command inserter, skip the test if not available
+func (s *suite) newInserter(t *testing.T, service devicehive.Service) CommandInserter {
+	if s.config.NewInserter != nil {
+		if inserter := s.config.NewInserter(t); inserter != nil {
+			return inserter
+		}
+	} else if inserter, ok := service.(CommandInserter); ok {
+		return inserter
+	}
+
+	t.Skip("no command inserter available")
+	return nil
+}
+
+// creates new test device with unique suffix
+func (s *suite) newDevice(suffix string) *core.Device {
+	dc := core.NewDeviceClass(s.config.DeviceClassName, s.config.DeviceClassVersion)
+	device := core.NewDevice(s.config.DeviceId+"-"+suffix, s.config.DeviceName+"-"+suffix, dc)
+	if len(s.config.DeviceKey) != 0 {
+		device.Key = s.config.DeviceKey + "-" + suffix
+	}
+	if s.config.Network != nil {
+		network := *s.config.Network
+		device.Network = &network
+	}
+	return device
+}
+
+// register the test device, fail the test on error
+func (s *suite) registerDevice(t *testing.T, service devicehive.Service, device *core.Device) {
+	err := service.RegisterDevice(device, s.config.Timeout)
+	if err != nil {
+		t.Fatalf("failed to register device %s (error: %s)", device, err)
+	}
+}
+
+// check the GetServerInfo method
+func (s *suite) testServerInfo(t *testing.T) {
+	service := s.newService(t)
+
+	info, err := service.GetServerInfo(s.config.Timeout)
+	if err != nil {
+		t.Fatalf("failed to get server info (error: %s)", err)
+	}
+
+	if len(info.Version) == 0 {
+		t.Errorf("no API version in %s", info)
+	}
+	if info.Timestamp.IsZero() {
+		t.Errorf("no server timestamp in %s", info)
+	}
+}
+
+// check all methods report timeout the same way
+func (s *suite) testTimeout(t *testing.T) {
+	service := s.newService(t)
+
+	_, err := service.GetServerInfo(time.Nanosecond)
+	if err == nil {
+		t.Fatalf("no error for tiny timeout")
+	}
+	if !errors.Is(err, core.ErrTimeout) {
+		t.Errorf("unexpected timeout error: %s", err)
+	}
+}
+
+// check the RegisterDevice and GetDevice methods
+func (s *suite) testRegisterDevice(t *testing.T) {
+	service := s.newService(t)
+	device := s.newDevice("register")
+
+	// create new device
+	s.registerDevice(t, service, device)
+	s.checkGetDevice(t, service, device)
+
+	// update the same device
+	device.Name += "-new"
+	device.Status = "Bad"
+	device.Data = "new data"
+	s.registerDevice(t, service, device)
+	s.checkGetDevice(t, service, device)
+}
+
+// get device and compare it with expected one
+func (s *suite) checkGetDevice(t *testing.T, service devicehive.Service, expected *core.Device) {
+	device, err := service.GetDevice(expected.Id, expected.Key, s.config.Timeout)
+	if err != nil {
+		t.Fatalf("failed to get device %q (error: %s)", expected.Id, err)
+	}
+
+	if device.Id != expected.Id {
+		t.Errorf("device id mismatch: %q != %q", device.Id, expected.Id)
+	}
+	if device.Name != expected.Name {
+		t.Errorf("device name mismatch: %q != %q", device.Name, expected.Name)
+	}
+	if device.Status != expected.Status {
+		t.Errorf("device status mismatch: %q != %q", device.Status, expected.Status)
+	}
+	if !jsonEqual(device.Data, expected.Data) {
+		t.Errorf("device data mismatch: %v != %v", device.Data, expected.Data)
+	}
+
+	if device.DeviceClass == nil {
+		t.Errorf("no device class in %s", device)
+	} else if device.DeviceClass.Name != expected.DeviceClass.Name ||
+		device.DeviceClass.Version != expected.DeviceClass.Version {
+		t.Errorf("device class mismatch: %s != %s", device.DeviceClass, expected.DeviceClass)
+	}
+}
+
+// check the GetDevice method fails for unknown device
+func (s *suite) testGetUnknownDevice(t *testing.T) {
+	service := s.newService(t)
+	device := s.newDevice("unknown")
+
+	_, err := service.GetDevice(device.Id, device.Key, s.config.Timeout)
+	if err == nil {
+		t.Errorf("no error for unknown device %q", device.Id)
+	}
+}
+
+// check the InsertNotification and GetNotification methods
+func (s *suite) testNotification(t *testing.T) {
+	service := s.newService(t)
+	device := s.newDevice("notification")
+	s.registerDevice(t, service, device)
+
+	notification := core.NewNotification("ntf-conformance", map[string]interface{}{"a": 1, "b": "two"})
+	err := service.InsertNotification(device, notification, s.config.Timeout)
+	if err != nil {
+		t.Fatalf("failed to insert notification (error: %s)", err)
+	}
+	if notification.Id == 0 {
+		t.Errorf("no notification id assigned: %s", notification)
+	}
+	if notification.Timestamp.IsZero() {
+		t.Errorf("no notification timestamp assigned: %s", notification)
+	}
+
+	notification2, err := service.GetNotification(device, notification.Id, s.config.Timeout)
+	if err != nil {
+		t.Fatalf("failed to get notification (error: %s)", err)
+	}
+	if notification2.Id != notification.Id {
+		t.Errorf("notification id mismatch: %d != %d", notification2.Id, notification.Id)
+	}
+	if notification2.Name != notification.Name {
+		t.Errorf("notification name mismatch: %q != %q", notification2.Name, notification.Name)
+	}
+	if !jsonEqual(notification2.Parameters, notification.Parameters) {
+		t.Errorf("notification parameters mismatch: %v != %v",
+			notification2.Parameters, notification.Parameters)
+	}
+}
+
+// check the GetNotification method fails for unknown notification
+func (s *suite) testGetUnknownNotification(t *testing.T) {
+	service := s.newService(t)
+	device := s.newDevice("notification")
+	s.registerDevice(t, service, device)
+
+	_, err := service.GetNotification(device, 1<<53, s.config.Timeout)
+	if err == nil {
+		t.Errorf("no error for unknown notification")
+	}
+}
+
+// check the GetCommand and UpdateCommand methods
+func (s *suite) testCommand(t *testing.T) {
+	service := s.newService(t)
+	inserter := s.newInserter(t, service)
+	device := s.newDevice("command")
+	s.registerDevice(t, service, device)
+
+	command := core.NewCommand("cmd-conformance", "hello")
+	err := inserter.InsertCommand(device, command, s.config.Timeout)
+	if err != nil {
+		t.Fatalf("failed to insert command (error: %s)", err)
+	}
+	if command.Id == 0 {
+		t.Fatalf("no command id assigned: %s", command)
+	}
+
+	command.Status = "Done"
+	command.Result = "world"
+	err = service.UpdateCommand(device, command, s.config.Timeout)
+	if err != nil {
+		t.Fatalf("failed to update command (error: %s)", err)
+	}
+
+	command2, err := service.GetCommand(device, command.Id, s.config.Timeout)
+	if err != nil {
+		t.Fatalf("failed to get command (error: %s)", err)
+	}
+	if command2.Id != command.Id {
+		t.Errorf("command id mismatch: %d != %d", command2.Id, command.Id)
+	}
+	if command2.Name != command.Name {
+		t.Errorf("command name mismatch: %q != %q", command2.Name, command.Name)
+	}
+	if command2.Status != command.Status {
+		t.Errorf("command status mismatch: %q != %q", command2.Status, command.Status)
+	}
+	if !jsonEqual(command2.Result, command.Result) {
+		t.Errorf("command result mismatch: %v != %v", command2.Result, command.Result)
+	}
+}
+
+// check the SubscribeCommands and UnsubscribeCommands methods
+func (s *suite) testSubscribeCommands(t *testing.T) {
+	service := s.newService(t)
+	inserter := s.newInserter(t, service)
+	device := s.newDevice("subscribe")
+	s.registerDevice(t, service, device)
+
+	info, err := service.GetServerInfo(s.config.Timeout)
+	if err != nil {
+		t.Fatalf("failed to get server info (error: %s)", err)
+	}
+
+	listener, err := service.SubscribeCommands(device, info.Timestamp, s.config.Timeout)
+	if err != nil {
+		t.Fatalf("failed to subscribe commands (error: %s)", err)
+	}
+
+	// commands should be received in the same order
+	const count = 3
+	for i := 0; i < count; i++ {
+		command := core.NewCommand("cmd-subscribe", fmt.Sprintf("%d", i))
+		err = inserter.InsertCommand(device, command, s.config.Timeout)
+		if err != nil {
+			t.Fatalf("failed to insert command #%d (error: %s)", i, err)
+		}
+	}
+	for i := 0; i < count; i++ {
+		select {
+		case command := <-listener.C:
+			if p, ok := command.Parameters.(string); !ok || p != fmt.Sprintf("%d", i) {
+				t.Errorf("command #%d unexpected: %s", i, command)
+			}
+		case <-time.After(s.config.WaitTimeout):
+			t.Fatalf("failed to wait command #%d (timed out)", i)
+		}
+	}
+
+	err = service.UnsubscribeCommands(device, s.config.Timeout)
+	if err != nil {
+		t.Fatalf("failed to unsubscribe commands (error: %s)", err)
+	}
+
+	// nothing should be received after unsubscribe
+	command := core.NewCommand("cmd-subscribe", "late")
+	err = inserter.InsertCommand(device, command, s.config.Timeout)
+	if err != nil {
+		t.Fatalf("failed to insert late command (error: %s)", err)
+	}
+	select {
+	case command := <-listener.C:
+		t.Errorf("command received after unsubscribe: %s", command)
+	case <-time.After(s.config.QuietPeriod):
+		// OK
+	}
+}
+
+// compare two arbitrary values using their JSON representation
+func jsonEqual(a, b interface{}) bool {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	jb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(ja) == string(jb)
+}
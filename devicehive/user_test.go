@@ -0,0 +1,263 @@
+package devicehive
+
+import (
+	"encoding/json"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"github.com/devicehive/devicehive-go/devicehive/rest"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// in-memory /user server, the current user is 1
+func testNewUserServer(t *testing.T) *httptest.Server {
+	var lock sync.Mutex
+	users := map[string]map[string]interface{}{
+		"1": {"id": 1, "login": "admin", "role": 0, "status": 0},
+	}
+	networks := make(map[string]bool) // "user/network"
+	keys := make(map[string]map[string]interface{})
+	lastId := 1
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if len(path) > 1 && path[1] == "current" {
+			path[1] = "1"
+		}
+
+		switch {
+		case len(path) == 1 && r.Method == "POST":
+			lastId++
+			var u map[string]interface{}
+			json.Unmarshal(body, &u)
+			if _, ok := u["password"]; !ok {
+				t.Errorf("no password provided")
+			}
+			delete(u, "password")
+			u["id"] = lastId
+			users[jsonString(lastId)] = u
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": lastId})
+		case len(path) == 1 && r.Method == "GET":
+			var list []interface{}
+			for _, u := range users {
+				if u["login"] == r.URL.Query().Get("login") {
+					list = append(list, u)
+				}
+			}
+			json.NewEncoder(w).Encode(list)
+		case users[path[1]] == nil:
+			w.WriteHeader(http.StatusNotFound)
+		case len(path) == 2 && r.Method == "GET":
+			u := users[path[1]]
+			var nets []interface{}
+			for n := range networks {
+				if strings.HasPrefix(n, path[1]+"/") {
+					nets = append(nets, map[string]interface{}{"network": map[string]interface{}{
+						"id": json.Number(strings.TrimPrefix(n, path[1]+"/")), "name": "net"}})
+				}
+			}
+			u["networks"] = nets
+			json.NewEncoder(w).Encode(u)
+		case len(path) == 2 && r.Method == "PUT":
+			var u map[string]interface{}
+			json.Unmarshal(body, &u)
+			for k, v := range u {
+				if k != "password" {
+					users[path[1]][k] = v
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case len(path) == 2 && r.Method == "DELETE":
+			delete(users, path[1])
+			w.WriteHeader(http.StatusNoContent)
+		case len(path) == 4 && path[2] == "network":
+			n := path[1] + "/" + path[3]
+			switch r.Method {
+			case "PUT":
+				networks[n] = true
+				w.WriteHeader(http.StatusNoContent)
+			case "DELETE":
+				delete(networks, n)
+				w.WriteHeader(http.StatusNoContent)
+			case "GET":
+				if !networks[n] {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Write([]byte(`{"network":{"id":` + path[3] + `,"name":"net"}}`))
+			}
+		case len(path) == 3 && path[2] == "accesskey" && r.Method == "POST":
+			lastId++
+			var k map[string]interface{}
+			json.Unmarshal(body, &k)
+			k["id"] = lastId
+			k["key"] = "secret-key-value"
+			keys[jsonString(lastId)] = k
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": lastId, "key": k["key"]})
+		case len(path) == 3 && path[2] == "accesskey" && r.Method == "GET":
+			list := []interface{}{}
+			for _, k := range keys {
+				list = append(list, k)
+			}
+			json.NewEncoder(w).Encode(list)
+		case len(path) == 4 && path[2] == "accesskey" && keys[path[3]] == nil:
+			w.WriteHeader(http.StatusNotFound)
+		case len(path) == 4 && path[2] == "accesskey" && r.Method == "GET":
+			json.NewEncoder(w).Encode(keys[path[3]])
+		case len(path) == 4 && path[2] == "accesskey" && r.Method == "PUT":
+			var k map[string]interface{}
+			json.Unmarshal(body, &k)
+			for f, v := range k {
+				keys[path[3]][f] = v
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case len(path) == 4 && path[2] == "accesskey" && r.Method == "DELETE":
+			delete(keys, path[3])
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+}
+
+// format JSON value as a string
+func jsonString(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// Test user CRUD and network assignments
+func TestUserCrud(t *testing.T) {
+	server := testNewUserServer(t)
+	defer server.Close()
+
+	service, err := rest.NewService(server.URL, "")
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	me, err := service.GetUser(rest.CurrentUser, testWaitTimeout)
+	if err != nil || me.Login != "admin" || me.Role != core.UserRoleAdmin {
+		t.Fatalf("unexpected current user: %v (error: %v)", me, err)
+	}
+
+	user := core.NewUser("go-user", "passw0rd", core.UserRoleClient)
+	if err = service.InsertUser(user, testWaitTimeout); err != nil || user.Id == 0 {
+		t.Fatalf("failed to insert user %s (error: %v)", user, err)
+	}
+	if strings.Contains(user.String(), "passw0rd") {
+		t.Errorf("password is not redacted: %s", user)
+	}
+	if err = service.InsertUser(core.NewUser("", "", core.UserRoleClient), testWaitTimeout); err == nil {
+		t.Errorf("invalid user inserted")
+	}
+
+	user.Password = ""
+	user.Status = core.UserStatusLocked
+	if err = service.UpdateUser(user, testWaitTimeout); err != nil {
+		t.Fatalf("failed to update user (error: %s)", err)
+	}
+
+	// network assignments
+	if err = service.AssignNetwork(user.Id, 7, testWaitTimeout); err != nil {
+		t.Fatalf("failed to assign network (error: %s)", err)
+	}
+	network, err := service.GetUserNetwork(user.Id, 7, testWaitTimeout)
+	if err != nil || network.Id != 7 || network.Name != "net" {
+		t.Errorf("unexpected user network: %v (error: %v)", network, err)
+	}
+	got, err := service.GetUser(user.Id, testWaitTimeout)
+	if err != nil || got.Status != core.UserStatusLocked ||
+		len(got.Networks) != 1 || got.Networks[0].Id != 7 {
+		t.Errorf("unexpected user: %v (error: %v)", got, err)
+	}
+	if err = service.UnassignNetwork(user.Id, 7, testWaitTimeout); err != nil {
+		t.Errorf("failed to unassign network (error: %s)", err)
+	}
+	if _, err = service.GetUserNetwork(user.Id, 7, testWaitTimeout); err == nil {
+		t.Errorf("unassigned network is still available")
+	}
+
+	list, err := service.GetUserListFilter(&core.UserFilter{Login: "go-user"}, testWaitTimeout)
+	if err != nil || len(list) != 1 || list[0].Id != user.Id {
+		t.Errorf("unexpected user list: %v (error: %v)", list, err)
+	}
+
+	if err = service.DeleteUser(user, testWaitTimeout); err != nil {
+		t.Errorf("failed to delete user (error: %s)", err)
+	}
+	if _, err = service.GetUser(user.Id, testWaitTimeout); err == nil {
+		t.Errorf("deleted user is still available")
+	}
+}
+
+// Test access key CRUD with permissions
+func TestAccessKeyCrud(t *testing.T) {
+	server := testNewUserServer(t)
+	defer server.Close()
+
+	service, err := rest.NewService(server.URL, "")
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	key := core.NewAccessKey("customer",
+		&core.AccessKeyPermission{
+			Subnets:    []string{"10.10.0.0/16"},
+			Actions:    []core.AccessKeyAction{core.ActionGetDevice, core.ActionCreateDeviceNotification},
+			NetworkIds: []uint64{7},
+			DeviceIds:  []string{},
+		})
+	if err = service.InsertAccessKey(rest.CurrentUser, key, testWaitTimeout); err != nil || key.Id == 0 || key.Key == "" {
+		t.Fatalf("failed to insert access key %s (error: %v)", key, err)
+	}
+	if strings.Contains(key.String(), key.Key) {
+		t.Errorf("access key is not redacted: %s", key)
+	}
+
+	got, err := service.GetAccessKey(rest.CurrentUser, key.Id, testWaitTimeout)
+	if err != nil || len(got.Permissions) != 1 {
+		t.Fatalf("unexpected access key: %v (error: %v)", got, err)
+	}
+	perm := got.Permissions[0]
+	if perm.Domains != nil || perm.DeviceIds == nil || len(perm.DeviceIds) != 0 {
+		t.Errorf("nil and empty lists are not preserved: %s", perm)
+	}
+	if !perm.HasAction(core.ActionGetDevice) || perm.HasAction(core.ActionManageAccessKey) {
+		t.Errorf("unexpected permission actions: %s", perm)
+	}
+	if len(perm.NetworkIds) != 1 || perm.NetworkIds[0] != 7 {
+		t.Errorf("unexpected permission networks: %s", perm)
+	}
+
+	key.Label = "customer-2"
+	if err = service.UpdateAccessKey(rest.CurrentUser, key, testWaitTimeout); err != nil {
+		t.Fatalf("failed to update access key (error: %s)", err)
+	}
+	if key.Label = ""; service.UpdateAccessKey(rest.CurrentUser, key, testWaitTimeout) == nil {
+		t.Errorf("invalid access key updated")
+	}
+
+	list, err := service.GetAccessKeyList(rest.CurrentUser, testWaitTimeout)
+	if err != nil || len(list) != 1 || list[0].Label != "customer-2" {
+		t.Errorf("unexpected access key list: %v (error: %v)", list, err)
+	}
+
+	if err = service.DeleteAccessKey(rest.CurrentUser, key, testWaitTimeout); err != nil {
+		t.Errorf("failed to delete access key (error: %s)", err)
+	}
+	if _, err = service.GetAccessKey(rest.CurrentUser, key.Id, testWaitTimeout); err == nil {
+		t.Errorf("deleted access key is still available")
+	}
+}
@@ -0,0 +1,294 @@
+package ws
+
+import (
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"sync"
+	"time"
+)
+
+const (
+	// Default maximum number of asynchronous requests in flight.
+	DefaultMaxInFlight = 256
+
+	// Default timeout of an asynchronous request.
+	DefaultAsyncTimeout = 30 * time.Second
+)
+
+// Future is the pending result of an asynchronous request.
+//
+// Futures of the same service are completed in the order they are
+// submitted, even if responses arrive out of order (see SetAsyncOrdered).
+// Completion callbacks are called from a single goroutine in the same order,
+// so they should not block and should not submit new asynchronous requests.
+type Future struct {
+	service  *Service
+	done     chan struct{}
+	err      error
+	ready    bool // response is received or request is failed
+	task     *Task
+	timer    *time.Timer
+	process  func(task *Task) error // response processing
+	callback func(err error)
+}
+
+// Done() returns the channel closed when the request is completed.
+func (future *Future) Done() <-chan struct{} {
+	return future.done
+}
+
+// Err() gets the request error, nil if succeeded.
+// Valid only after the Done() channel is closed.
+func (future *Future) Err() error {
+	select {
+	case <-future.done:
+		return future.err
+	default:
+		return nil
+	}
+}
+
+// Wait() waits for the request completion and returns the request error.
+// The request is not canceled if timed out.
+func (future *Future) Wait(timeout time.Duration) error {
+	select {
+	case <-time.After(timeout):
+		return core.ErrTimeout
+
+	case <-future.done:
+		return future.err
+	}
+}
+
+// Cancel() cancels the request if the response is not received yet.
+// The future is completed with ErrCanceled, late response is discarded.
+func (future *Future) Cancel() {
+	q := &future.service.async
+	q.lock.Lock()
+	task := future.task
+	q.lock.Unlock()
+
+	if task != nil && future.service.cancelTask(task) {
+		future.service.completeAsync(future, ErrCanceled)
+	}
+}
+
+// asynchronous requests in submission order
+type asyncQueue struct {
+	lock        sync.Mutex
+	slotFree    *sync.Cond // in-flight request is completed or connection is lost
+	hasReady    *sync.Cond // completed list is not empty
+	maxInFlight int
+	timeout     time.Duration
+	unordered   bool      // complete as soon as ready, ignoring submission order
+	inFlight    int       // submitted but not yet completed
+	pending     []*Future // waiting for response, in submission order
+	completed   []*Future // ready for completion
+}
+
+// initialize the queue with default settings
+func (q *asyncQueue) init() {
+	q.slotFree = sync.NewCond(&q.lock)
+	q.hasReady = sync.NewCond(&q.lock)
+	q.maxInFlight = DefaultMaxInFlight
+	q.timeout = DefaultAsyncTimeout
+}
+
+// SetMaxInFlight changes the maximum number of asynchronous requests in flight.
+// Asynchronous calls block while the window is full. Zero means DefaultMaxInFlight.
+func (service *Service) SetMaxInFlight(n int) {
+	if n <= 0 {
+		n = DefaultMaxInFlight
+	}
+
+	service.async.lock.Lock()
+	defer service.async.lock.Unlock()
+	service.async.maxInFlight = n
+	service.async.slotFree.Broadcast()
+}
+
+// SetAsyncTimeout changes the timeout of asynchronous requests.
+// Zero means DefaultAsyncTimeout.
+func (service *Service) SetAsyncTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultAsyncTimeout
+	}
+
+	service.async.lock.Lock()
+	defer service.async.lock.Unlock()
+	service.async.timeout = timeout
+}
+
+// SetAsyncOrdered enables or disables in-order completion of asynchronous requests.
+// Futures are completed in submission order by default, so a lost response
+// delays all the later futures until it's timed out. Disable ordering to
+// complete futures (and call callbacks) as soon as responses arrive.
+func (service *Service) SetAsyncOrdered(ordered bool) {
+	q := &service.async
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.unordered = !ordered
+	q.moveReady()
+}
+
+// move ready requests to the completed list
+// should be called under lock
+func (q *asyncQueue) moveReady() {
+	n := len(q.completed)
+	if q.unordered {
+		pending := q.pending[:0]
+		for _, future := range q.pending {
+			if future.ready {
+				q.completed = append(q.completed, future)
+			} else {
+				pending = append(pending, future)
+			}
+		}
+		for i := len(pending); i < len(q.pending); i++ {
+			q.pending[i] = nil // release moved futures
+		}
+		q.pending = pending
+	} else {
+		k := 0
+		for k < len(q.pending) && q.pending[k].ready {
+			k++
+		}
+		q.completed = append(q.completed, q.pending[:k]...)
+		m := copy(q.pending, q.pending[k:])
+		for i := m; i < len(q.pending); i++ {
+			q.pending[i] = nil // release moved futures
+		}
+		q.pending = q.pending[:m]
+	}
+	if len(q.completed) != n {
+		q.hasReady.Signal()
+	}
+}
+
+// check if the connection is lost, without locking
+func (service *Service) isClosed() bool {
+	select {
+	case <-service.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// submit asynchronous request
+// waits for a free slot in the in-flight window
+// fails immediately if the connection is lost,
+// the earlier requests are completed first
+func (service *Service) submitAsync(name string, prepare func() (*Task, error), process func(task *Task) error, callback func(err error)) *Future {
+	future := &Future{service: service, done: make(chan struct{}),
+		process: process, callback: callback}
+	q := &service.async
+
+	q.lock.Lock()
+	for q.inFlight >= q.maxInFlight && !service.isClosed() {
+		q.slotFree.Wait()
+	}
+	if service.isClosed() {
+		if q.inFlight != 0 {
+			// pass through the completion thread to keep the order
+			q.inFlight += 1
+			q.pending = append(q.pending, future)
+			q.lock.Unlock()
+			service.completeAsync(future, service.Err())
+			return future
+		}
+		q.lock.Unlock()
+
+		// fail immediately, the request is not submitted
+		future.err = service.Err()
+		future.ready = true
+		close(future.done)
+		if callback != nil {
+			callback(future.err)
+		}
+		return future
+	}
+	q.inFlight += 1
+	q.pending = append(q.pending, future)
+	timeout := q.timeout
+	q.lock.Unlock()
+
+	task, err := prepare()
+	if err != nil {
+		service.completeAsync(future, err)
+		return future
+	}
+
+	task.complete = func(task *Task) {
+		service.completeAsync(future, nil)
+	}
+
+	q.lock.Lock()
+	future.task = task
+	future.timer = time.AfterFunc(timeout, func() {
+		if service.cancelTask(task) {
+			service.logger.Warnf("WS: failed to wait %s for /%s task", timeout, name)
+			service.completeAsync(future, core.ErrTimeout)
+		}
+	})
+	q.lock.Unlock()
+
+	// add to the TX pipeline
+	service.send(task)
+	return future
+}
+
+// mark the request as ready
+// ready requests are passed to the completion thread in submission order
+func (service *Service) completeAsync(future *Future, err error) {
+	q := &service.async
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if future.ready {
+		return // already completed
+	}
+	if future.timer != nil {
+		future.timer.Stop()
+	}
+	future.err = err
+	future.ready = true
+	q.moveReady()
+}
+
+// completion thread
+// stopped when the connection is lost and all requests are completed
+func (service *Service) doComplete() {
+	q := &service.async
+	for {
+		q.lock.Lock()
+		for len(q.completed) == 0 {
+			if q.inFlight == 0 && service.isClosed() {
+				q.lock.Unlock()
+				service.logger.Debugf("WS: completion thread stopped")
+				return
+			}
+			q.hasReady.Wait()
+		}
+		completed := q.completed
+		q.completed = nil
+		q.lock.Unlock()
+
+		for _, future := range completed {
+			if future.err == nil && future.process != nil {
+				future.err = future.process(future.task)
+			}
+			close(future.done)
+			if future.callback != nil {
+				future.callback(future.err)
+			}
+
+			// the slot is freed after the callback,
+			// so callbacks of the later requests are not called earlier
+			q.lock.Lock()
+			future.task = nil // release the response
+			q.inFlight -= 1
+			q.slotFree.Signal()
+			q.lock.Unlock()
+		}
+	}
+}
@@ -1,22 +1,21 @@
 package ws
 
 import (
-	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"time"
 )
 
 // Prepare Authenticate task
 func (service *Service) prepareAuthenticate(device *core.Device) (task *Task, err error) {
-	task = service.newTask()
-	task.dataToSend = map[string]interface{}{
-		"action":    "authenticate",
-		"accessKey": service.accessKey,
-		"requestId": task.id}
+	task, err = service.newTask()
+	if err != nil {
+		return
+	}
 
-	// prepare authorization
-	task.prepareAuthorization(device)
+	task.request = &authenticateRequest{
+		requestHeader: requestHeader{Action: "authenticate", RequestId: task.id},
+		deviceAuth:    newDeviceAuth(device),
+		AccessKey:     service.accessKey}
 
 	return
 }
@@ -26,7 +25,7 @@ func (service *Service) processAuthenticate(task *Task) (err error) {
 	// check response status
 	err = task.CheckStatus()
 	if err != nil {
-		log.Warnf("WS: bad /authenticate status (error: %s)", err)
+		service.logger.Warnf("WS: bad /authenticate status (error: %s)", err)
 		return
 	}
 
@@ -37,22 +36,23 @@ func (service *Service) processAuthenticate(task *Task) (err error) {
 func (service *Service) Authenticate(device *core.Device, timeout time.Duration) (err error) {
 	task, err := service.prepareAuthenticate(device)
 	if err != nil {
-		log.Warnf("WS: failed to prepare /authenticate task (error: %s)", err)
+		service.logger.Warnf("WS: failed to prepare /authenticate task (error: %s)", err)
 		return
 	}
 
 	// add to the TX pipeline
-	service.tx <- task
+	service.send(task)
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("WS: failed to wait %s for /authenticate task", timeout)
-		err = fmt.Errorf("timed out")
+		service.cancelTask(task)
+		service.logger.Warnf("WS: failed to wait %s for /authenticate task", timeout)
+		err = core.ErrTimeout
 
 	case <-task.done:
 		err = service.processAuthenticate(task)
 		if err != nil {
-			log.Warnf("WS: failed to process /authenticate task (error: %s)", err)
+			service.logger.Warnf("WS: failed to process /authenticate task (error: %s)", err)
 			return
 		}
 	}
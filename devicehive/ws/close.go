@@ -0,0 +1,119 @@
+package ws
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gorilla/websocket"
+)
+
+// Error returned by all calls after the Websocket connection is lost.
+// The actual error is *ConnectionClosedError, check with errors.Is().
+var ErrConnectionClosed = errors.New("connection closed")
+
+// Cause of the lost Websocket connection.
+type ConnectionClosedError struct {
+	// Close code and reason, if closed by the server's Close frame.
+	Code   int
+	Reason string
+
+	// Underlying network error.
+	Err error
+}
+
+// Get error string representation
+func (err *ConnectionClosedError) Error() string {
+	if err.Code != 0 {
+		return fmt.Sprintf("connection closed (code: %d, reason: %q)", err.Code, err.Reason)
+	}
+	if err.Err != nil {
+		return fmt.Sprintf("connection closed (error: %s)", err.Err)
+	}
+	return "connection closed"
+}
+
+// Unwrap() gets the underlying network error.
+func (err *ConnectionClosedError) Unwrap() error {
+	return err.Err
+}
+
+// Is() reports the error matches ErrConnectionClosed.
+func (err *ConnectionClosedError) Is(target error) bool {
+	return target == ErrConnectionClosed
+}
+
+// Done() returns the channel closed when the connection is lost.
+func (service *Service) Done() <-chan struct{} {
+	return service.done
+}
+
+// Err() gets the cause of the lost connection, nil if connection is alive.
+func (service *Service) Err() error {
+	service.taskLock.Lock()
+	defer service.taskLock.Unlock()
+
+	if service.err == nil {
+		return nil // avoid typed nil
+	}
+	return service.err
+}
+
+// mark the connection lost
+// all pending tasks are failed, TX and completion threads are stopped
+// command listeners are closed by RX thread, see closeCommandListeners()
+func (service *Service) closeWith(cause error) {
+	err := &ConnectionClosedError{Err: cause}
+	var closeErr *websocket.CloseError
+	if errors.As(cause, &closeErr) {
+		err.Code, err.Reason = closeErr.Code, closeErr.Text
+	}
+
+	service.taskLock.Lock()
+	if service.err != nil {
+		service.taskLock.Unlock()
+		return // already closed
+	}
+	service.err = err
+	tasks := service.tasks
+	service.tasks = make(map[uint64]*Task)
+	close(service.done)
+	service.taskLock.Unlock()
+
+	// wake up asynchronous calls waiting for a free slot
+	// and the completion thread
+	service.async.lock.Lock()
+	service.async.slotFree.Broadcast()
+	service.async.hasReady.Signal()
+	service.async.lock.Unlock()
+
+	service.logger.Warnf("WS: connection lost (error: %s), %d pending requests failed", err, len(tasks))
+	service.conn.Close()
+
+	for _, task := range tasks {
+		task.err = err
+		if task.complete != nil {
+			task.complete(task)
+		} else {
+			task.done <- task // buffered
+		}
+	}
+}
+
+// close all command listeners
+// called from RX thread once the connection is lost
+func (service *Service) closeCommandListeners() {
+	service.commandListenerLock.Lock()
+	defer service.commandListenerLock.Unlock()
+	for deviceId, listener := range service.commandListeners {
+		listener.close()
+		delete(service.commandListeners, deviceId)
+	}
+}
+
+// add the task to the TX pipeline
+// the task is dropped if the connection is lost, it's already failed
+func (service *Service) send(task *Task) {
+	select {
+	case service.tx <- task:
+	case <-service.done:
+	}
+}
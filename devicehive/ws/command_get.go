@@ -0,0 +1,71 @@
+package ws
+
+import (
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"time"
+)
+
+// Prepare GetCommand task
+func (service *Service) prepareGetCommand(device *core.Device, commandId uint64) (task *Task, err error) {
+	task, err = service.newTask()
+	if err != nil {
+		return
+	}
+
+	task.request = &getCommandRequest{
+		requestHeader: requestHeader{Action: "command/get", RequestId: task.id},
+		deviceAuth:    newDeviceAuth(device),
+		CommandId:     commandId}
+
+	return
+}
+
+// Process GetCommand task
+func (service *Service) processGetCommand(task *Task, command *core.Command) (err error) {
+	// parse response
+	response := &getCommandResponse{Command: command}
+	err = task.Decode(response)
+	if err != nil {
+		service.logger.Warnf("WS: failed to parse /command/get body (error: %s)", err)
+		return
+	}
+
+	// check response status
+	err = response.check()
+	if err != nil {
+		service.logger.Warnf("WS: bad /command/get status (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// GetCommand() function gets the device command.
+func (service *Service) GetCommand(device *core.Device, commandId uint64, timeout time.Duration) (command *core.Command, err error) {
+	task, err := service.prepareGetCommand(device, commandId)
+	if err != nil {
+		service.logger.Warnf("WS: failed to prepare /command/get task (error: %s)", err)
+		return
+	}
+
+	// add to the TX pipeline
+	service.send(task)
+
+	select {
+	case <-time.After(timeout):
+		service.cancelTask(task)
+		service.logger.Warnf("WS: failed to wait %s for /command/get task", timeout)
+		err = core.ErrTimeout
+
+	case <-task.done:
+		command = &core.Command{}
+		err = service.processGetCommand(task, command)
+		if err != nil {
+			service.logger.Warnf("WS: failed to process /command/get task (error: %s)", err)
+			command = nil
+			return
+		}
+	}
+
+	return
+}
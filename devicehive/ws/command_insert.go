@@ -0,0 +1,113 @@
+package ws
+
+import (
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"time"
+)
+
+// Prepare InsertCommand task
+func (service *Service) prepareInsertCommand(device *core.Device, command *core.Command) (task *Task, err error) {
+	task, err = service.newTask()
+	if err != nil {
+		return
+	}
+
+	task.request = &insertCommandRequest{
+		requestHeader: requestHeader{Action: "command/insert", RequestId: task.id},
+		DeviceId:      device.Id,
+		Command: &core.Command{Name: command.Name,
+			Parameters: command.Parameters,
+			Lifetime:   command.Lifetime}}
+
+	return
+}
+
+// Process InsertCommand task
+func (service *Service) processInsertCommand(task *Task, command *core.Command) (err error) {
+	// parse response
+	response := &insertCommandResponse{Command: command}
+	err = task.Decode(response)
+	if err != nil {
+		service.logger.Warnf("WS: failed to parse /command/insert response (error: %s)", err)
+		return
+	}
+
+	// check response status
+	err = response.check()
+	if err != nil {
+		service.logger.Warnf("WS: bad /command/insert status (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// InsertCommand() function inserts the device command.
+// The server should support command/insert action on the device connection.
+func (service *Service) InsertCommand(device *core.Device, command *core.Command, timeout time.Duration) (err error) {
+	// client-side validation
+	err = core.Validate(command)
+	if err != nil {
+		service.logger.Warnf("WS: invalid command (error: %s)", err)
+		return
+	}
+
+	task, err := service.prepareInsertCommand(device, command)
+	if err != nil {
+		service.logger.Warnf("WS: failed to prepare /command/insert task (error: %s)", err)
+		return
+	}
+
+	// add to the TX pipeline
+	service.send(task)
+
+	select {
+	case <-time.After(timeout):
+		service.cancelTask(task)
+		service.logger.Warnf("WS: failed to wait %s for /command/insert task", timeout)
+		err = core.ErrTimeout
+
+	case <-task.done:
+		err = service.processInsertCommand(task, command)
+		if err != nil {
+			service.logger.Warnf("WS: failed to process /command/insert task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
+
+// InsertCommandAsync() function inserts the device command without
+// waiting for the response. The command is updated when the returned
+// future is completed, callback [optional] is called on completion.
+func (service *Service) InsertCommandAsync(device *core.Device, command *core.Command, callback func(err error)) *Future {
+	prepare := func() (task *Task, err error) {
+		// client-side validation
+		err = core.Validate(command)
+		if err != nil {
+			service.logger.Warnf("WS: invalid command (error: %s)", err)
+			return
+		}
+
+		task, err = service.prepareInsertCommand(device, command)
+		if err != nil {
+			service.logger.Warnf("WS: failed to prepare /command/insert task (error: %s)", err)
+			return
+		}
+
+		return
+	}
+
+	process := func(task *Task) (err error) {
+		err = service.processInsertCommand(task, command)
+		if err != nil {
+			service.logger.Warnf("WS: failed to process /command/insert task (error: %s)", err)
+			return
+		}
+
+		return
+	}
+
+	return service.submitAsync("command/insert", prepare, process, callback)
+}
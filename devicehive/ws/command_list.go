@@ -0,0 +1,81 @@
+package ws
+
+import (
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"time"
+)
+
+// Prepare GetCommandList task
+func (service *Service) prepareGetCommandList(device *core.Device, filter *core.CommandFilter) (task *Task, err error) {
+	task, err = service.newTask()
+	if err != nil {
+		return
+	}
+
+	request := &listCommandRequest{
+		requestHeader: requestHeader{Action: "command/list", RequestId: task.id},
+		deviceAuth:    newDeviceAuth(device)}
+	if filter != nil {
+		request.Start = optionalTimestamp(filter.Start)
+		request.End = optionalTimestamp(filter.End)
+		request.Command = filter.Command
+		request.Status = filter.Status
+		request.SortField = filter.SortField
+		request.SortOrder = filter.SortOrder
+		request.Take = filter.Take
+		request.Skip = filter.Skip
+	}
+
+	task.request = request
+	return
+}
+
+// Process GetCommandList task
+func (service *Service) processGetCommandList(task *Task) (commands []core.Command, err error) {
+	// parse response
+	response := &listCommandResponse{}
+	err = task.Decode(response)
+	if err != nil {
+		service.logger.Warnf("WS: failed to parse /command/list body (error: %s)", err)
+		return
+	}
+
+	// check response status
+	err = response.check()
+	if err != nil {
+		service.logger.Warnf("WS: bad /command/list status (error: %s)", err)
+		return
+	}
+
+	commands = response.Commands
+	return
+}
+
+// GetCommandList() function gets the command history of the device.
+// The server should support command/list action on the device connection.
+func (service *Service) GetCommandList(device *core.Device, filter *core.CommandFilter, timeout time.Duration) (commands []core.Command, err error) {
+	task, err := service.prepareGetCommandList(device, filter)
+	if err != nil {
+		service.logger.Warnf("WS: failed to prepare /command/list task (error: %s)", err)
+		return
+	}
+
+	// add to the TX pipeline
+	service.send(task)
+
+	select {
+	case <-time.After(timeout):
+		service.cancelTask(task)
+		service.logger.Warnf("WS: failed to wait %s for /command/list task", timeout)
+		err = core.ErrTimeout
+
+	case <-task.done:
+		commands, err = service.processGetCommandList(task)
+		if err != nil {
+			service.logger.Warnf("WS: failed to process /command/list task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
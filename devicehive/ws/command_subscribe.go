@@ -1,27 +1,31 @@
 package ws
 
 import (
-	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"time"
 )
 
+// Capacity of a command listener.
+// RX thread is blocked while the listener is full.
+const commandListenerSize = 64
+
 // Prepare SubscribeCommand task
-func (service *Service) prepareSubscribeCommand(device *core.Device, timestamp string) (task *Task, err error) {
-	task = service.newTask()
-	task.dataToSend = map[string]interface{}{
-		"action":    "command/subscribe",
-		"requestId": task.id}
+func (service *Service) prepareSubscribeCommand(device *core.Device, timestamp core.Timestamp) (task *Task, err error) {
+	task, err = service.newTask()
+	if err != nil {
+		return
+	}
+
+	request := &subscribeCommandRequest{
+		requestHeader: requestHeader{Action: "command/subscribe", RequestId: task.id},
+		deviceAuth:    newDeviceAuth(device)}
 
 	// timestamp [optional]
-	if len(timestamp) != 0 {
-		task.dataToSend["timestamp"] = timestamp
+	if !timestamp.IsZero() {
+		request.Timestamp = &timestamp
 	}
 
-	// prepare authorization
-	task.prepareAuthorization(device)
-
+	task.request = request
 	return
 }
 
@@ -30,40 +34,47 @@ func (service *Service) processSubscribeCommand(task *Task) (err error) {
 	// check response status
 	err = task.CheckStatus()
 	if err != nil {
-		log.Warnf("WS: bad /command/subscribe status (error: %s)", err)
+		service.logger.Warnf("WS: bad /command/subscribe status (error: %s)", err)
 		return
 	}
 
 	return
 }
 
-// SubscribeCommand() function updates the command.
-func (service *Service) SubscribeCommands(device *core.Device, timestamp string, timeout time.Duration) (listener *core.CommandListener, err error) {
+// SubscribeCommand() function subscribes to the device commands.
+// The listener is installed before the request is sent, so commands
+// sent by the server right after the response are not lost.
+// Previous listener of the same device is closed.
+func (service *Service) SubscribeCommands(device *core.Device, timestamp core.Timestamp, timeout time.Duration) (listener *core.CommandListener, err error) {
 	task, err := service.prepareSubscribeCommand(device, timestamp)
 	if err != nil {
-		log.Warnf("WS: failed to prepare /command/subscribe task (error: %s)", err)
+		service.logger.Warnf("WS: failed to prepare /command/subscribe task (error: %s)", err)
 		return
 	}
 
+	// install listener, removed on failure
+	listener = &core.CommandListener{C: make(chan *core.Command, commandListenerSize)}
+	installed := service.insertCommandListener(device.Id, listener)
+
 	// add to the TX pipeline
-	service.tx <- task
+	service.send(task)
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("WS: failed to wait %s for /command/subscribe task", timeout)
-		err = fmt.Errorf("timed out")
+		service.cancelTask(task)
+		service.logger.Warnf("WS: failed to wait %s for /command/subscribe task", timeout)
+		err = core.ErrTimeout
 
 	case <-task.done:
 		err = service.processSubscribeCommand(task)
 		if err != nil {
-			log.Warnf("WS: failed to process /command/subscribe task (error: %s)", err)
-			return
+			service.logger.Warnf("WS: failed to process /command/subscribe task (error: %s)", err)
 		}
-
-		// done, create listener
-		listener = core.NewCommandListener()
-		service.insertCommandListener(device.Id, listener)
 	}
 
+	if err != nil {
+		service.dropCommandListener(device.Id, installed)
+		listener = nil
+	}
 	return
 }
@@ -1,21 +1,20 @@
 package ws
 
 import (
-	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"time"
 )
 
 // Prepare UnsubscribeCommand task
 func (service *Service) prepareUnsubscribeCommand(device *core.Device) (task *Task, err error) {
-	task = service.newTask()
-	task.dataToSend = map[string]interface{}{
-		"action":    "command/unsubscribe",
-		"requestId": task.id}
+	task, err = service.newTask()
+	if err != nil {
+		return
+	}
 
-	// prepare authorization
-	task.prepareAuthorization(device)
+	task.request = &unsubscribeCommandRequest{
+		requestHeader: requestHeader{Action: "command/unsubscribe", RequestId: task.id},
+		deviceAuth:    newDeviceAuth(device)}
 
 	return
 }
@@ -25,7 +24,7 @@ func (service *Service) processUnsubscribeCommand(task *Task) (err error) {
 	// check response status
 	err = task.CheckStatus()
 	if err != nil {
-		log.Warnf("WS: bad /command/unsubscribe status (error: %s)", err)
+		service.logger.Warnf("WS: bad /command/unsubscribe status (error: %s)", err)
 		return
 	}
 
@@ -36,24 +35,25 @@ func (service *Service) processUnsubscribeCommand(task *Task) (err error) {
 func (service *Service) UnsubscribeCommands(device *core.Device, timeout time.Duration) (err error) {
 	task, err := service.prepareUnsubscribeCommand(device)
 	if err != nil {
-		log.Warnf("WS: failed to prepare /command/unsubscribe task (error: %s)", err)
+		service.logger.Warnf("WS: failed to prepare /command/unsubscribe task (error: %s)", err)
 		return
 	}
 
 	service.removeCommandListener(device.Id)
 
 	// add to the TX pipeline
-	service.tx <- task
+	service.send(task)
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("WS: failed to wait %s for /command/unsubscribe task", timeout)
-		err = fmt.Errorf("timed out")
+		service.cancelTask(task)
+		service.logger.Warnf("WS: failed to wait %s for /command/unsubscribe task", timeout)
+		err = core.ErrTimeout
 
 	case <-task.done:
 		err = service.processUnsubscribeCommand(task)
 		if err != nil {
-			log.Warnf("WS: failed to process /command/unsubscribe task (error: %s)", err)
+			service.logger.Warnf("WS: failed to process /command/unsubscribe task (error: %s)", err)
 			return
 		}
 	}
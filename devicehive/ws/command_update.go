@@ -1,26 +1,24 @@
 package ws
 
 import (
-	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"time"
 )
 
 // Prepare UpdateCommand task
 func (service *Service) prepareUpdateCommand(device *core.Device, command *core.Command) (task *Task, err error) {
-	task = service.newTask()
-	task.dataToSend = map[string]interface{}{
-		"action":    "command/update",
-		"commandId": command.Id,
-		"requestId": task.id}
-
-	// prepare authorization
-	task.prepareAuthorization(device)
+	task, err = service.newTask()
+	if err != nil {
+		return
+	}
 
-	cmd_data := *command // deep copy
+	cmd_data := *command // shallow copy, only Id is changed
 	cmd_data.Id = 0      // do not put Id inside
-	task.dataToSend["command"] = cmd_data
+	task.request = &updateCommandRequest{
+		requestHeader: requestHeader{Action: "command/update", RequestId: task.id},
+		deviceAuth:    newDeviceAuth(device),
+		CommandId:     command.Id,
+		Command:       &cmd_data}
 
 	return
 }
@@ -30,7 +28,7 @@ func (service *Service) processUpdateCommand(task *Task) (err error) {
 	// check response status
 	err = task.CheckStatus()
 	if err != nil {
-		log.Warnf("WS: bad /command/update status (error: %s)", err)
+		service.logger.Warnf("WS: bad /command/update status (error: %s)", err)
 		return
 	}
 
@@ -41,25 +39,52 @@ func (service *Service) processUpdateCommand(task *Task) (err error) {
 func (service *Service) UpdateCommand(device *core.Device, command *core.Command, timeout time.Duration) (err error) {
 	task, err := service.prepareUpdateCommand(device, command)
 	if err != nil {
-		log.Warnf("WS: failed to prepare /command/update task (error: %s)", err)
+		service.logger.Warnf("WS: failed to prepare /command/update task (error: %s)", err)
 		return
 	}
 
 	// add to the TX pipeline
-	service.tx <- task
+	service.send(task)
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("WS: failed to wait %s for /command/update task", timeout)
-		err = fmt.Errorf("timed out")
+		service.cancelTask(task)
+		service.logger.Warnf("WS: failed to wait %s for /command/update task", timeout)
+		err = core.ErrTimeout
 
 	case <-task.done:
 		err = service.processUpdateCommand(task)
 		if err != nil {
-			log.Warnf("WS: failed to process /command/update task (error: %s)", err)
+			service.logger.Warnf("WS: failed to process /command/update task (error: %s)", err)
 			return
 		}
 	}
 
 	return
 }
+
+// UpdateCommandAsync() function updates the command without waiting
+// for the response. Callback [optional] is called on completion.
+func (service *Service) UpdateCommandAsync(device *core.Device, command *core.Command, callback func(err error)) *Future {
+	prepare := func() (task *Task, err error) {
+		task, err = service.prepareUpdateCommand(device, command)
+		if err != nil {
+			service.logger.Warnf("WS: failed to prepare /command/update task (error: %s)", err)
+			return
+		}
+
+		return
+	}
+
+	process := func(task *Task) (err error) {
+		err = service.processUpdateCommand(task)
+		if err != nil {
+			service.logger.Warnf("WS: failed to process /command/update task (error: %s)", err)
+			return
+		}
+
+		return
+	}
+
+	return service.submitAsync("command/update", prepare, process, callback)
+}
@@ -0,0 +1,109 @@
+package ws
+
+import (
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"time"
+)
+
+// Maximum number of command updates kept while nobody waits for them.
+// Updates might be received before the command/insert response is processed.
+const maxCommandUpdates = 32
+
+// Capacity of a command waiter, extra updates are dropped.
+const commandWaiterSize = 16
+
+// deliver command/update to the waiter
+// called from RX thread, so it never blocks
+func (service *Service) deliverCommandUpdate(command *core.Command) {
+	service.commandWaiterLock.Lock()
+	defer service.commandWaiterLock.Unlock()
+
+	ch, ok := service.commandWaiters[command.Id]
+	if !ok {
+		service.commandUpdates = append(service.commandUpdates, command)
+		if n := len(service.commandUpdates); n > maxCommandUpdates {
+			service.commandUpdates = service.commandUpdates[n-maxCommandUpdates:]
+		}
+		return
+	}
+
+	select {
+	case ch <- command:
+	default:
+		service.logger.Warnf("WS: too many updates of command %d, %s dropped", command.Id, command)
+	}
+}
+
+// install new command waiter, already received updates are delivered
+func (service *Service) insertCommandWaiter(commandId uint64) chan *core.Command {
+	service.commandWaiterLock.Lock()
+	defer service.commandWaiterLock.Unlock()
+
+	ch := make(chan *core.Command, commandWaiterSize)
+	service.commandWaiters[commandId] = ch
+
+	updates := service.commandUpdates[:0]
+	for _, command := range service.commandUpdates {
+		if command.Id == commandId && len(ch) < cap(ch) {
+			ch <- command
+		} else {
+			updates = append(updates, command)
+		}
+	}
+	service.commandUpdates = updates
+
+	return ch
+}
+
+// remove command waiter
+func (service *Service) removeCommandWaiter(commandId uint64) {
+	service.commandWaiterLock.Lock()
+	defer service.commandWaiterLock.Unlock()
+	delete(service.commandWaiters, commandId)
+}
+
+// SendCommandAndWait() function inserts the command and waits until
+// the device reports the final status. The command's Status and Result
+// are updated in place.
+//
+// Update callback is called for every status change, including the final one.
+// It returns true to finish waiting. Nil update finishes on the first final
+// status (see core.Command.IsFinal). Timeout is the deadline for the whole call.
+//
+// Updates are received as command/update messages pushed by the server.
+func (service *Service) SendCommandAndWait(device *core.Device, command *core.Command, timeout time.Duration, update func(command *core.Command) bool) (err error) {
+	deadline := time.After(timeout)
+	if update == nil {
+		update = (*core.Command).IsFinal
+	}
+
+	err = service.InsertCommand(device, command, timeout)
+	if err != nil {
+		return
+	}
+
+	updates := service.insertCommandWaiter(command.Id)
+	defer service.removeCommandWaiter(command.Id)
+
+	for {
+		select {
+		case <-deadline:
+			service.logger.Warnf("WS: failed to wait %s for command %d result", timeout, command.Id)
+			return core.ErrTimeout
+
+		case <-service.Done():
+			return service.Err()
+
+		case got := <-updates:
+			// status or result is changed
+			prev := command.Clone()
+			command.Status, command.Result = got.Status, got.Result
+			if !prev.Equal(command) {
+				service.logger.Debugf("WS: command %d is updated, status:%q", command.Id, command.Status)
+				if update(command) {
+					return nil // done
+				}
+			}
+		}
+	}
+}
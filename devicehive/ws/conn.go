@@ -0,0 +1,27 @@
+package ws
+
+import (
+	"github.com/gorilla/websocket"
+	"net/http"
+)
+
+// Websocket connection.
+// It's implemented by *websocket.Conn and might be wrapped
+// to inject faults, record traffic, etc.
+type Conn interface {
+	ReadMessage() (messageType int, data []byte, err error)
+	WriteMessage(messageType int, data []byte) error
+	Close() error
+}
+
+// Dial function is used to establish a new Websocket connection.
+type DialFunc func(url string, headers http.Header) (conn Conn, err error)
+
+// DefaultDial establishes a new Websocket connection using default dialer.
+func DefaultDial(url string, headers http.Header) (conn Conn, err error) {
+	c, _, err := websocket.DefaultDialer.Dial(url, headers)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
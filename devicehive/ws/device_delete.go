@@ -0,0 +1,61 @@
+package ws
+
+import (
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"time"
+)
+
+// Prepare DeleteDevice task
+func (service *Service) prepareDeleteDevice(device *core.Device) (task *Task, err error) {
+	task, err = service.newTask()
+	if err != nil {
+		return
+	}
+
+	task.request = &deleteDeviceRequest{
+		requestHeader: requestHeader{Action: "device/delete", RequestId: task.id},
+		deviceAuth:    newDeviceAuth(device)}
+
+	return
+}
+
+// Process DeleteDevice task
+func (service *Service) processDeleteDevice(task *Task) (err error) {
+	// check response status
+	err = task.CheckStatus()
+	if err != nil {
+		service.logger.Warnf("WS: bad /device/delete status (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// DeleteDevice() function deletes the device.
+// The server should support device/delete action on the device connection.
+func (service *Service) DeleteDevice(device *core.Device, timeout time.Duration) (err error) {
+	task, err := service.prepareDeleteDevice(device)
+	if err != nil {
+		service.logger.Warnf("WS: failed to prepare /device/delete task (error: %s)", err)
+		return
+	}
+
+	// add to the TX pipeline
+	service.send(task)
+
+	select {
+	case <-time.After(timeout):
+		service.cancelTask(task)
+		service.logger.Warnf("WS: failed to wait %s for /device/delete task", timeout)
+		err = core.ErrTimeout
+
+	case <-task.done:
+		err = service.processDeleteDevice(task)
+		if err != nil {
+			service.logger.Warnf("WS: failed to process /device/delete task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
@@ -1,38 +1,38 @@
 package ws
 
 import (
-	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"time"
 )
 
 // Prepare GetDevice task
 func (service *Service) prepareGetDevice(device *core.Device) (task *Task, err error) {
-	task = service.newTask()
-	task.dataToSend = map[string]interface{}{
-		"action":    "device/get",
-		"requestId": task.id}
+	task, err = service.newTask()
+	if err != nil {
+		return
+	}
 
-	// prepare authorization
-	task.prepareAuthorization(device)
+	task.request = &getDeviceRequest{
+		requestHeader: requestHeader{Action: "device/get", RequestId: task.id},
+		deviceAuth:    newDeviceAuth(device)}
 
 	return
 }
 
 // Process GetDevice task
 func (service *Service) processGetDevice(task *Task, device *core.Device) (err error) {
-	// check response status
-	err = task.CheckStatus()
+	// parse response
+	response := &getDeviceResponse{Device: device}
+	err = task.Decode(response)
 	if err != nil {
-		log.Warnf("WS: bad /device/get status (error: %s)", err)
+		service.logger.Warnf("WS: failed to parse /device/get body (error: %s)", err)
 		return
 	}
 
-	// parse response
-	err = device.AssignJSON(task.dataRecved["device"])
+	// check response status
+	err = response.check()
 	if err != nil {
-		log.Warnf("WS: failed to parse /device/get body (error: %s)", err)
+		service.logger.Warnf("WS: bad /device/get status (error: %s)", err)
 		return
 	}
 
@@ -44,22 +44,23 @@ func (service *Service) GetDevice(deviceId, deviceKey string, timeout time.Durat
 	device = &core.Device{Id: deviceId, Key: deviceKey}
 	task, err := service.prepareGetDevice(device)
 	if err != nil {
-		log.Warnf("WS: failed to prepare /device/get task (error: %s)", err)
+		service.logger.Warnf("WS: failed to prepare /device/get task (error: %s)", err)
 		return
 	}
 
 	// add to the TX pipeline
-	service.tx <- task
+	service.send(task)
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("WS: failed to wait %s for /device/get task", timeout)
-		err = fmt.Errorf("timed out")
+		service.cancelTask(task)
+		service.logger.Warnf("WS: failed to wait %s for /device/get task", timeout)
+		err = core.ErrTimeout
 
 	case <-task.done:
 		err = service.processGetDevice(task, device)
 		if err != nil {
-			log.Warnf("WS: failed to process /device/get task (error: %s)", err)
+			service.logger.Warnf("WS: failed to process /device/get task (error: %s)", err)
 			return
 		}
 	}
@@ -1,25 +1,23 @@
 package ws
 
 import (
-	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"time"
 )
 
 // Prepare RegisterDevice task
 func (service *Service) prepareRegisterDevice(device *core.Device) (task *Task, err error) {
-	task = service.newTask()
-	task.dataToSend = map[string]interface{}{
-		"action":    "device/save",
-		"requestId": task.id}
-
-	// prepare authorization
-	task.prepareAuthorization(device)
+	task, err = service.newTask()
+	if err != nil {
+		return
+	}
 
-	dev_data := *device // deep copy
+	dev_data := *device // shallow copy, only Id is changed
 	dev_data.Id = ""    // do not put Id inside
-	task.dataToSend["device"] = dev_data
+	task.request = &saveDeviceRequest{
+		requestHeader: requestHeader{Action: "device/save", RequestId: task.id},
+		deviceAuth:    newDeviceAuth(device),
+		Device:        &dev_data}
 
 	return
 }
@@ -29,7 +27,7 @@ func (service *Service) processRegisterDevice(task *Task) (err error) {
 	// check response status
 	err = task.CheckStatus()
 	if err != nil {
-		log.Warnf("WS: bad /device/register status (error: %s)", err)
+		service.logger.Warnf("WS: bad /device/register status (error: %s)", err)
 		return
 	}
 
@@ -38,24 +36,32 @@ func (service *Service) processRegisterDevice(task *Task) (err error) {
 
 // RegisterDevice() function registers the device.
 func (service *Service) RegisterDevice(device *core.Device, timeout time.Duration) (err error) {
+	// client-side validation
+	err = core.Validate(device)
+	if err != nil {
+		service.logger.Warnf("WS: invalid device (error: %s)", err)
+		return
+	}
+
 	task, err := service.prepareRegisterDevice(device)
 	if err != nil {
-		log.Warnf("WS: failed to prepare /device/register task (error: %s)", err)
+		service.logger.Warnf("WS: failed to prepare /device/register task (error: %s)", err)
 		return
 	}
 
 	// add to the TX pipeline
-	service.tx <- task
+	service.send(task)
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("WS: failed to wait %s for /device/register task", timeout)
-		err = fmt.Errorf("timed out")
+		service.cancelTask(task)
+		service.logger.Warnf("WS: failed to wait %s for /device/register task", timeout)
+		err = core.ErrTimeout
 
 	case <-task.done:
 		err = service.processRegisterDevice(task)
 		if err != nil {
-			log.Warnf("WS: failed to process /device/register task (error: %s)", err)
+			service.logger.Warnf("WS: failed to process /device/register task (error: %s)", err)
 			return
 		}
 	}
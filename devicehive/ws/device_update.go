@@ -0,0 +1,70 @@
+package ws
+
+import (
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"time"
+)
+
+// Prepare UpdateDevice task
+func (service *Service) prepareUpdateDevice(device *core.Device, update *core.Device) (task *Task, err error) {
+	task, err = service.newTask()
+	if err != nil {
+		return
+	}
+
+	dev_data := *update // shallow copy, only Id is changed
+	dev_data.Id = ""    // do not put Id inside
+	task.request = &saveDeviceRequest{
+		requestHeader: requestHeader{Action: "device/save", RequestId: task.id},
+		deviceAuth:    newDeviceAuth(device),
+		Device:        &dev_data}
+
+	return
+}
+
+// Process UpdateDevice task
+func (service *Service) processUpdateDevice(task *Task) (err error) {
+	// check response status
+	err = task.CheckStatus()
+	if err != nil {
+		service.logger.Warnf("WS: bad /device/update status (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// UpdateDevice() function partially updates the registered device.
+// Device is used for identification and authorization, update contains
+// only the fields to change, empty fields are not sent (see core.Device.Delta).
+// Nil update does nothing.
+func (service *Service) UpdateDevice(device *core.Device, update *core.Device, timeout time.Duration) (err error) {
+	if update == nil {
+		return // nothing to update
+	}
+
+	task, err := service.prepareUpdateDevice(device, update)
+	if err != nil {
+		service.logger.Warnf("WS: failed to prepare /device/update task (error: %s)", err)
+		return
+	}
+
+	// add to the TX pipeline
+	service.send(task)
+
+	select {
+	case <-time.After(timeout):
+		service.cancelTask(task)
+		service.logger.Warnf("WS: failed to wait %s for /device/update task", timeout)
+		err = core.ErrTimeout
+
+	case <-task.done:
+		err = service.processUpdateDevice(task)
+		if err != nil {
+			service.logger.Warnf("WS: failed to process /device/update task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
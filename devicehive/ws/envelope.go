@@ -0,0 +1,283 @@
+package ws
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"strconv"
+	"strings"
+)
+
+// Message header, decoded first to dispatch the message.
+// The rest of the message is decoded into action specific envelope.
+type messageHeader struct {
+	Action    string      `json:"action"`
+	RequestId json.Number `json:"requestId"`
+}
+
+// get request identifier, zero if not provided
+func (header *messageHeader) requestId() (id uint64, ok bool) {
+	if len(header.RequestId) == 0 {
+		return 0, false
+	}
+
+	id, err := strconv.ParseUint(header.RequestId.String(), 10, 64)
+	return id, err == nil
+}
+
+// Common request header.
+type requestHeader struct {
+	Action    string `json:"action"`
+	RequestId uint64 `json:"requestId"`
+}
+
+// get request header of any request envelope
+func (header *requestHeader) header() *requestHeader {
+	return header
+}
+
+// Device-key authorization, both fields are optional.
+type deviceAuth struct {
+	DeviceId  string `json:"deviceId,omitempty"`
+	DeviceKey string `json:"deviceKey,omitempty"`
+}
+
+// get authorization of any request envelope
+func (auth *deviceAuth) auth() *deviceAuth {
+	return auth
+}
+
+// prepare device-key authorization
+func newDeviceAuth(device *core.Device) (auth deviceAuth) {
+	if device != nil {
+		auth.DeviceId = device.Id
+		auth.DeviceKey = device.Key
+	}
+	return
+}
+
+// Common response status.
+type responseStatus struct {
+	Status string      `json:"status"`
+	Code   json.Number `json:"code"`
+	Error  string      `json:"error"`
+}
+
+// Check "success" status
+func (status *responseStatus) check() (err error) {
+	if !strings.EqualFold(status.Status, "success") {
+		err = fmt.Errorf("unexpected status: %q [%s %s]",
+			status.Status, status.Code, status.Error)
+	}
+	return
+}
+
+// authenticate request
+type authenticateRequest struct {
+	requestHeader
+	deviceAuth
+	AccessKey string `json:"accessKey"`
+}
+
+// command/subscribe request
+type subscribeCommandRequest struct {
+	requestHeader
+	deviceAuth
+	Timestamp *core.Timestamp `json:"timestamp,omitempty"`
+}
+
+// command/unsubscribe request
+type unsubscribeCommandRequest struct {
+	requestHeader
+	deviceAuth
+}
+
+// command/update request
+type updateCommandRequest struct {
+	requestHeader
+	deviceAuth
+	CommandId uint64        `json:"commandId"`
+	Command   *core.Command `json:"command"`
+}
+
+// device/get request
+type getDeviceRequest struct {
+	requestHeader
+	deviceAuth
+}
+
+// device/get response
+type getDeviceResponse struct {
+	responseStatus
+	Device *core.Device `json:"device"`
+}
+
+// device/save request
+type saveDeviceRequest struct {
+	requestHeader
+	deviceAuth
+	Device *core.Device `json:"device"`
+}
+
+// device/delete request
+type deleteDeviceRequest struct {
+	requestHeader
+	deviceAuth
+}
+
+// notification/insert request
+type insertNotificationRequest struct {
+	requestHeader
+	deviceAuth
+	Notification *core.Notification `json:"notification"`
+}
+
+// notification/insert response
+type insertNotificationResponse struct {
+	responseStatus
+	Notification *core.Notification `json:"notification"`
+}
+
+// server/info request
+type serverInfoRequest struct {
+	requestHeader
+}
+
+// server/info response
+type serverInfoResponse struct {
+	responseStatus
+	Info *core.ServerInfo `json:"info"`
+}
+
+// command/insert request
+type insertCommandRequest struct {
+	requestHeader
+	DeviceId string        `json:"deviceGuid"`
+	Command  *core.Command `json:"command"`
+}
+
+// command/insert response
+type insertCommandResponse struct {
+	responseStatus
+	Command *core.Command `json:"command"`
+}
+
+// command/get request
+type getCommandRequest struct {
+	requestHeader
+	deviceAuth
+	CommandId uint64 `json:"commandId"`
+}
+
+// command/get response
+type getCommandResponse struct {
+	responseStatus
+	Command *core.Command `json:"command"`
+}
+
+// notification/get request
+type getNotificationRequest struct {
+	requestHeader
+	deviceAuth
+	NotificationId uint64 `json:"notificationId"`
+}
+
+// notification/get response
+type getNotificationResponse struct {
+	responseStatus
+	Notification *core.Notification `json:"notification"`
+}
+
+// command/update asynchronous message
+type commandUpdateMessage struct {
+	Command *core.Command `json:"command"`
+}
+
+// command/insert asynchronous message
+type commandInsertMessage struct {
+	DeviceId string        `json:"deviceGuid"`
+	Command  *core.Command `json:"command"`
+}
+
+// network/list request
+type listNetworkRequest struct {
+	requestHeader
+	Name        string `json:"name,omitempty"`
+	NamePattern string `json:"namePattern,omitempty"`
+	SortField   string `json:"sortField,omitempty"`
+	SortOrder   string `json:"sortOrder,omitempty"`
+	Take        int    `json:"take,omitempty"`
+	Skip        int    `json:"skip,omitempty"`
+}
+
+// network/list response
+type listNetworkResponse struct {
+	responseStatus
+	Networks []core.Network `json:"networks"`
+}
+
+// network/get and network/delete request
+type networkIdRequest struct {
+	requestHeader
+	NetworkId uint64 `json:"networkId"`
+}
+
+// network/get and network/insert response
+type networkResponse struct {
+	responseStatus
+	Network *core.Network `json:"network"`
+}
+
+// network/insert and network/update request
+type saveNetworkRequest struct {
+	requestHeader
+	NetworkId uint64        `json:"networkId,omitempty"`
+	Network   *core.Network `json:"network"`
+}
+
+// command/list request
+type listCommandRequest struct {
+	requestHeader
+	deviceAuth
+	Start     *core.Timestamp `json:"start,omitempty"`
+	End       *core.Timestamp `json:"end,omitempty"`
+	Command   string          `json:"command,omitempty"`
+	Status    string          `json:"status,omitempty"`
+	SortField string          `json:"sortField,omitempty"`
+	SortOrder string          `json:"sortOrder,omitempty"`
+	Take      int             `json:"take,omitempty"`
+	Skip      int             `json:"skip,omitempty"`
+}
+
+// command/list response
+type listCommandResponse struct {
+	responseStatus
+	Commands []core.Command `json:"commands"`
+}
+
+// notification/list request
+type listNotificationRequest struct {
+	requestHeader
+	deviceAuth
+	Start        *core.Timestamp `json:"start,omitempty"`
+	End          *core.Timestamp `json:"end,omitempty"`
+	Notification string          `json:"notification,omitempty"`
+	SortField    string          `json:"sortField,omitempty"`
+	SortOrder    string          `json:"sortOrder,omitempty"`
+	Take         int             `json:"take,omitempty"`
+	Skip         int             `json:"skip,omitempty"`
+}
+
+// notification/list response
+type listNotificationResponse struct {
+	responseStatus
+	Notifications []core.Notification `json:"notifications"`
+}
+
+// optional timestamp, nil for zero
+func optionalTimestamp(ts core.Timestamp) *core.Timestamp {
+	if ts.IsZero() {
+		return nil
+	}
+	return &ts
+}
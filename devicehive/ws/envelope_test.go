@@ -0,0 +1,184 @@
+package ws
+
+import (
+	"encoding/json"
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"testing"
+)
+
+// typical server responses
+var (
+	testInfoResponse = []byte(`{"action":"server/info","requestId":12345,"status":"success",
+"info":{"apiVersion":"2.0.0","serverTimestamp":"2016-03-01T10:20:30.123456",
+"webSocketServerUrl":"ws://localhost:8080/dh/websocket"}}`)
+
+	testNotificationResponse = []byte(`{"action":"notification/insert","requestId":12346,"status":"success",
+"notification":{"id":9007199254740993,"timestamp":"2016-03-01T10:20:30.123456"}}`)
+
+	testCommandInsertMessage = []byte(`{"action":"command/insert","deviceGuid":"go-test-dev",
+"subscriptionId":"0b6ba4a5-45d1-4e1c-9ab4-7b2b0d3f9a11",
+"command":{"id":100500,"timestamp":"2016-03-01T10:20:30.123456","userId":1,
+"command":"hello","parameters":{"a":1,"b":[1,2,3],"c":{"x":"y"}},"lifetime":60}}`)
+)
+
+// check two-phase decoding of the response
+func TestEnvelopeResponse(t *testing.T) {
+	var header messageHeader
+	if err := json.Unmarshal(testNotificationResponse, &header); err != nil {
+		t.Fatalf("failed to parse header: %s", err)
+	}
+	if id, ok := header.requestId(); !ok || id != 12346 {
+		t.Errorf("unexpected request id: %d (%t)", id, ok)
+	}
+
+	task := &Task{response: testNotificationResponse}
+	response := &insertNotificationResponse{Notification: &core.Notification{}}
+	if err := task.Decode(response); err != nil {
+		t.Fatalf("failed to parse response: %s", err)
+	}
+	if err := response.check(); err != nil {
+		t.Errorf("unexpected status: %s", err)
+	}
+	if response.Notification.Id != 9007199254740993 {
+		t.Errorf("unexpected notification id: %d", response.Notification.Id)
+	}
+}
+
+// check asynchronous message decoding
+func TestEnvelopeCommandInsert(t *testing.T) {
+	var header messageHeader
+	if err := json.Unmarshal(testCommandInsertMessage, &header); err != nil {
+		t.Fatalf("failed to parse header: %s", err)
+	}
+	if _, ok := header.requestId(); ok {
+		t.Errorf("no request id expected")
+	}
+	if header.Action != "command/insert" {
+		t.Errorf("unexpected action: %q", header.Action)
+	}
+
+	msg := commandInsertMessage{Command: &core.Command{}}
+	if err := json.Unmarshal(testCommandInsertMessage, &msg); err != nil {
+		t.Fatalf("failed to parse message: %s", err)
+	}
+	if msg.DeviceId != "go-test-dev" || msg.Command.Id != 100500 || msg.Command.Name != "hello" {
+		t.Errorf("unexpected message: %+v, %s", msg, msg.Command)
+	}
+}
+
+// check the request formatting
+func TestEnvelopeRequest(t *testing.T) {
+	device := &core.Device{Id: "go-test-dev", Key: "go-test-key"}
+	task := &Task{request: &subscribeCommandRequest{
+		requestHeader: requestHeader{Action: "command/subscribe", RequestId: 1},
+		deviceAuth:    newDeviceAuth(device)}}
+
+	buf, err := task.Format()
+	if err != nil {
+		t.Fatalf("failed to format request: %s", err)
+	}
+	defer releaseBuffer(buf)
+
+	expected := `{"action":"command/subscribe","requestId":1,"deviceId":"go-test-dev","deviceKey":"go-test-key"}`
+	if buf.String() != expected {
+		t.Errorf("unexpected request:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
+
+// generic decoding: the whole message into a map, then re-encode the payload
+func legacyDecode(body []byte, key string, v interface{}) error {
+	var msg map[string]interface{}
+	if err := core.UnmarshalNumber(body, &msg); err != nil {
+		return err
+	}
+	data, err := json.Marshal(msg[key])
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// typed decoding: header first, then the payload envelope
+func envelopeDecode(body []byte, response interface{}) error {
+	var header messageHeader
+	if err := json.Unmarshal(body, &header); err != nil {
+		return err
+	}
+	return (&Task{response: body}).Decode(response)
+}
+
+func BenchmarkDecodeInfoLegacy(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		info := &core.ServerInfo{}
+		if err := legacyDecode(testInfoResponse, "info", info); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeInfoEnvelope(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		response := &serverInfoResponse{Info: &core.ServerInfo{}}
+		if err := envelopeDecode(testInfoResponse, response); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeCommandLegacy(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		command := &core.Command{}
+		if err := legacyDecode(testCommandInsertMessage, "command", command); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeCommandEnvelope(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		msg := &commandInsertMessage{Command: &core.Command{}}
+		if err := envelopeDecode(testCommandInsertMessage, msg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// test notification to send
+var testNotification = &core.Notification{Name: "hello",
+	Parameters: map[string]interface{}{"a": 1, "b": "text"}}
+
+func BenchmarkEncodeNotificationLegacy(b *testing.B) {
+	b.ReportAllocs()
+	device := &core.Device{Id: "go-test-dev", Key: "go-test-key"}
+	for i := 0; i < b.N; i++ {
+		msg := map[string]interface{}{
+			"action":       "notification/insert",
+			"requestId":    uint64(i),
+			"deviceId":     device.Id,
+			"deviceKey":    device.Key,
+			"notification": testNotification}
+		if _, err := json.Marshal(msg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodeNotificationEnvelope(b *testing.B) {
+	b.ReportAllocs()
+	device := &core.Device{Id: "go-test-dev", Key: "go-test-key"}
+	for i := 0; i < b.N; i++ {
+		task := &Task{request: &insertNotificationRequest{
+			requestHeader: requestHeader{Action: "notification/insert", RequestId: uint64(i)},
+			deviceAuth:    newDeviceAuth(device),
+			Notification:  testNotification}}
+		buf, err := task.Format()
+		if err != nil {
+			b.Fatal(err)
+		}
+		releaseBuffer(buf)
+	}
+}
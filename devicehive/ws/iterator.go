@@ -0,0 +1,38 @@
+package ws
+
+import (
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"time"
+)
+
+// IterateCommands() creates the command history iterator of the device.
+// Filter's Take and Skip limit the whole sequence, not a page.
+// Zero pageSize means core.DefaultPageSize, timeout is used for each page request.
+func (service *Service) IterateCommands(device *core.Device, filter *core.CommandFilter, pageSize int, timeout time.Duration) *core.CommandIterator {
+	f := core.CommandFilter{}
+	if filter != nil {
+		f = *filter
+	}
+	fetch := func(take, skip int, timeout time.Duration) ([]core.Command, error) {
+		page := f // copy
+		page.Take, page.Skip = take, skip
+		return service.GetCommandList(device, &page, timeout)
+	}
+	return &core.CommandIterator{Pager: core.NewPager(fetch, f.Take, f.Skip, pageSize, timeout)}
+}
+
+// IterateNotifications() creates the notification history iterator of the device.
+// Filter's Take and Skip limit the whole sequence, not a page.
+// Zero pageSize means core.DefaultPageSize, timeout is used for each page request.
+func (service *Service) IterateNotifications(device *core.Device, filter *core.NotificationFilter, pageSize int, timeout time.Duration) *core.NotificationIterator {
+	f := core.NotificationFilter{}
+	if filter != nil {
+		f = *filter
+	}
+	fetch := func(take, skip int, timeout time.Duration) ([]core.Notification, error) {
+		page := f // copy
+		page.Take, page.Skip = take, skip
+		return service.GetNotificationList(device, &page, timeout)
+	}
+	return &core.NotificationIterator{Pager: core.NewPager(fetch, f.Take, f.Skip, pageSize, timeout)}
+}
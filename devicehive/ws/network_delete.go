@@ -0,0 +1,65 @@
+package ws
+
+import (
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"time"
+)
+
+// Prepare DeleteNetwork task
+func (service *Service) prepareDeleteNetwork(network *core.Network) (task *Task, err error) {
+	err = service.requireClient("network/delete")
+	if err != nil {
+		return
+	}
+
+	task, err = service.newTask()
+	if err != nil {
+		return
+	}
+
+	task.request = &networkIdRequest{
+		requestHeader: requestHeader{Action: "network/delete", RequestId: task.id},
+		NetworkId:     network.Id}
+
+	return
+}
+
+// Process DeleteNetwork task
+func (service *Service) processDeleteNetwork(task *Task) (err error) {
+	// check response status
+	err = task.CheckStatus()
+	if err != nil {
+		service.logger.Warnf("WS: bad /network/delete status (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// DeleteNetwork() function deletes the network.
+func (service *Service) DeleteNetwork(network *core.Network, timeout time.Duration) (err error) {
+	task, err := service.prepareDeleteNetwork(network)
+	if err != nil {
+		service.logger.Warnf("WS: failed to prepare /network/delete task (error: %s)", err)
+		return
+	}
+
+	// add to the TX pipeline
+	service.send(task)
+
+	select {
+	case <-time.After(timeout):
+		service.cancelTask(task)
+		service.logger.Warnf("WS: failed to wait %s for /network/delete task", timeout)
+		err = core.ErrTimeout
+
+	case <-task.done:
+		err = service.processDeleteNetwork(task)
+		if err != nil {
+			service.logger.Warnf("WS: failed to process /network/delete task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
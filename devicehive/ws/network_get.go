@@ -0,0 +1,74 @@
+package ws
+
+import (
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"time"
+)
+
+// Prepare GetNetwork task
+func (service *Service) prepareGetNetwork(networkId uint64) (task *Task, err error) {
+	err = service.requireClient("network/get")
+	if err != nil {
+		return
+	}
+
+	task, err = service.newTask()
+	if err != nil {
+		return
+	}
+
+	task.request = &networkIdRequest{
+		requestHeader: requestHeader{Action: "network/get", RequestId: task.id},
+		NetworkId:     networkId}
+
+	return
+}
+
+// Process GetNetwork task
+func (service *Service) processGetNetwork(task *Task, network *core.Network) (err error) {
+	// parse response
+	response := &networkResponse{Network: network}
+	err = task.Decode(response)
+	if err != nil {
+		service.logger.Warnf("WS: failed to parse /network/get body (error: %s)", err)
+		return
+	}
+
+	// check response status
+	err = response.check()
+	if err != nil {
+		service.logger.Warnf("WS: bad /network/get status (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// GetNetwork() function gets the network information.
+func (service *Service) GetNetwork(networkId uint64, timeout time.Duration) (network *core.Network, err error) {
+	task, err := service.prepareGetNetwork(networkId)
+	if err != nil {
+		service.logger.Warnf("WS: failed to prepare /network/get task (error: %s)", err)
+		return
+	}
+
+	// add to the TX pipeline
+	service.send(task)
+
+	select {
+	case <-time.After(timeout):
+		service.cancelTask(task)
+		service.logger.Warnf("WS: failed to wait %s for /network/get task", timeout)
+		err = core.ErrTimeout
+
+	case <-task.done:
+		network = &core.Network{}
+		err = service.processGetNetwork(task, network)
+		if err != nil {
+			service.logger.Warnf("WS: failed to process /network/get task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,75 @@
+package ws
+
+import (
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"time"
+)
+
+// Prepare InsertNetwork task
+func (service *Service) prepareInsertNetwork(network *core.Network) (task *Task, err error) {
+	err = service.requireClient("network/insert")
+	if err != nil {
+		return
+	}
+
+	task, err = service.newTask()
+	if err != nil {
+		return
+	}
+
+	net_data := *network // shallow copy, only Id is changed
+	net_data.Id = 0      // do not put Id inside
+	task.request = &saveNetworkRequest{
+		requestHeader: requestHeader{Action: "network/insert", RequestId: task.id},
+		Network:       &net_data}
+
+	return
+}
+
+// Process InsertNetwork task
+func (service *Service) processInsertNetwork(task *Task, network *core.Network) (err error) {
+	// parse response
+	response := &networkResponse{Network: network}
+	err = task.Decode(response)
+	if err != nil {
+		service.logger.Warnf("WS: failed to parse /network/insert body (error: %s)", err)
+		return
+	}
+
+	// check response status
+	err = response.check()
+	if err != nil {
+		service.logger.Warnf("WS: bad /network/insert status (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// InsertNetwork() function inserts the network.
+func (service *Service) InsertNetwork(network *core.Network, timeout time.Duration) (err error) {
+	task, err := service.prepareInsertNetwork(network)
+	if err != nil {
+		service.logger.Warnf("WS: failed to prepare /network/insert task (error: %s)", err)
+		return
+	}
+
+	// add to the TX pipeline
+	service.send(task)
+
+	select {
+	case <-time.After(timeout):
+		service.cancelTask(task)
+		service.logger.Warnf("WS: failed to wait %s for /network/insert task", timeout)
+		err = core.ErrTimeout
+
+	case <-task.done:
+		err = service.processInsertNetwork(task, network)
+		if err != nil {
+			service.logger.Warnf("WS: failed to process /network/insert task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
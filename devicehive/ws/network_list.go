@@ -0,0 +1,87 @@
+package ws
+
+import (
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"time"
+)
+
+// Prepare GetNetworkList task
+func (service *Service) prepareGetNetworkList(filter *core.NetworkFilter) (task *Task, err error) {
+	err = service.requireClient("network/list")
+	if err != nil {
+		return
+	}
+
+	task, err = service.newTask()
+	if err != nil {
+		return
+	}
+
+	request := &listNetworkRequest{
+		requestHeader: requestHeader{Action: "network/list", RequestId: task.id}}
+	if filter != nil {
+		request.Name = filter.Name
+		request.NamePattern = filter.NamePattern
+		request.SortField = filter.SortField
+		request.SortOrder = filter.SortOrder
+		request.Take = filter.Take
+		request.Skip = filter.Skip
+	}
+
+	task.request = request
+	return
+}
+
+// Process GetNetworkList task
+func (service *Service) processGetNetworkList(task *Task) (networks []core.Network, err error) {
+	// parse response
+	response := &listNetworkResponse{}
+	err = task.Decode(response)
+	if err != nil {
+		service.logger.Warnf("WS: failed to parse /network/list body (error: %s)", err)
+		return
+	}
+
+	// check response status
+	err = response.check()
+	if err != nil {
+		service.logger.Warnf("WS: bad /network/list status (error: %s)", err)
+		return
+	}
+
+	networks = response.Networks
+	return
+}
+
+// GetNetworkList() function gets the network list.
+func (service *Service) GetNetworkList(take, skip int, timeout time.Duration) (networks []core.Network, err error) {
+	return service.GetNetworkListFilter(&core.NetworkFilter{Take: take, Skip: skip}, timeout)
+}
+
+// GetNetworkListFilter() function gets the network list using filter.
+func (service *Service) GetNetworkListFilter(filter *core.NetworkFilter, timeout time.Duration) (networks []core.Network, err error) {
+	task, err := service.prepareGetNetworkList(filter)
+	if err != nil {
+		service.logger.Warnf("WS: failed to prepare /network/list task (error: %s)", err)
+		return
+	}
+
+	// add to the TX pipeline
+	service.send(task)
+
+	select {
+	case <-time.After(timeout):
+		service.cancelTask(task)
+		service.logger.Warnf("WS: failed to wait %s for /network/list task", timeout)
+		err = core.ErrTimeout
+
+	case <-task.done:
+		networks, err = service.processGetNetworkList(task)
+		if err != nil {
+			service.logger.Warnf("WS: failed to process /network/list task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,68 @@
+package ws
+
+import (
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"time"
+)
+
+// Prepare UpdateNetwork task
+func (service *Service) prepareUpdateNetwork(network *core.Network) (task *Task, err error) {
+	err = service.requireClient("network/update")
+	if err != nil {
+		return
+	}
+
+	task, err = service.newTask()
+	if err != nil {
+		return
+	}
+
+	net_data := *network // shallow copy, only Id is changed
+	net_data.Id = 0      // do not put Id inside
+	task.request = &saveNetworkRequest{
+		requestHeader: requestHeader{Action: "network/update", RequestId: task.id},
+		NetworkId:     network.Id,
+		Network:       &net_data}
+
+	return
+}
+
+// Process UpdateNetwork task
+func (service *Service) processUpdateNetwork(task *Task) (err error) {
+	// check response status
+	err = task.CheckStatus()
+	if err != nil {
+		service.logger.Warnf("WS: bad /network/update status (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// UpdateNetwork() function updates the network.
+func (service *Service) UpdateNetwork(network *core.Network, timeout time.Duration) (err error) {
+	task, err := service.prepareUpdateNetwork(network)
+	if err != nil {
+		service.logger.Warnf("WS: failed to prepare /network/update task (error: %s)", err)
+		return
+	}
+
+	// add to the TX pipeline
+	service.send(task)
+
+	select {
+	case <-time.After(timeout):
+		service.cancelTask(task)
+		service.logger.Warnf("WS: failed to wait %s for /network/update task", timeout)
+		err = core.ErrTimeout
+
+	case <-task.done:
+		err = service.processUpdateNetwork(task)
+		if err != nil {
+			service.logger.Warnf("WS: failed to process /network/update task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
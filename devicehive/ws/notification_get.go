@@ -0,0 +1,71 @@
+package ws
+
+import (
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"time"
+)
+
+// Prepare GetNotification task
+func (service *Service) prepareGetNotification(device *core.Device, notificationId uint64) (task *Task, err error) {
+	task, err = service.newTask()
+	if err != nil {
+		return
+	}
+
+	task.request = &getNotificationRequest{
+		requestHeader:  requestHeader{Action: "notification/get", RequestId: task.id},
+		deviceAuth:     newDeviceAuth(device),
+		NotificationId: notificationId}
+
+	return
+}
+
+// Process GetNotification task
+func (service *Service) processGetNotification(task *Task, notification *core.Notification) (err error) {
+	// parse response
+	response := &getNotificationResponse{Notification: notification}
+	err = task.Decode(response)
+	if err != nil {
+		service.logger.Warnf("WS: failed to parse /notification/get body (error: %s)", err)
+		return
+	}
+
+	// check response status
+	err = response.check()
+	if err != nil {
+		service.logger.Warnf("WS: bad /notification/get status (error: %s)", err)
+		return
+	}
+
+	return
+}
+
+// GetNotification() function gets the notification.
+func (service *Service) GetNotification(device *core.Device, notificationId uint64, timeout time.Duration) (notification *core.Notification, err error) {
+	task, err := service.prepareGetNotification(device, notificationId)
+	if err != nil {
+		service.logger.Warnf("WS: failed to prepare /notification/get task (error: %s)", err)
+		return
+	}
+
+	// add to the TX pipeline
+	service.send(task)
+
+	select {
+	case <-time.After(timeout):
+		service.cancelTask(task)
+		service.logger.Warnf("WS: failed to wait %s for /notification/get task", timeout)
+		err = core.ErrTimeout
+
+	case <-task.done:
+		notification = &core.Notification{}
+		err = service.processGetNotification(task, notification)
+		if err != nil {
+			service.logger.Warnf("WS: failed to process /notification/get task (error: %s)", err)
+			notification = nil
+			return
+		}
+	}
+
+	return
+}
@@ -1,42 +1,40 @@
 package ws
 
 import (
-	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"time"
 )
 
 // Prepare InsertNotification task
 func (service *Service) prepareInsertNotification(device *core.Device, notification *core.Notification) (task *Task, err error) {
-	task = service.newTask()
-	task.dataToSend = map[string]interface{}{
-		"action":    "notification/insert",
-		"requestId": task.id}
-
-	// prepare authorization
-	task.prepareAuthorization(device)
+	task, err = service.newTask()
+	if err != nil {
+		return
+	}
 
-	ntf_data := core.Notification{Name: notification.Name,
-		Parameters: notification.Parameters}
-	task.dataToSend["notification"] = ntf_data
+	task.request = &insertNotificationRequest{
+		requestHeader: requestHeader{Action: "notification/insert", RequestId: task.id},
+		deviceAuth:    newDeviceAuth(device),
+		Notification: &core.Notification{Name: notification.Name,
+			Parameters: notification.Parameters}}
 
 	return
 }
 
 // Process InsertNotification task
 func (service *Service) processInsertNotification(task *Task, notification *core.Notification) (err error) {
-	// check response status
-	err = task.CheckStatus()
+	// parse response
+	response := &insertNotificationResponse{Notification: notification}
+	err = task.Decode(response)
 	if err != nil {
-		log.Warnf("WS: bad /notification/insert status (error: %s)", err)
+		service.logger.Warnf("WS: failed to parse /notification/insert response (error: %s)", err)
 		return
 	}
 
-	// parse response
-	err = notification.AssignJSON(task.dataRecved["notification"])
+	// check response status
+	err = response.check()
 	if err != nil {
-		log.Warnf("WS: failed to parse /notification/insert response (error: %s)", err)
+		service.logger.Warnf("WS: bad /notification/insert status (error: %s)", err)
 		return
 	}
 
@@ -45,27 +43,69 @@ func (service *Service) processInsertNotification(task *Task, notification *core
 
 // InsertNotification() function inserts the notification.
 func (service *Service) InsertNotification(device *core.Device, notification *core.Notification, timeout time.Duration) (err error) {
+	// client-side validation
+	err = core.Validate(notification)
+	if err != nil {
+		service.logger.Warnf("WS: invalid notification (error: %s)", err)
+		return
+	}
+
 	task, err := service.prepareInsertNotification(device, notification)
 	if err != nil {
-		log.Warnf("WS: failed to prepare /notification/insert task (error: %s)", err)
+		service.logger.Warnf("WS: failed to prepare /notification/insert task (error: %s)", err)
 		return
 	}
 
 	// add to the TX pipeline
-	service.tx <- task
+	service.send(task)
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("WS: failed to wait %s for /notification/insert task", timeout)
-		err = fmt.Errorf("timed out")
+		service.cancelTask(task)
+		service.logger.Warnf("WS: failed to wait %s for /notification/insert task", timeout)
+		err = core.ErrTimeout
 
 	case <-task.done:
 		err = service.processInsertNotification(task, notification)
 		if err != nil {
-			log.Warnf("WS: failed to process /notification/insert task (error: %s)", err)
+			service.logger.Warnf("WS: failed to process /notification/insert task (error: %s)", err)
 			return
 		}
 	}
 
 	return
 }
+
+// InsertNotificationAsync() function inserts the notification without
+// waiting for the response. The notification is updated when the returned
+// future is completed, callback [optional] is called on completion.
+func (service *Service) InsertNotificationAsync(device *core.Device, notification *core.Notification, callback func(err error)) *Future {
+	prepare := func() (task *Task, err error) {
+		// client-side validation
+		err = core.Validate(notification)
+		if err != nil {
+			service.logger.Warnf("WS: invalid notification (error: %s)", err)
+			return
+		}
+
+		task, err = service.prepareInsertNotification(device, notification)
+		if err != nil {
+			service.logger.Warnf("WS: failed to prepare /notification/insert task (error: %s)", err)
+			return
+		}
+
+		return
+	}
+
+	process := func(task *Task) (err error) {
+		err = service.processInsertNotification(task, notification)
+		if err != nil {
+			service.logger.Warnf("WS: failed to process /notification/insert task (error: %s)", err)
+			return
+		}
+
+		return
+	}
+
+	return service.submitAsync("notification/insert", prepare, process, callback)
+}
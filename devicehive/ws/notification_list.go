@@ -0,0 +1,80 @@
+package ws
+
+import (
+	"github.com/devicehive/devicehive-go/devicehive/core"
+	"time"
+)
+
+// Prepare GetNotificationList task
+func (service *Service) prepareGetNotificationList(device *core.Device, filter *core.NotificationFilter) (task *Task, err error) {
+	task, err = service.newTask()
+	if err != nil {
+		return
+	}
+
+	request := &listNotificationRequest{
+		requestHeader: requestHeader{Action: "notification/list", RequestId: task.id},
+		deviceAuth:    newDeviceAuth(device)}
+	if filter != nil {
+		request.Start = optionalTimestamp(filter.Start)
+		request.End = optionalTimestamp(filter.End)
+		request.Notification = filter.Notification
+		request.SortField = filter.SortField
+		request.SortOrder = filter.SortOrder
+		request.Take = filter.Take
+		request.Skip = filter.Skip
+	}
+
+	task.request = request
+	return
+}
+
+// Process GetNotificationList task
+func (service *Service) processGetNotificationList(task *Task) (notifications []core.Notification, err error) {
+	// parse response
+	response := &listNotificationResponse{}
+	err = task.Decode(response)
+	if err != nil {
+		service.logger.Warnf("WS: failed to parse /notification/list body (error: %s)", err)
+		return
+	}
+
+	// check response status
+	err = response.check()
+	if err != nil {
+		service.logger.Warnf("WS: bad /notification/list status (error: %s)", err)
+		return
+	}
+
+	notifications = response.Notifications
+	return
+}
+
+// GetNotificationList() function gets the notification history of the device.
+// The server should support notification/list action on the device connection.
+func (service *Service) GetNotificationList(device *core.Device, filter *core.NotificationFilter, timeout time.Duration) (notifications []core.Notification, err error) {
+	task, err := service.prepareGetNotificationList(device, filter)
+	if err != nil {
+		service.logger.Warnf("WS: failed to prepare /notification/list task (error: %s)", err)
+		return
+	}
+
+	// add to the TX pipeline
+	service.send(task)
+
+	select {
+	case <-time.After(timeout):
+		service.cancelTask(task)
+		service.logger.Warnf("WS: failed to wait %s for /notification/list task", timeout)
+		err = core.ErrTimeout
+
+	case <-task.done:
+		notifications, err = service.processGetNotificationList(task)
+		if err != nil {
+			service.logger.Warnf("WS: failed to process /notification/list task (error: %s)", err)
+			return
+		}
+	}
+
+	return
+}
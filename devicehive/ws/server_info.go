@@ -1,34 +1,36 @@
 package ws
 
 import (
-	"fmt"
 	"github.com/devicehive/devicehive-go/devicehive/core"
-	"github.com/devicehive/devicehive-go/devicehive/log"
 	"time"
 )
 
 // Prepare GetServerInfo task
 func (service *Service) prepareGetServerInfo() (task *Task, err error) {
-	task = service.newTask()
-	task.dataToSend = map[string]interface{}{
-		"action":    "server/info",
-		"requestId": task.id}
+	task, err = service.newTask()
+	if err != nil {
+		return
+	}
+
+	task.request = &serverInfoRequest{
+		requestHeader: requestHeader{Action: "server/info", RequestId: task.id}}
 	return
 }
 
 // Process GetServerInfo task
 func (service *Service) processGetServerInfo(task *Task, info *core.ServerInfo) (err error) {
-	// check response status
-	err = task.CheckStatus()
+	// parse response
+	response := &serverInfoResponse{Info: info}
+	err = task.Decode(response)
 	if err != nil {
-		log.Warnf("WS: bad /info status (error: %s)", err)
+		service.logger.Warnf("WS: failed to parse /info body (error: %s)", err)
 		return
 	}
 
-	// parse response
-	err = info.AssignJSON(task.dataRecved["info"])
+	// check response status
+	err = response.check()
 	if err != nil {
-		log.Warnf("WS: failed to parse /info body (error: %s)", err)
+		service.logger.Warnf("WS: bad /info status (error: %s)", err)
 		return
 	}
 
@@ -39,23 +41,24 @@ func (service *Service) processGetServerInfo(task *Task, info *core.ServerInfo)
 func (service *Service) GetServerInfo(timeout time.Duration) (info *core.ServerInfo, err error) {
 	task, err := service.prepareGetServerInfo()
 	if err != nil {
-		log.Warnf("WS: failed to prepare /info task (error: %s)", err)
+		service.logger.Warnf("WS: failed to prepare /info task (error: %s)", err)
 		return
 	}
 
 	// add to the TX pipeline
-	service.tx <- task
+	service.send(task)
 
 	select {
 	case <-time.After(timeout):
-		log.Warnf("WS: failed to wait %s for /info task", timeout)
-		err = fmt.Errorf("timed out")
+		service.cancelTask(task)
+		service.logger.Warnf("WS: failed to wait %s for /info task", timeout)
+		err = core.ErrTimeout
 
 	case <-task.done:
 		info = &core.ServerInfo{}
 		err = service.processGetServerInfo(task, info)
 		if err != nil {
-			log.Warnf("WS: failed to process /info task (error: %s)", err)
+			service.logger.Warnf("WS: failed to process /info task (error: %s)", err)
 			return
 		}
 	}
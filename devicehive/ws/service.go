@@ -2,6 +2,7 @@ package ws
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/devicehive/devicehive-go/devicehive/core"
 	"github.com/devicehive/devicehive-go/devicehive/log"
 	"github.com/gorilla/websocket"
@@ -14,27 +15,54 @@ import (
 	"time"
 )
 
-// TODO: support /client websocket endpoint
+// Websocket endpoints.
+const (
+	DeviceEndpoint = "/device"
+	ClientEndpoint = "/client"
+)
+
+// Error returned by calls not supported by the connected endpoint.
+// For example, network management requires ClientEndpoint.
+var ErrNotSupported = errors.New("not supported by the endpoint")
 
 // Websocket service representation.
 type Service struct {
 	// Base URL.
 	baseUrl *url.URL
 
+	// Endpoint connected to, DeviceEndpoint or ClientEndpoint.
+	endpoint string
+
 	// Access key, might be empty - means no access key authorizathion used.
 	accessKey string
 
 	// Websocket connection
-	conn *websocket.Conn
+	conn Conn
+
+	// Logger with "transport" field
+	logger *log.Entry
 
 	// active task set
 	taskLock   sync.Mutex
 	lastTaskId uint64
 	tasks      map[uint64]*Task
+	maxPending int
+
+	// lost connection, protected by taskLock
+	err  *ConnectionClosedError
+	done chan struct{}
 
 	// command listeners
 	commandListenerLock sync.Mutex
-	commandListeners    map[string]*core.CommandListener
+	commandListeners    map[string]*commandListener
+
+	// command update waiters
+	commandWaiterLock sync.Mutex
+	commandWaiters    map[uint64]chan *core.Command
+	commandUpdates    []*core.Command // recent updates without waiter
+
+	// asynchronous requests
+	async asyncQueue
 
 	// transmitter
 	tx chan *Task
@@ -42,23 +70,46 @@ type Service struct {
 
 // Get string representation of a Websocket service.
 func (s *Service) String() string {
-	return fmt.Sprintf("WebsocketService{baseUrl:%q, accessKey:%q}", s.baseUrl, s.accessKey)
+	return fmt.Sprintf("WebsocketService{baseUrl:%q, endpoint:%q, accessKey:%q}", s.baseUrl, s.endpoint, core.RedactSecret(s.accessKey))
 }
 
-func (service *Service) GetCommand(device *core.Device, commandId uint64, timeout time.Duration) (command *core.Command, err error) {
-	return &core.Command{}, nil
+// installed command listener
+// it could be closed from any thread while RX thread delivers a command,
+// it's closed by the one who removed it from the listener set
+type commandListener struct {
+	*core.CommandListener
+	lock   sync.Mutex    // held while delivering
+	stop   chan struct{} // interrupts blocked delivery
+	closed bool
 }
 
-func (service *Service) InsertCommand(device *core.Device, command *core.Command, timeout time.Duration) (err error) {
-	return nil
+// deliver the command, blocks while the listener is full
+// returns false if the listener is closed
+func (l *commandListener) deliver(command *core.Command) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.closed {
+		return false
+	}
+	select {
+	case l.C <- command:
+		return true
+	case <-l.stop:
+		return false
+	}
 }
 
-func (service *Service) GetNotification(device *core.Device, notificationId uint64, timeout time.Duration) (notification *core.Notification, err error) {
-	return &core.Notification{}, nil
+// close the listener channel
+func (l *commandListener) close() {
+	close(l.stop)
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.closed = true
+	close(l.C)
 }
 
 // find command listener
-func (service *Service) findCommandListener(deviceId string) *core.CommandListener {
+func (service *Service) findCommandListener(deviceId string) *commandListener {
 	service.commandListenerLock.Lock()
 	defer service.commandListenerLock.Unlock()
 	listener := service.commandListeners[deviceId]
@@ -66,10 +117,33 @@ func (service *Service) findCommandListener(deviceId string) *core.CommandListen
 }
 
 // insert new command listener
-func (service *Service) insertCommandListener(deviceId string, listener *core.CommandListener) {
+// previous listener of the device is closed
+// listener is closed immediately if connection is lost
+func (service *Service) insertCommandListener(deviceId string, listener *core.CommandListener) *commandListener {
+	l := &commandListener{CommandListener: listener, stop: make(chan struct{})}
+
+	service.commandListenerLock.Lock()
+	defer service.commandListenerLock.Unlock()
+	if service.Err() != nil {
+		l.close()
+		return l
+	}
+	if old := service.commandListeners[deviceId]; old != nil {
+		service.logger.Debugf("WS: command listener of %q is replaced, previous one is closed", deviceId)
+		old.close()
+	}
+	service.commandListeners[deviceId] = l
+	return l
+}
+
+// remove and close command listener, if it's still installed
+func (service *Service) dropCommandListener(deviceId string, listener *commandListener) {
 	service.commandListenerLock.Lock()
 	defer service.commandListenerLock.Unlock()
-	service.commandListeners[deviceId] = listener
+	if service.commandListeners[deviceId] == listener {
+		delete(service.commandListeners, deviceId)
+		listener.close()
+	}
 }
 
 // remove command listener
@@ -79,10 +153,42 @@ func (service *Service) removeCommandListener(deviceId string) {
 	delete(service.commandListeners, deviceId)
 }
 
+// check the endpoint is /client
+func (service *Service) requireClient(action string) error {
+	if service.endpoint != ClientEndpoint {
+		return fmt.Errorf("%w: %s requires %s endpoint", ErrNotSupported, action, ClientEndpoint)
+	}
+	return nil
+}
+
 // NewService creates new Websocket /device service.
 func NewService(baseUrl, accessKey string) (service *Service, err error) {
-	log.Tracef("WS: creating service (url:%q)", baseUrl)
-	service = &Service{accessKey: accessKey}
+	return NewServiceDial(baseUrl, accessKey, DefaultDial)
+}
+
+// NewServiceDial creates new Websocket /device service.
+// Custom dial function is used to establish the connection.
+func NewServiceDial(baseUrl, accessKey string, dial DialFunc) (service *Service, err error) {
+	return newServiceDial(baseUrl, DeviceEndpoint, accessKey, dial)
+}
+
+// NewClientService creates new Websocket /client service.
+// Network management is supported by /client endpoint only.
+func NewClientService(baseUrl, accessKey string) (service *Service, err error) {
+	return NewClientServiceDial(baseUrl, accessKey, DefaultDial)
+}
+
+// NewClientServiceDial creates new Websocket /client service.
+// Custom dial function is used to establish the connection.
+func NewClientServiceDial(baseUrl, accessKey string, dial DialFunc) (service *Service, err error) {
+	return newServiceDial(baseUrl, ClientEndpoint, accessKey, dial)
+}
+
+// create new Websocket service connected to the endpoint
+func newServiceDial(baseUrl, endpoint, accessKey string, dial DialFunc) (service *Service, err error) {
+	service = &Service{accessKey: accessKey, endpoint: endpoint,
+		logger: log.NewEntry(nil, log.WS, log.Transport("ws"))}
+	service.logger.Tracef("WS: creating service (url:%q, endpoint:%q)", baseUrl, endpoint)
 
 	// remove trailing slashes from URL
 	for len(baseUrl) > 1 && strings.HasSuffix(baseUrl, "/") {
@@ -92,63 +198,90 @@ func NewService(baseUrl, accessKey string) (service *Service, err error) {
 	// parse URL
 	service.baseUrl, err = url.Parse(baseUrl)
 	if err != nil {
-		log.Warnf("WS: failed to parse URL (error: %s)", err)
+		service.logger.Warnf("WS: failed to parse URL (error: %s)", err)
 		service = nil
 		return
 	}
 
-	// connect to /device endpoint
-	ws_url := fmt.Sprintf("%s/device", service.baseUrl)
+	// connect to the endpoint
+	ws_url := fmt.Sprintf("%s%s", service.baseUrl, endpoint)
 	headers := http.Header{}
 	headers.Add("Origin", "http://localhost/")
 	if len(service.accessKey) != 0 {
 		headers.Add("Authorization", "Bearer "+service.accessKey)
 	}
-	service.conn, _, err = websocket.DefaultDialer.Dial(ws_url, headers)
+	service.conn, err = dial(ws_url, headers)
 	if err != nil {
-		log.Warnf("WS: failed to dial (error: %s)", err)
+		service.logger.Warnf("WS: failed to dial (error: %s)", err)
 		service = nil
 		return
 	}
 
 	// set of active tasks
 	service.tasks = make(map[uint64]*Task)
+	service.maxPending = DefaultMaxPending
+	service.done = make(chan struct{})
 
 	// command listeners
-	service.commandListeners = make(map[string]*core.CommandListener)
+	service.commandListeners = make(map[string]*commandListener)
+	service.commandWaiters = make(map[uint64]chan *core.Command)
+
+	// asynchronous requests
+	service.async.init()
 
 	// create TX channel
 	service.tx = make(chan *Task)
 
-	// and start RX/TX threads
+	// and start RX/TX and completion threads
 	go service.doRX()
 	go service.doTX()
+	go service.doComplete()
 
 	return
 }
 
+// SetLogger changes the logging backend.
+// nil means log.StdLogger.
+func (service *Service) SetLogger(logger log.Logger) {
+	service.logger.SetLogger(logger)
+}
+
 // TX thread
+// stopped when the connection is lost
 func (service *Service) doTX() {
 	for {
 		select {
+		case <-service.done:
+			service.logger.Infof("WS: TX thread stopped (connection lost)")
+			return
+
 		case task, ok := <-service.tx:
 			if !ok || task == nil {
-				log.Infof("WS: TX thread stopped")
+				service.logger.Infof("WS: TX thread stopped")
 				service.conn.Close() // TODO: send Close frame?
 				return
 			}
 
-			body, err := task.Format()
+			if service.Err() != nil {
+				service.taskLogger(task).Debugf("WS: connection lost, message dropped")
+				continue // task is already failed
+			}
+
+			buf, err := task.Format()
 			if err != nil {
-				log.Warnf("WS: failed to format message (error: %s)", err)
+				service.taskLogger(task).Warnf("WS: failed to format message (error: %s)", err)
 				continue // TODO: return?
 			}
 
-			log.Tracef("WS: sending message: %s", string(body))
-			err = service.conn.WriteMessage(websocket.TextMessage, body)
+			if service.logger.Enabled(log.TRACE) {
+				service.taskLogger(task).Tracef("WS: sending message: %s", core.RedactJSON(buf.Bytes()))
+			}
+			err = service.conn.WriteMessage(websocket.TextMessage, buf.Bytes())
+			releaseBuffer(buf)
 			if err != nil {
-				log.Warnf("WS: failed to send message (error: %s)", err)
-				continue // TODO: return?
+				service.taskLogger(task).Warnf("WS: failed to send message (error: %s)", err)
+				service.closeWith(err)
+				return
 			}
 
 			//			case <-service.pingTimer.C:
@@ -165,96 +298,87 @@ func (service *Service) doRX() {
 	for {
 		_, body, err := service.conn.ReadMessage()
 		if err != nil {
-			log.Warnf("WS: failed to receive message (error: %s)", err)
+			service.logger.Warnf("WS: failed to receive message (error: %s)", err)
+			service.closeWith(err)
+			service.closeCommandListeners()
 			return
 		}
-		log.Tracef("WS: received message: %s", string(body))
 
-		// parse JSON
-		var msg map[string]interface{}
-		err = json.Unmarshal(body, &msg)
+		// parse message header only, the rest is decoded later
+		var header messageHeader
+		err = json.Unmarshal(body, &header)
 		if err != nil {
-			log.Warnf("WS: failed to parse JSON (error: %s), ignored", err)
+			service.logger.Warnf("WS: failed to parse JSON (error: %s), ignored", err)
 			continue
 		}
 
-		// log.Tracef("WS: parsed JSON %+v", msg)
-		if v, ok := msg["requestId"]; ok {
-			id := safeUint64(v)
+		if id, ok := header.requestId(); ok {
 			task := service.takeTask(id)
 			if task != nil {
-				task.dataRecved = msg
-				task.done <- task
+				if service.logger.Enabled(log.TRACE) {
+					service.taskLogger(task).
+						With(log.Duration(time.Since(task.started))).
+						Tracef("WS: received message: %s", core.RedactJSON(body))
+				}
+				task.response = body
+				if task.complete != nil {
+					task.complete(task)
+				} else {
+					task.done <- task
+				}
+				continue
+			}
+			if service.isIssuedTask(id) {
+				service.logger.With(log.RequestId(id), log.Action(header.Action)).
+					Debugf("WS: late response discarded: %s", core.RedactJSON(body))
 				continue
 			}
 		}
 
-		if v, ok := msg["action"]; ok {
-			action := safeString(v)
-			service.handleAction(action, msg)
+		if service.logger.Enabled(log.TRACE) {
+			service.logger.With(log.Action(header.Action)).
+				Tracef("WS: received message: %s", core.RedactJSON(body))
+		}
+		if header.Action != "" {
+			service.handleAction(header.Action, body)
 			continue
 		}
 
-		log.Warnf("WS: unexpected message: %s, ignored", string(body))
+		service.logger.Warnf("WS: unexpected message: %s, ignored", core.RedactJSON(body))
 	}
 }
 
 // handle asynchronous actions
-func (service *Service) handleAction(action string, data map[string]interface{}) {
+func (service *Service) handleAction(action string, body []byte) {
 	switch action {
 	case "command/insert":
-		if v, ok := data["deviceGuid"]; ok {
-			deviceId := safeString(v)
-			listener := service.findCommandListener(deviceId)
-			if listener != nil {
-				command := &core.Command{}
-				err := command.AssignJSON(data["command"])
-				if err != nil {
-					log.Warnf("WS: failed to parse commnad/insert body (error: %s)", err)
-					return
-				}
-				listener.C <- command
-			} else {
-				log.Warnf("WS: no command listener installed, %v ignored", data)
+		msg := commandInsertMessage{Command: &core.Command{}}
+		err := json.Unmarshal(body, &msg)
+		if err != nil {
+			service.logger.Warnf("WS: failed to parse command/insert body (error: %s)", err)
+			return
+		}
+		if msg.DeviceId == "" {
+			service.logger.Warnf("WS: no deviceId provided for command/insert, %s ignored", core.RedactJSON(body))
+			return
+		}
+		listener := service.findCommandListener(msg.DeviceId)
+		if listener != nil {
+			if !listener.deliver(msg.Command) {
+				service.logger.Debugf("WS: command listener is closed, %s ignored", core.RedactJSON(body))
 			}
 		} else {
-			log.Warnf("WS: no deviceId provided for command/insert, %v ignored", data)
+			service.logger.Warnf("WS: no command listener installed, %s ignored", core.RedactJSON(body))
 		}
+	case "command/update":
+		msg := commandUpdateMessage{Command: &core.Command{}}
+		err := json.Unmarshal(body, &msg)
+		if err != nil {
+			service.logger.Warnf("WS: failed to parse command/update body (error: %s)", err)
+			return
+		}
+		service.deliverCommandUpdate(msg.Command)
 	default:
-		log.Warnf("WS: unexpected action received: %v, ignored", data)
-	}
-}
-
-// get uint64
-func safeUint64(v interface{}) uint64 {
-	switch x := v.(type) {
-	case float64:
-		return uint64(x)
-
-	case uint64:
-		return x
-
-	// TODO: add other types
-
-	default:
-		log.Warnf("WS: unable to convert %v to uint64", v)
-		return 0
-	}
-}
-
-// get string
-func safeString(v interface{}) string {
-	switch x := v.(type) {
-	case string:
-		return x
-
-	case float64:
-		return fmt.Sprintf("%g", x)
-
-	// TODO: add other types
-
-	default:
-		log.Warnf("WS: unable to convert %v to string", v)
-		return ""
+		service.logger.Warnf("WS: unexpected action received: %s, ignored", core.RedactJSON(body))
 	}
 }
@@ -1,58 +1,125 @@
 package ws
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/devicehive/devicehive-go/devicehive/core"
-	"strings"
+	"github.com/devicehive/devicehive-go/devicehive/log"
+	"sync"
+	"time"
+)
+
+const (
+	// Default maximum number of pending requests.
+	DefaultMaxPending = 1024
+)
+
+var (
+	// Error returned when the maximum number of pending requests is reached.
+	ErrTooManyPending = errors.New("too many pending requests")
+
+	// Error of canceled asynchronous request.
+	ErrCanceled = errors.New("canceled")
 )
 
 type Task struct {
-	id         uint64
-	dataToSend map[string]interface{}
-	dataRecved map[string]interface{}
-	done       chan *Task
+	id       uint64
+	request  interface{} // typed request envelope
+	response []byte      // raw response message
+	err      error       // connection error, no response
+	done     chan *Task
+	complete func(task *Task) // called by RX thread instead of done, if set
+	started  time.Time        // when task is created
 }
 
-// prepare device-key authorization
-func (task *Task) prepareAuthorization(device *core.Device) {
-	if device != nil {
-		// DeviceId [optional]
-		if len(device.Id) != 0 {
-			task.dataToSend["deviceId"] = device.Id
-		}
-
-		// DeviceKey [optional]
-		if len(device.Key) != 0 {
-			task.dataToSend["deviceKey"] = device.Key
-		}
+// get structured logging fields of the task
+func (task *Task) logFields() []log.Field {
+	fields := []log.Field{log.RequestId(task.id)}
+	if r, ok := task.request.(interface{ header() *requestHeader }); ok {
+		fields = append(fields, log.Action(r.header().Action))
+	}
+	if r, ok := task.request.(interface{ auth() *deviceAuth }); ok && len(r.auth().DeviceId) != 0 {
+		fields = append(fields, log.DeviceId(r.auth().DeviceId))
 	}
+	return fields
+}
+
+// create a logger with task fields
+// should be called only if the message is really logged
+func (service *Service) taskLogger(task *Task) *log.Entry {
+	return service.logger.With(task.logFields()...)
+}
+
+// pool of buffers to format messages
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
 }
 
 // Format the JSON data
-func (task *Task) Format() (body []byte, err error) {
-	body, err = json.Marshal(task.dataToSend)
+// The buffer should be released with releaseBuffer() when done
+func (task *Task) Format() (buf *bytes.Buffer, err error) {
+	buf = bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	err = json.NewEncoder(buf).Encode(task.request)
+	if err != nil {
+		releaseBuffer(buf)
+		return nil, err
+	}
+
+	// remove trailing newline
+	buf.Truncate(buf.Len() - 1)
 	return
 }
 
+// put the buffer back to the pool
+func releaseBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= 64*1024 { // do not keep huge buffers
+		bufferPool.Put(buf)
+	}
+}
+
+// Decode the response into typed envelope
+func (task *Task) Decode(response interface{}) (err error) {
+	if task.err != nil {
+		return task.err
+	}
+	if len(task.response) == 0 {
+		return fmt.Errorf("no response")
+	}
+
+	return json.Unmarshal(task.response, response)
+}
+
 // Check "success" status
 func (task *Task) CheckStatus() (err error) {
-	status := safeString(task.dataRecved["status"])
-	if !strings.EqualFold(status, "success") {
-		err = fmt.Errorf("unexpected status: %q [%s %s]", status,
-			safeString(task.dataRecved["code"]),
-			safeString(task.dataRecved["error"]))
+	var status responseStatus
+	err = task.Decode(&status)
+	if err != nil {
+		return
 	}
-	return
+
+	return status.check()
 }
 
 // create new empty task and put to active set
-func (service *Service) newTask() (task *Task) {
-	task = new(Task)
-	task.done = make(chan *Task)
-
+// fails if there are too many pending tasks
+func (service *Service) newTask() (task *Task, err error) {
 	service.taskLock.Lock()
 	defer service.taskLock.Unlock()
+
+	if service.err != nil {
+		return nil, service.err
+	}
+	if len(service.tasks) >= service.maxPending {
+		service.logger.Warnf("WS: %d requests are pending, new request rejected", len(service.tasks))
+		return nil, ErrTooManyPending
+	}
+
+	task = new(Task)
+	task.done = make(chan *Task, 1) // late response should not block RX thread
+	task.started = time.Now()
 	service.lastTaskId += 1 // generate unique identifier
 	task.id = service.lastTaskId
 	service.tasks[task.id] = task // put to "active" set
@@ -72,3 +139,38 @@ func (service *Service) takeTask(id uint64) (task *Task) {
 
 	return
 }
+
+// remove abandoned task from active list
+// return false if the response is already received
+func (service *Service) cancelTask(task *Task) bool {
+	return service.takeTask(task.id) != nil
+}
+
+// check the identifier was issued by newTask()
+func (service *Service) isIssuedTask(id uint64) bool {
+	service.taskLock.Lock()
+	defer service.taskLock.Unlock()
+
+	return id != 0 && id <= service.lastTaskId
+}
+
+// Pending() gets the number of requests waiting for response.
+func (service *Service) Pending() int {
+	service.taskLock.Lock()
+	defer service.taskLock.Unlock()
+
+	return len(service.tasks)
+}
+
+// SetMaxPending changes the maximum number of requests waiting for response.
+// New requests fail with ErrTooManyPending if the limit is reached.
+// Zero means DefaultMaxPending.
+func (service *Service) SetMaxPending(n int) {
+	if n <= 0 {
+		n = DefaultMaxPending
+	}
+
+	service.taskLock.Lock()
+	defer service.taskLock.Unlock()
+	service.maxPending = n
+}